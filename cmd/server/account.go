@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"workout-api/internal/archive"
+	"workout-api/internal/models"
+	"workout-api/internal/services"
+)
+
+const accountUsage = "usage: server account export <email> [file] | import <email> <file>"
+
+// runAccount implements the `account` subcommand, which backs up and
+// restores a user's data. Without a file, export writes to stdout.
+func runAccount(ctx context.Context, userService *services.UserService, accountService *services.AccountService, args []string) error {
+	if len(args) < 2 {
+		return errors.New(accountUsage)
+	}
+	user, err := userService.GetUserByEmail(ctx, args[1])
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "export":
+		if len(args) > 3 {
+			return errors.New(accountUsage)
+		}
+		a, err := accountService.ExportAccount(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		if len(args) == 2 {
+			return archive.Write(os.Stdout, a)
+		}
+		return writeArchiveFile(args[2], a)
+	case "import":
+		if len(args) != 3 {
+			return errors.New(accountUsage)
+		}
+		f, err := os.Open(args[2])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		a, err := archive.Read(f)
+		if err != nil {
+			return err
+		}
+		report, err := accountService.ImportAccount(ctx, models.Viewer{UserID: user.ID, Role: user.Role}, a)
+		if err != nil {
+			return err
+		}
+		printAccountReport(os.Stdout, report)
+		return nil
+	default:
+		return fmt.Errorf("unknown account command %q\n%s", args[0], accountUsage)
+	}
+}
+
+func writeArchiveFile(path string, a archive.Archive) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := archive.Write(f, a); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func printAccountReport(w io.Writer, report models.AccountImportReport) {
+	created := 0
+	for _, e := range report.Exercises {
+		if e.Created {
+			created++
+		}
+	}
+	fmt.Fprintf(w, "restored archive v%d: %d workout(s) created, %d skipped, %d set(s), %d exercise(s) created\n",
+		report.FormatVersion, report.WorkoutsCreated, report.WorkoutsSkipped, report.SetsImported, created)
+	for _, s := range report.Skipped {
+		fmt.Fprintf(w, "workout %d set %d (%s): %s\n", s.Workout, s.Set, s.Exercise, s.Reason)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/catalog"
+	"workout-api/internal/models"
+	"workout-api/internal/services"
+)
+
+const catalogUsage = "usage: server catalog import [-dry-run] [file.json|file.csv]"
+
+// runCatalog implements the `catalog` subcommand. Without a file the
+// catalog bundled with the server is imported.
+func runCatalog(ctx context.Context, exerciseService *services.ExerciseService, args []string) error {
+	if len(args) == 0 || args[0] != "import" {
+		return errors.New(catalogUsage)
+	}
+
+	flags := flag.NewFlagSet("catalog import", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "validate the catalog without writing it")
+	if err := flags.Parse(args[1:]); err != nil {
+		return errors.New(catalogUsage)
+	}
+
+	var (
+		c   catalog.Catalog
+		err error
+	)
+	switch flags.NArg() {
+	case 0:
+		c, err = catalog.Default()
+	case 1:
+		c, err = readCatalogFile(flags.Arg(0))
+	default:
+		return errors.New(catalogUsage)
+	}
+	if err != nil {
+		return err
+	}
+
+	// The command line is trusted with admin rights
+	report, err := exerciseService.ImportCatalog(ctx, models.Viewer{Role: models.RoleAdmin}, c, *dryRun)
+	if err != nil {
+		return err
+	}
+	printCatalogReport(report)
+	if report.Failed > 0 {
+		return fmt.Errorf("%d catalog row(s) failed validation", report.Failed)
+	}
+	return nil
+}
+
+func readCatalogFile(path string) (catalog.Catalog, error) {
+	format, err := catalog.FormatFromName(path)
+	if err != nil {
+		return catalog.Catalog{}, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return catalog.Catalog{}, err
+	}
+	defer f.Close()
+	return catalog.Parse(format, f)
+}
+
+func printCatalogReport(report models.CatalogImportReport) {
+	verb := "imported"
+	if report.DryRun {
+		verb = "validated"
+	}
+	fmt.Printf("%s catalog %s: %d row(s), %d created, %d updated, %d failed\n",
+		verb, report.Version, report.Total, report.Created, report.Updated, report.Failed)
+	for _, rowErr := range report.Errors {
+		fmt.Printf("row %d (%s): %s\n", rowErr.Row, rowErr.Slug, describeFields(rowErr.Fields))
+	}
+}
+
+func describeFields(fields []apperrors.FieldError) string {
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	return strings.Join(parts, "; ")
+}
@@ -1,33 +1,167 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
-	"net/http"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"workout-api/internal/auth"
+	"workout-api/internal/config"
 	"workout-api/internal/database"
 	"workout-api/internal/handlers"
+	"workout-api/internal/middleware"
+	"workout-api/internal/migrate"
 	"workout-api/internal/repository"
 	"workout-api/internal/routes"
 	"workout-api/internal/services"
+	"workout-api/migrations"
+
+	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path to an optional YAML config file")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatal("Failed to load configuration:", err)
+	}
+	setupLogging(cfg.Log)
+
 	// Initialize database connection
-	db, err := database.NewConnection()
+	db, err := database.NewConnection(cfg.Database)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 	defer db.Close()
 
-	// Initialize repository, service, and handler
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		log.Fatal("Failed to load migrations:", err)
+	}
+
+	// `server migrate ...` manages the schema and exits without serving
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(context.Background(), migrator, flag.Args()[1:]); err != nil {
+			log.Fatal("Migration failed: ", err)
+		}
+		return
+	}
+
+	if cfg.Database.AutoMigrate {
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			log.Fatal("Failed to apply migrations:", err)
+		}
+		log.Printf("Applied %d migration(s)", len(applied))
+	}
+
+	repository.DefaultQueryTimeout = cfg.Database.QueryTimeout
+
+	// Initialize repositories, services, and handlers
 	userRepo := repository.NewUserRepository(db)
-	userService := services.NewUserService(userRepo)
+	userService := services.NewUserService(userRepo, services.NewPasswordHasher(cfg.Auth.BcryptCost))
 	userHandler := handlers.NewUserHandler(userService)
 
+	// `server user ...` manages accounts and exits without serving
+	if flag.Arg(0) == "user" {
+		if err := runUser(context.Background(), userService, flag.Args()[1:]); err != nil {
+			log.Fatal("User command failed: ", err)
+		}
+		return
+	}
+
+	tokenManager := auth.NewTokenManager(cfg.Auth.JWTSecret, cfg.Auth.AccessTokenTTL)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	authService := services.NewAuthService(userService, tokenManager, refreshTokenRepo, cfg.Auth.RefreshTokenTTL)
+	authHandler := handlers.NewAuthHandler(authService)
+
+	taxonomyRepo := repository.NewTaxonomyRepository(db)
+	taxonomyService := services.NewTaxonomyService(taxonomyRepo)
+	taxonomyHandler := handlers.NewTaxonomyHandler(taxonomyService)
+
+	exerciseRepo := repository.NewExerciseRepository(db)
+	exerciseService := services.NewExerciseService(exerciseRepo, taxonomyRepo)
+	exerciseHandler := handlers.NewExerciseHandler(exerciseService)
+
+	// `server catalog import ...` loads the exercise catalog and exits
+	if flag.Arg(0) == "catalog" {
+		if err := runCatalog(context.Background(), exerciseService, flag.Args()[1:]); err != nil {
+			log.Fatal("Catalog import failed: ", err)
+		}
+		return
+	}
+
+	recordRepo := repository.NewPersonalRecordRepository(db)
+	recordService := services.NewRecordService(recordRepo, exerciseRepo)
+	recordHandler := handlers.NewRecordHandler(recordService)
+
+	workoutRepo := repository.NewWorkoutRepository(db)
+	workoutService := services.NewWorkoutService(workoutRepo, userRepo, exerciseRepo, recordRepo)
+	workoutHandler := handlers.NewWorkoutHandler(workoutService)
+
+	historyService := services.NewHistoryService(workoutRepo, exerciseRepo, exerciseService)
+	historyHandler := handlers.NewHistoryHandler(historyService)
+
+	accountService := services.NewAccountService(userRepo, exerciseRepo, workoutRepo, exerciseService)
+	accountHandler := handlers.NewAccountHandler(accountService)
+
+	// `server account ...` exports or restores a user's data and exits
+	if flag.Arg(0) == "account" {
+		if err := runAccount(context.Background(), userService, accountService, flag.Args()[1:]); err != nil {
+			log.Fatal("Account command failed: ", err)
+		}
+		return
+	}
+
+	activityRepo := repository.NewActivityRepository(db)
+	activityService := services.NewActivityService(activityRepo, exerciseRepo, recordRepo)
+	activityHandler := handlers.NewActivityHandler(activityService)
+
+	routineRepo := repository.NewRoutineRepository(db)
+	routineService := services.NewRoutineService(routineRepo, exerciseRepo, workoutRepo)
+	routineHandler := handlers.NewRoutineHandler(routineService)
+
+	programRepo := repository.NewProgramRepository(db)
+	enrollmentRepo := repository.NewEnrollmentRepository(db)
+	programService := services.NewProgramService(programRepo, enrollmentRepo, routineRepo, exerciseRepo, workoutRepo)
+	programHandler := handlers.NewProgramHandler(programService)
+
+	analyticsRepo := repository.NewAnalyticsRepository(db)
+	analyticsService := services.NewAnalyticsService(analyticsRepo, exerciseRepo)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+
+	healthHandler := handlers.NewHealthHandler(db, migrator)
+
 	// Setup router with handlers
-	r := routes.SetupRouter(userHandler)
+	r := routes.SetupRouter(middleware.RequireAuth(tokenManager), healthHandler, authHandler, userHandler, exerciseHandler, taxonomyHandler, workoutHandler, routineHandler, programHandler, analyticsHandler, recordHandler, historyHandler, activityHandler, accountHandler)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := serve(ctx, newHTTPServer(cfg.Server, r), healthHandler, cfg.Server)
+
+	// Close the pool explicitly; log.Fatal below would skip deferred calls
+	if err := db.Close(); err != nil {
+		log.Println("Error closing database:", err)
+	}
+	if serveErr != nil {
+		log.Fatal("Server error: ", serveErr)
+	}
+}
+
+// setupLogging routes the standard logger through slog at the configured
+// level and keeps gin's debug output for debug level only
+func setupLogging(cfg config.LogConfig) {
+	level, _ := cfg.SlogLevel()
+	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})
+	slog.SetDefault(slog.New(handler))
 
-	log.Println("Starting server on 8081")
-	if err := http.ListenAndServe(":8081", r); err != nil {
-		log.Fatal("Error starting server:", err)
+	if level > slog.LevelDebug {
+		gin.SetMode(gin.ReleaseMode)
 	}
 }
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"workout-api/internal/migrate"
+)
+
+const migrateUsage = "usage: server migrate up | down [steps] | to <version> | status"
+
+// runMigrate implements the `migrate` subcommand
+func runMigrate(ctx context.Context, migrator *migrate.Migrator, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		printMigrations("applied", applied)
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid step count %q", args[1])
+			}
+			steps = n
+		}
+		rolledBack, err := migrator.Down(ctx, steps)
+		printMigrations("rolled back", rolledBack)
+		return err
+	case "to":
+		if len(args) < 2 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		changed, err := migrator.To(ctx, version)
+		printMigrations("migrated", changed)
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range statuses {
+			state, appliedAt := "pending", ""
+			if s.Applied {
+				state = "applied"
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if s.ChecksumMismatch {
+				state = "applied (checksum mismatch)"
+			}
+			fmt.Fprintf(w, "%03d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", args[0], migrateUsage)
+	}
+}
+
+func printMigrations(verb string, migrations []migrate.Migration) {
+	if len(migrations) == 0 {
+		fmt.Println("no migrations", verb)
+		return
+	}
+	for _, m := range migrations {
+		fmt.Printf("%s %03d_%s\n", verb, m.Version, m.Name)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+	"workout-api/internal/config"
+	"workout-api/internal/handlers"
+)
+
+// newHTTPServer applies the configured timeouts so slow or idle clients
+// cannot hold connections open indefinitely
+func newHTTPServer(cfg config.ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+}
+
+// serve runs srv until ctx is cancelled, then fails readiness, waits out the
+// drain delay and gives in-flight requests up to ShutdownTimeout to finish
+func serve(ctx context.Context, srv *http.Server, health *handlers.HealthHandler, cfg config.ServerConfig) error {
+	errCh := make(chan error, 1)
+	go func() {
+		log.Println("Starting server on", srv.Addr)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		// The listener failed before any shutdown was requested
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Println("Shutdown requested, draining connections")
+	health.SetReady(false)
+	if cfg.DrainDelay > 0 {
+		time.Sleep(cfg.DrainDelay)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		// Deadline passed with requests still running; cut them off
+		_ = srv.Close()
+		return err
+	}
+
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	log.Println("Server stopped")
+	return nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"workout-api/internal/services"
+)
+
+const userUsage = "usage: server user role <email> <user|admin>"
+
+// runUser implements the `user` subcommand, used to bootstrap admins
+func runUser(ctx context.Context, userService *services.UserService, args []string) error {
+	if len(args) == 0 {
+		return errors.New(userUsage)
+	}
+
+	switch args[0] {
+	case "role":
+		if len(args) != 3 {
+			return errors.New(userUsage)
+		}
+		user, err := userService.SetUserRole(ctx, args[1], args[2])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("user %d (%s) is now %s\n", user.ID, user.Email, user.Role)
+		return nil
+	default:
+		return fmt.Errorf("unknown user command %q\n%s", args[0], userUsage)
+	}
+}
@@ -0,0 +1,179 @@
+// Package activity reads the GPX, TCX and FIT files sport watches and
+// bike computers export, and derives an activity's summary metrics and
+// splits from its track points. Everything is parsed locally; FIT files are
+// read by a small decoder covering the messages activities need.
+package activity
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+)
+
+// Sports an activity may be tagged with. Files that name no sport, or one
+// not listed here, are "other".
+const (
+	SportRunning = "running"
+	SportCycling = "cycling"
+	SportWalking = "walking"
+	SportHiking  = "hiking"
+	SportRowing  = "rowing"
+	SportOther   = "other"
+)
+
+// Bounds on what a track may hold. Longer names are cut short, and samples
+// outside the plausible range or the range their columns can store are
+// treated as missing; distance and elevation bounds are exclusive.
+const (
+	maxNameLength = 255
+	minHeartRate  = 20
+	maxHeartRate  = 250
+	maxSample     = math.MaxInt16
+	maxDistance   = 1e8
+	maxElevation  = 1e6
+)
+
+// Track is the content of an activity file. Calories is set when the
+// file records a total.
+type Track struct {
+	Format   string
+	Sport    string
+	Name     string
+	Calories *int
+	Points   []models.ActivityPoint
+}
+
+// Detect tells the format of an activity file from its content
+func Detect(data []byte) (string, error) {
+	if len(data) >= 12 && string(data[8:12]) == ".FIT" {
+		return models.ActivityFIT, nil
+	}
+	head := data[:min(len(data), 1024)]
+	switch {
+	case bytes.Contains(head, []byte("<gpx")):
+		return models.ActivityGPX, nil
+	case bytes.Contains(head, []byte("<TrainingCenterDatabase")):
+		return models.ActivityTCX, nil
+	}
+	return "", apperrors.Invalid("file", "unrecognised activity file, expected GPX, TCX or FIT")
+}
+
+// Parse reads an activity file in the given format. Track points without
+// a time are dropped and the rest ordered by time; a track needs at least
+// two to describe an activity. Out of range samples are dropped too.
+func Parse(format string, data []byte) (Track, error) {
+	var (
+		track Track
+		err   error
+	)
+	switch format {
+	case models.ActivityGPX:
+		track, err = parseGPX(data)
+	case models.ActivityTCX:
+		track, err = parseTCX(data)
+	case models.ActivityFIT:
+		track, err = parseFIT(data)
+	default:
+		return Track{}, apperrors.Invalid("format", fmt.Sprintf("unsupported activity format %q", format))
+	}
+	if err != nil {
+		return Track{}, err
+	}
+
+	points := track.Points[:0]
+	for _, p := range track.Points {
+		if !p.RecordedAt.IsZero() {
+			points = append(points, clampPoint(p))
+		}
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].RecordedAt.Before(points[j].RecordedAt) })
+	if len(points) < 2 {
+		return Track{}, apperrors.Invalid("file", "activity has fewer than two timed track points")
+	}
+	for i := range points {
+		points[i].Seq = i
+	}
+	track.Points = points
+	track.Format = format
+	track.Name = strings.TrimSpace(track.Name)
+	if utf8.RuneCountInString(track.Name) > maxNameLength {
+		track.Name = string([]rune(track.Name)[:maxNameLength])
+	}
+	return track, nil
+}
+
+// MaxStoredPoints bounds the track points kept for an activity so that
+// storing a long recording fits within one query timeout. Summaries are
+// computed from the full track before it is thinned.
+const MaxStoredPoints = 10000
+
+// Downsample returns at most n points spread evenly over the track, always
+// keeping the first and last, numbered from zero again
+func Downsample(points []models.ActivityPoint, n int) []models.ActivityPoint {
+	if len(points) <= n || n < 2 {
+		return points
+	}
+	kept := make([]models.ActivityPoint, n)
+	for i := range kept {
+		kept[i] = points[i*(len(points)-1)/(n-1)]
+		kept[i].Seq = i
+	}
+	return kept
+}
+
+// clampPoint drops the samples of p that fall outside the track bounds
+func clampPoint(p models.ActivityPoint) models.ActivityPoint {
+	p.HeartRate = intWithin(p.HeartRate, minHeartRate, maxHeartRate)
+	p.Cadence = intWithin(p.Cadence, 0, maxSample)
+	p.PowerWatts = intWithin(p.PowerWatts, 0, maxSample)
+	if p.Elevation != nil && math.Abs(*p.Elevation) >= maxElevation {
+		p.Elevation = nil
+	}
+	if p.DistanceMeters != nil && (*p.DistanceMeters < 0 || *p.DistanceMeters >= maxDistance) {
+		p.DistanceMeters = nil
+	}
+	return p
+}
+
+func intWithin(v *int, low, high int) *int {
+	if v == nil || *v < low || *v > high {
+		return nil
+	}
+	return v
+}
+
+// sportFromName maps the sport names used by GPX and TCX files
+func sportFromName(name string) string {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "running", "run", "trail_running", "treadmill_running":
+		return SportRunning
+	case "biking", "cycling", "bike", "ride", "road_biking", "mountain_biking":
+		return SportCycling
+	case "walking", "walk":
+		return SportWalking
+	case "hiking", "hike":
+		return SportHiking
+	case "rowing", "row":
+		return SportRowing
+	}
+	return SportOther
+}
+
+// parseTime reads the ISO 8601 timestamps used by GPX and TCX
+func parseTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}
+	}
+	return t.UTC()
+}
+
+func floatPtr(f float64) *float64 { return &f }
+
+func intPtr(i int) *int { return &i }
@@ -0,0 +1,240 @@
+package activity
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleGPX = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="watch" xmlns="http://www.topografix.com/GPX/1/1"
+	xmlns:gpxtpx="http://www.garmin.com/xmlschemas/TrackPointExtension/v1">
+	<metadata><name>Morning Run</name></metadata>
+	<trk>
+		<type>running</type>
+		<trkseg>
+			<trkpt lat="51.5000" lon="-0.1200"><ele>10</ele><time>2026-03-02T07:00:00Z</time>
+				<extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>140</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions></trkpt>
+			<trkpt lat="51.5045" lon="-0.1200"><ele>15</ele><time>2026-03-02T07:02:30Z</time>
+				<extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>150</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions></trkpt>
+			<trkpt lat="51.5090" lon="-0.1200"><ele>14</ele><time>2026-03-02T07:05:00Z</time>
+				<extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>160</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions></trkpt>
+			<trkpt lat="51.5100" lon="-0.1200"><time></time></trkpt>
+		</trkseg>
+	</trk>
+</gpx>`
+
+func TestParseGPX(t *testing.T) {
+	format, err := Detect([]byte(sampleGPX))
+	assert.NoError(t, err)
+	assert.Equal(t, models.ActivityGPX, format)
+
+	track, err := Parse(format, []byte(sampleGPX))
+	assert.NoError(t, err)
+	assert.Equal(t, "Morning Run", track.Name)
+	assert.Equal(t, SportRunning, track.Sport)
+	// The untimed point is dropped
+	assert.Len(t, track.Points, 3)
+	assert.Equal(t, 150, *track.Points[1].HeartRate)
+	assert.Equal(t, 15.0, *track.Points[1].Elevation)
+
+	a := Summarize(track)
+	assert.Equal(t, time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC), a.StartedAt)
+	assert.Equal(t, 300, a.DurationSeconds)
+	assert.InDelta(t, 1000.8, a.DistanceMeters, 1)
+	assert.Equal(t, 5.0, *a.ElevationGain)
+	assert.Equal(t, 150, *a.AvgHeartRate)
+	assert.Equal(t, 160, *a.MaxHeartRate)
+	assert.Nil(t, a.AvgPowerWatts)
+	if assert.Len(t, a.Splits, 2) {
+		assert.Equal(t, 1000.0, a.Splits[0].DistanceMeters)
+		assert.InDelta(t, 300, a.Splits[0].PaceSecondsPerKm, 1)
+		assert.Equal(t, 2, a.Splits[1].Number)
+	}
+}
+
+const sampleTCX = `<?xml version="1.0" encoding="UTF-8"?>
+<TrainingCenterDatabase xmlns="http://www.garmin.com/xmlschemas/TrainingCenterDatabase/v2">
+	<Activities>
+		<Activity Sport="Biking">
+			<Id>2026-03-02T07:00:00Z</Id>
+			<Lap StartTime="2026-03-02T07:00:00Z">
+				<Calories>150</Calories>
+				<Track>
+					<Trackpoint><Time>2026-03-02T07:00:00Z</Time><DistanceMeters>0</DistanceMeters><HeartRateBpm><Value>120</Value></HeartRateBpm>
+						<Extensions><ns3:TPX xmlns:ns3="http://www.garmin.com/xmlschemas/ActivityExtension/v2"><ns3:Watts>200</ns3:Watts></ns3:TPX></Extensions></Trackpoint>
+					<Trackpoint><Time>2026-03-02T07:05:00Z</Time><DistanceMeters>2500</DistanceMeters><HeartRateBpm><Value>140</Value></HeartRateBpm>
+						<Extensions><ns3:TPX xmlns:ns3="http://www.garmin.com/xmlschemas/ActivityExtension/v2"><ns3:Watts>220</ns3:Watts></ns3:TPX></Extensions></Trackpoint>
+				</Track>
+			</Lap>
+			<Lap StartTime="2026-03-02T07:05:00Z">
+				<Calories>50</Calories>
+				<Track>
+					<Trackpoint><Time>2026-03-02T07:10:00Z</Time><DistanceMeters>5000</DistanceMeters></Trackpoint>
+				</Track>
+			</Lap>
+		</Activity>
+	</Activities>
+</TrainingCenterDatabase>`
+
+func TestParse_DropsOutOfRangeSamples(t *testing.T) {
+	name := strings.Repeat("é", 300)
+	gpx := `<gpx><metadata><name>` + name + `</name></metadata><trk><trkseg>
+		<trkpt lat="51.5" lon="-0.12"><ele>1e7</ele><time>2026-03-02T07:00:00Z</time>
+			<extensions><TrackPointExtension><hr>0</hr><cad>90</cad></TrackPointExtension><power>65534</power></extensions></trkpt>
+		<trkpt lat="51.5045" lon="-0.12"><ele>15</ele><time>2026-03-02T07:02:30Z</time>
+			<extensions><TrackPointExtension><hr>150</hr><cad>-1</cad></TrackPointExtension><power>250</power></extensions></trkpt>
+	</trkseg></trk></gpx>`
+
+	track, err := Parse(models.ActivityGPX, []byte(gpx))
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat("é", 255), track.Name)
+	first, second := track.Points[0], track.Points[1]
+	assert.Nil(t, first.Elevation)
+	assert.Nil(t, first.HeartRate)
+	assert.Nil(t, first.PowerWatts)
+	assert.Equal(t, 90, *first.Cadence)
+	assert.Equal(t, 150, *second.HeartRate)
+	assert.Nil(t, second.Cadence)
+	assert.Equal(t, 250, *second.PowerWatts)
+}
+
+func TestDownsample(t *testing.T) {
+	start := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+	points := make([]models.ActivityPoint, 10)
+	for i := range points {
+		points[i] = models.ActivityPoint{Seq: i, RecordedAt: start.Add(time.Duration(i) * time.Second)}
+	}
+
+	assert.Len(t, Downsample(points, 20), 10)
+	kept := Downsample(points, 4)
+	if assert.Len(t, kept, 4) {
+		assert.Equal(t, []time.Time{start, start.Add(3 * time.Second), start.Add(6 * time.Second), start.Add(9 * time.Second)},
+			[]time.Time{kept[0].RecordedAt, kept[1].RecordedAt, kept[2].RecordedAt, kept[3].RecordedAt})
+		assert.Equal(t, 3, kept[3].Seq)
+	}
+}
+
+func TestParseTCX(t *testing.T) {
+	format, err := Detect([]byte(sampleTCX))
+	assert.NoError(t, err)
+	assert.Equal(t, models.ActivityTCX, format)
+
+	track, err := Parse(format, []byte(sampleTCX))
+	assert.NoError(t, err)
+	assert.Equal(t, SportCycling, track.Sport)
+	assert.Equal(t, 200, *track.Calories)
+	assert.Len(t, track.Points, 3)
+	assert.Equal(t, 200, *track.Points[0].PowerWatts)
+
+	a := Summarize(track)
+	assert.Equal(t, 5000.0, a.DistanceMeters)
+	assert.Equal(t, 600, a.DurationSeconds)
+	assert.Equal(t, 210, *a.AvgPowerWatts)
+	assert.Nil(t, a.ElevationGain)
+	if assert.Len(t, a.Splits, 5) {
+		// Device distances are trusted and boundaries interpolated in time
+		assert.Equal(t, 120.0, a.Splits[0].DurationSeconds)
+		assert.Equal(t, 120.0, a.Splits[4].DurationSeconds)
+	}
+}
+
+// fitFile assembles a FIT file from record bytes, adding the header and CRC
+func fitFile(records ...[]byte) []byte {
+	body := bytes.Join(records, nil)
+	header := []byte{12, 0x20, 0, 0, 0, 0, 0, 0, '.', 'F', 'I', 'T'}
+	binary.LittleEndian.PutUint32(header[4:8], uint32(len(body)))
+	data := append(header, body...)
+	return binary.LittleEndian.AppendUint16(data, fitCRC(data))
+}
+
+func fitRecord(local byte, timestamp uint32, lat, lon int32, altitude uint16, distance uint32, hr byte) []byte {
+	b := []byte{local}
+	b = binary.LittleEndian.AppendUint32(b, timestamp)
+	b = binary.LittleEndian.AppendUint32(b, uint32(lat))
+	b = binary.LittleEndian.AppendUint32(b, uint32(lon))
+	b = binary.LittleEndian.AppendUint16(b, altitude)
+	b = binary.LittleEndian.AppendUint32(b, distance)
+	return append(b, hr)
+}
+
+func TestParseFIT(t *testing.T) {
+	recordDef := []byte{0x40, 0, 0, fitMessageRecord, 0, 6,
+		fitFieldTimestamp, 4, 0x86, fitRecordLatitude, 4, 0x85, fitRecordLongitude, 4, 0x85,
+		fitRecordAltitude, 2, 0x84, fitRecordDistance, 4, 0x86, fitRecordHeartRate, 1, 0x02}
+	// A session in big-endian layout with a developer field to skip
+	sessionDef := []byte{0x61, 0, 1, 0, fitMessageSession, 2,
+		fitSessionSport, 1, 0x00, fitSessionTotalCalories, 2, 0x84, 1, 0, 2, 0}
+	start := uint32(1141369200)     // 2026-03-02T07:00:00Z
+	semicircles := int32(614418933) // 51.5 degrees
+
+	// A compressed timestamp header with an invalid heart rate
+	compressedDef := []byte{0x42, 0, 0, fitMessageRecord, 0, 2, fitRecordDistance, 4, 0x86, fitRecordHeartRate, 1, 0x02}
+	compressed := []byte{0x80 | 2<<5 | byte((start+20)&0x1F)}
+	compressed = binary.LittleEndian.AppendUint32(compressed, 150000)
+	compressed = append(compressed, 0xFF)
+
+	data := fitFile(
+		recordDef,
+		fitRecord(0, start, semicircles, 0, (100+500)*5, 0, 130),
+		fitRecord(0, start+10, semicircles, 0, (110+500)*5, 100000, 150),
+		compressedDef,
+		compressed,
+		sessionDef,
+		[]byte{0x01, 1, 0x01, 0x2C, 7, 7},
+	)
+
+	format, err := Detect(data)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ActivityFIT, format)
+
+	track, err := Parse(format, data)
+	assert.NoError(t, err)
+	assert.Equal(t, SportRunning, track.Sport)
+	assert.Equal(t, 300, *track.Calories)
+	if assert.Len(t, track.Points, 3) {
+		first := track.Points[0]
+		assert.Equal(t, time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC), first.RecordedAt)
+		assert.InDelta(t, 51.5, *first.Latitude, 1e-6)
+		assert.Equal(t, 100.0, *first.Elevation)
+		assert.Equal(t, 1000.0, *track.Points[1].DistanceMeters)
+
+		last := track.Points[2]
+		assert.Equal(t, time.Date(2026, 3, 2, 7, 0, 20, 0, time.UTC), last.RecordedAt)
+		assert.Equal(t, 1500.0, *last.DistanceMeters)
+		assert.Nil(t, last.HeartRate)
+		assert.Nil(t, last.Latitude)
+	}
+}
+
+func TestParseFIT_Corrupt(t *testing.T) {
+	data := fitFile([]byte{0x40, 0, 0, fitMessageRecord, 0, 0})
+	data[len(data)-1] ^= 0xFF
+	_, err := Parse(models.ActivityFIT, data)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	_, err = Parse(models.ActivityFIT, fitFile([]byte{0x00}))
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	_, err = Parse(models.ActivityFIT, data[:10])
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
+
+func TestDetect_Unknown(t *testing.T) {
+	_, err := Detect([]byte("Date,Workout Name\n"))
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+}
+
+func TestElevationGain_IgnoresNoise(t *testing.T) {
+	var g elevationGain
+	for _, e := range []float64{100, 101, 100, 102, 101, 104, 103, 110, 108, 109} {
+		g.add(&e)
+	}
+	assert.Equal(t, 10.0, g.total)
+}
@@ -0,0 +1,279 @@
+package activity
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+)
+
+// FIT global message numbers and the fields read from them, from the FIT
+// SDK profile
+const (
+	fitMessageSession = 18
+	fitMessageRecord  = 20
+
+	fitFieldTimestamp = 253
+
+	fitRecordLatitude         = 0
+	fitRecordLongitude        = 1
+	fitRecordAltitude         = 2
+	fitRecordHeartRate        = 3
+	fitRecordCadence          = 4
+	fitRecordDistance         = 5
+	fitRecordPower            = 7
+	fitRecordEnhancedAltitude = 78
+
+	fitSessionSport         = 5
+	fitSessionTotalCalories = 11
+)
+
+// fitEpoch is the zero of FIT timestamps, 1989-12-31T00:00:00Z
+var fitEpoch = time.Date(1989, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// fitSports maps the FIT sport enum
+var fitSports = map[uint64]string{1: SportRunning, 2: SportCycling, 11: SportWalking, 15: SportRowing, 17: SportHiking}
+
+type fitField struct {
+	num  byte
+	size int
+}
+
+// fitDefinition describes the layout of the data messages that use a
+// local message type
+type fitDefinition struct {
+	global    uint16
+	bigEndian bool
+	fields    []fitField
+	// devSize is the total size of developer fields, which are skipped
+	devSize int
+}
+
+// fitMessage holds a decoded data message's integer fields by number.
+// Fields holding the invalid value for their size are left out.
+type fitMessage map[byte]uint64
+
+// parseFIT decodes the record messages of a FIT activity file into track
+// points, and the sport and calories of its session
+func parseFIT(data []byte) (Track, error) {
+	if len(data) < 12 {
+		return Track{}, apperrors.Invalid("file", "FIT file is truncated")
+	}
+	headerSize := int(data[0])
+	end := headerSize + int(binary.LittleEndian.Uint32(data[4:8]))
+	if headerSize < 12 || end+2 > len(data) {
+		return Track{}, apperrors.Invalid("file", "FIT file is truncated")
+	}
+	if fitCRC(data[:end]) != binary.LittleEndian.Uint16(data[end:end+2]) {
+		return Track{}, apperrors.Invalid("file", "FIT file failed its CRC check")
+	}
+
+	track := Track{Sport: SportOther}
+	definitions := map[byte]*fitDefinition{}
+	var lastTimestamp uint32
+	r := fitReader{data: data[:end], pos: headerSize}
+
+	for r.pos < end {
+		header, err := r.take(1)
+		if err != nil {
+			return Track{}, err
+		}
+		h := header[0]
+
+		var local byte
+		var compressedTime *uint32
+		switch {
+		case h&0x80 != 0:
+			// Compressed timestamp header: a data message whose time is an
+			// offset of up to 31 seconds from the last full timestamp
+			local = (h >> 5) & 0x03
+			offset := uint32(h & 0x1F)
+			ts := lastTimestamp&^0x1F + offset
+			if offset < lastTimestamp&0x1F {
+				ts += 0x20
+			}
+			compressedTime = &ts
+		case h&0x40 != 0:
+			def, err := r.definition(h&0x20 != 0)
+			if err != nil {
+				return Track{}, err
+			}
+			definitions[h&0x0F] = def
+			continue
+		default:
+			local = h & 0x0F
+		}
+
+		def, ok := definitions[local]
+		if !ok {
+			return Track{}, apperrors.Invalid("file", fmt.Sprintf("FIT data message uses undefined local type %d", local))
+		}
+		msg, err := r.message(def)
+		if err != nil {
+			return Track{}, err
+		}
+		if ts, ok := msg[fitFieldTimestamp]; ok {
+			lastTimestamp = uint32(ts)
+		} else if compressedTime != nil {
+			msg[fitFieldTimestamp] = uint64(*compressedTime)
+			lastTimestamp = *compressedTime
+		}
+
+		switch def.global {
+		case fitMessageRecord:
+			track.Points = append(track.Points, fitPoint(msg))
+		case fitMessageSession:
+			if sport, ok := fitSports[msg[fitSessionSport]]; ok {
+				track.Sport = sport
+			}
+			if calories, ok := msg[fitSessionTotalCalories]; ok {
+				track.Calories = intPtr(int(calories) + valueOr(track.Calories))
+			}
+		}
+	}
+	return track, nil
+}
+
+func fitPoint(msg fitMessage) models.ActivityPoint {
+	var p models.ActivityPoint
+	if ts, ok := msg[fitFieldTimestamp]; ok {
+		p.RecordedAt = fitEpoch.Add(time.Duration(ts) * time.Second)
+	}
+	lat, latOK := msg[fitRecordLatitude]
+	lon, lonOK := msg[fitRecordLongitude]
+	if latOK && lonOK {
+		p.Latitude = floatPtr(semicirclesToDegrees(lat))
+		p.Longitude = floatPtr(semicirclesToDegrees(lon))
+	}
+	if alt, ok := msg[fitRecordEnhancedAltitude]; ok {
+		p.Elevation = floatPtr(float64(alt)/5 - 500)
+	} else if alt, ok := msg[fitRecordAltitude]; ok {
+		p.Elevation = floatPtr(float64(alt)/5 - 500)
+	}
+	if d, ok := msg[fitRecordDistance]; ok {
+		p.DistanceMeters = floatPtr(float64(d) / 100)
+	}
+	if hr, ok := msg[fitRecordHeartRate]; ok {
+		p.HeartRate = intPtr(int(hr))
+	}
+	if cad, ok := msg[fitRecordCadence]; ok {
+		p.Cadence = intPtr(int(cad))
+	}
+	if power, ok := msg[fitRecordPower]; ok {
+		p.PowerWatts = intPtr(int(power))
+	}
+	return p
+}
+
+// semicirclesToDegrees converts FIT's signed 32-bit angles
+func semicirclesToDegrees(v uint64) float64 {
+	return float64(int32(uint32(v))) * 180 / math.Pow(2, 31)
+}
+
+type fitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *fitReader) take(n int) ([]byte, error) {
+	if r.pos+n > len(r.data) {
+		return nil, apperrors.Invalid("file", "FIT file is truncated")
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+// definition reads a definition message following its record header
+func (r *fitReader) definition(developer bool) (*fitDefinition, error) {
+	fixed, err := r.take(5)
+	if err != nil {
+		return nil, err
+	}
+	def := &fitDefinition{bigEndian: fixed[1] == 1}
+	if def.bigEndian {
+		def.global = binary.BigEndian.Uint16(fixed[2:4])
+	} else {
+		def.global = binary.LittleEndian.Uint16(fixed[2:4])
+	}
+
+	fields, err := r.take(3 * int(fixed[4]))
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(fields); i += 3 {
+		def.fields = append(def.fields, fitField{num: fields[i], size: int(fields[i+1])})
+	}
+
+	if developer {
+		count, err := r.take(1)
+		if err != nil {
+			return nil, err
+		}
+		devFields, err := r.take(3 * int(count[0]))
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < len(devFields); i += 3 {
+			def.devSize += int(devFields[i+1])
+		}
+	}
+	return def, nil
+}
+
+// message reads a data message laid out by def. Only single integers are
+// kept; arrays, strings and developer fields are skipped.
+func (r *fitReader) message(def *fitDefinition) (fitMessage, error) {
+	msg := fitMessage{}
+	for _, f := range def.fields {
+		b, err := r.take(f.size)
+		if err != nil {
+			return nil, err
+		}
+		var order binary.ByteOrder = binary.LittleEndian
+		if def.bigEndian {
+			order = binary.BigEndian
+		}
+		var v, invalid uint64
+		switch f.size {
+		case 1:
+			v, invalid = uint64(b[0]), math.MaxUint8
+		case 2:
+			v, invalid = uint64(order.Uint16(b)), math.MaxUint16
+		case 4:
+			v, invalid = uint64(order.Uint32(b)), math.MaxUint32
+		default:
+			continue
+		}
+		// Signed 32-bit fields mark missing values with their maximum
+		if v == invalid || (f.size == 4 && v == math.MaxInt32) {
+			continue
+		}
+		msg[f.num] = v
+	}
+	if _, err := r.take(def.devSize); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+var fitCRCTable = [16]uint16{
+	0x0000, 0xCC01, 0xD801, 0x1400, 0xF001, 0x3C00, 0x2800, 0xE401,
+	0xA001, 0x6C00, 0x7800, 0xB401, 0x5000, 0x9C01, 0x8801, 0x4400,
+}
+
+// fitCRC computes the CRC-16 that FIT files end with
+func fitCRC(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		tmp := fitCRCTable[crc&0xF]
+		crc = (crc >> 4) & 0x0FFF
+		crc = crc ^ tmp ^ fitCRCTable[b&0xF]
+		tmp = fitCRCTable[crc&0xF]
+		crc = (crc >> 4) & 0x0FFF
+		crc = crc ^ tmp ^ fitCRCTable[(b>>4)&0xF]
+	}
+	return crc
+}
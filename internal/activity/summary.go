@@ -0,0 +1,197 @@
+package activity
+
+import (
+	"math"
+	"time"
+	"workout-api/internal/models"
+)
+
+// splitMeters is the length of one split
+const splitMeters = 1000
+
+// elevationThreshold is the climb in meters that counts towards elevation
+// gain; smaller rises are treated as GPS and barometer noise
+const elevationThreshold = 3
+
+// earthRadius is the mean radius of the Earth in meters
+const earthRadius = 6371008.8
+
+// Summarize computes an activity's metrics from a parsed track. Points
+// without a device distance get the distance along their coordinates, so
+// every point leaves with its distance from the start when one is known.
+func Summarize(track Track) models.Activity {
+	points := track.Points
+	fillDistances(points)
+
+	first, last := points[0], points[len(points)-1]
+	a := models.Activity{
+		Format:          track.Format,
+		Sport:           track.Sport,
+		Name:            track.Name,
+		StartedAt:       first.RecordedAt,
+		DurationSeconds: int(math.Round(last.RecordedAt.Sub(first.RecordedAt).Seconds())),
+		Calories:        track.Calories,
+		PointCount:      len(points),
+	}
+	if last.DistanceMeters != nil {
+		a.DistanceMeters = round(*last.DistanceMeters)
+	}
+
+	var gain elevationGain
+	var hr, power stat
+	for _, p := range points {
+		gain.add(p.Elevation)
+		hr.add(p.HeartRate)
+		power.add(p.PowerWatts)
+	}
+	if gain.seen {
+		a.ElevationGain = floatPtr(round(gain.total))
+	}
+	a.AvgHeartRate, a.MaxHeartRate = hr.mean(), hr.maximum()
+	a.AvgPowerWatts = power.mean()
+	a.Splits = splits(points)
+	return a
+}
+
+// fillDistances sets the cumulative distance of points that lack one from
+// the distance between consecutive coordinates
+func fillDistances(points []models.ActivityPoint) {
+	var total float64
+	var prev *models.ActivityPoint
+	for i := range points {
+		p := &points[i]
+		if p.DistanceMeters != nil {
+			total = *p.DistanceMeters
+		} else if p.Latitude != nil && p.Longitude != nil {
+			if prev != nil {
+				total += haversine(*prev.Latitude, *prev.Longitude, *p.Latitude, *p.Longitude)
+			}
+			p.DistanceMeters = floatPtr(round(total))
+		}
+		if p.Latitude != nil && p.Longitude != nil {
+			prev = p
+		}
+	}
+}
+
+// haversine returns the great-circle distance in meters between two
+// coordinates in degrees
+func haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := math.Pi / 180
+	dLat := (lat2 - lat1) * toRad
+	dLon := (lon2 - lon1) * toRad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1*toRad)*math.Cos(lat2*toRad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+// splits cuts the track into whole kilometers plus the remainder, timing
+// each boundary by interpolating between the points either side of it
+func splits(points []models.ActivityPoint) []models.ActivitySplit {
+	result := []models.ActivitySplit{}
+	start := points[0]
+	if start.DistanceMeters == nil {
+		return result
+	}
+
+	startDistance, startTime := *start.DistanceMeters, start.RecordedAt
+	var gain elevationGain
+	var hr stat
+	gain.add(start.Elevation)
+	closeSplit := func(distance float64, seconds float64) {
+		split := models.ActivitySplit{
+			Number:          len(result) + 1,
+			DistanceMeters:  round(distance),
+			DurationSeconds: round(seconds),
+			ElevationGain:   round(gain.total),
+			AvgHeartRate:    hr.mean(),
+		}
+		if distance > 0 {
+			split.PaceSecondsPerKm = round(seconds / (distance / 1000))
+		}
+		result = append(result, split)
+		gain.total, hr = 0, stat{}
+	}
+
+	prev := start
+	for _, p := range points[1:] {
+		if p.DistanceMeters == nil {
+			continue
+		}
+		gain.add(p.Elevation)
+		hr.add(p.HeartRate)
+		// A point may cross several boundaries when the device paused
+		for *p.DistanceMeters >= startDistance+splitMeters {
+			boundary := startDistance + splitMeters
+			span := *p.DistanceMeters - *prev.DistanceMeters
+			fraction := 1.0
+			if span > 0 {
+				fraction = (boundary - *prev.DistanceMeters) / span
+			}
+			at := prev.RecordedAt.Add(time.Duration(float64(p.RecordedAt.Sub(prev.RecordedAt)) * fraction))
+			closeSplit(splitMeters, at.Sub(startTime).Seconds())
+			startDistance, startTime = boundary, at
+		}
+		prev = p
+	}
+	if rest := *prev.DistanceMeters - startDistance; rest > 0 {
+		closeSplit(rest, prev.RecordedAt.Sub(startTime).Seconds())
+	}
+	return result
+}
+
+// elevationGain adds up climbs with a dead band of elevationThreshold:
+// the reference elevation only moves once the track has risen or fallen
+// that far from it, so jitter around a level stretch adds nothing.
+type elevationGain struct {
+	total     float64
+	reference float64
+	seen      bool
+}
+
+func (g *elevationGain) add(elevation *float64) {
+	if elevation == nil {
+		return
+	}
+	e := *elevation
+	switch {
+	case !g.seen:
+		g.reference, g.seen = e, true
+	case e-g.reference >= elevationThreshold:
+		g.total += e - g.reference
+		g.reference = e
+	case g.reference-e >= elevationThreshold:
+		g.reference = e
+	}
+}
+
+// stat accumulates an optional integer measurement
+type stat struct {
+	sum, count, max int
+}
+
+func (s *stat) add(v *int) {
+	if v == nil || *v <= 0 {
+		return
+	}
+	s.sum += *v
+	s.count++
+	s.max = max(s.max, *v)
+}
+
+func (s stat) mean() *int {
+	if s.count == 0 {
+		return nil
+	}
+	return intPtr(int(math.Round(float64(s.sum) / float64(s.count))))
+}
+
+func (s stat) maximum() *int {
+	if s.count == 0 {
+		return nil
+	}
+	return intPtr(s.max)
+}
+
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
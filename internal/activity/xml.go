@@ -0,0 +1,124 @@
+package activity
+
+import (
+	"bytes"
+	"encoding/xml"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+)
+
+// GPX and TCX elements are matched by local name so files work whatever
+// namespace prefixes their extensions use
+
+type gpxFile struct {
+	Name   string `xml:"metadata>name"`
+	Tracks []struct {
+		Name     string `xml:"name"`
+		Type     string `xml:"type"`
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+type gpxPoint struct {
+	Lat       float64  `xml:"lat,attr"`
+	Lon       float64  `xml:"lon,attr"`
+	Elevation *float64 `xml:"ele"`
+	Time      string   `xml:"time"`
+	HeartRate *int     `xml:"extensions>TrackPointExtension>hr"`
+	Cadence   *int     `xml:"extensions>TrackPointExtension>cad"`
+	Power     *int     `xml:"extensions>power"`
+}
+
+// parseGPX reads every track of a GPX file as one activity
+func parseGPX(data []byte) (Track, error) {
+	var f gpxFile
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&f); err != nil {
+		return Track{}, apperrors.Invalid("file", "malformed GPX: "+err.Error())
+	}
+
+	track := Track{Name: f.Name, Sport: SportOther}
+	for _, trk := range f.Tracks {
+		if track.Name == "" {
+			track.Name = trk.Name
+		}
+		if track.Sport == SportOther {
+			track.Sport = sportFromName(trk.Type)
+		}
+		for _, seg := range trk.Segments {
+			for _, p := range seg.Points {
+				track.Points = append(track.Points, models.ActivityPoint{
+					RecordedAt: parseTime(p.Time),
+					Latitude:   floatPtr(p.Lat),
+					Longitude:  floatPtr(p.Lon),
+					Elevation:  p.Elevation,
+					HeartRate:  p.HeartRate,
+					Cadence:    p.Cadence,
+					PowerWatts: p.Power,
+				})
+			}
+		}
+	}
+	return track, nil
+}
+
+type tcxFile struct {
+	Activities []struct {
+		Sport string `xml:"Sport,attr"`
+		Notes string `xml:"Notes"`
+		Laps  []struct {
+			Calories *int `xml:"Calories"`
+			Points   []struct {
+				Time      string   `xml:"Time"`
+				Latitude  *float64 `xml:"Position>LatitudeDegrees"`
+				Longitude *float64 `xml:"Position>LongitudeDegrees"`
+				Altitude  *float64 `xml:"AltitudeMeters"`
+				Distance  *float64 `xml:"DistanceMeters"`
+				HeartRate *int     `xml:"HeartRateBpm>Value"`
+				Cadence   *int     `xml:"Cadence"`
+				Watts     *int     `xml:"Extensions>TPX>Watts"`
+			} `xml:"Track>Trackpoint"`
+		} `xml:"Lap"`
+	} `xml:"Activities>Activity"`
+}
+
+// parseTCX reads the first activity of a TCX file. Lap calories are added
+// up; TCX files have no activity name, so its notes stand in.
+func parseTCX(data []byte) (Track, error) {
+	var f tcxFile
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&f); err != nil {
+		return Track{}, apperrors.Invalid("file", "malformed TCX: "+err.Error())
+	}
+	if len(f.Activities) == 0 {
+		return Track{}, apperrors.Invalid("file", "TCX file has no activity")
+	}
+
+	a := f.Activities[0]
+	track := Track{Name: a.Notes, Sport: sportFromName(a.Sport)}
+	for _, lap := range a.Laps {
+		if lap.Calories != nil {
+			track.Calories = intPtr(*lap.Calories + valueOr(track.Calories))
+		}
+		for _, p := range lap.Points {
+			track.Points = append(track.Points, models.ActivityPoint{
+				RecordedAt:     parseTime(p.Time),
+				Latitude:       p.Latitude,
+				Longitude:      p.Longitude,
+				Elevation:      p.Altitude,
+				DistanceMeters: p.Distance,
+				HeartRate:      p.HeartRate,
+				Cadence:        p.Cadence,
+				PowerWatts:     p.Watts,
+			})
+		}
+	}
+	return track, nil
+}
+
+func valueOr(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
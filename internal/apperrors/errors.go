@@ -0,0 +1,88 @@
+// Package apperrors defines the domain errors shared by the repository,
+// service and handler layers. Handlers never choose status codes for these
+// themselves; the error middleware maps each kind to an HTTP status.
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Kinds of domain error. Match them with errors.Is.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+)
+
+// FieldError describes a problem with a single input field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is a domain error of a given kind with a client safe message
+type Error struct {
+	kind    error
+	message string
+	Fields  []FieldError
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+func (e *Error) Unwrap() error {
+	return e.kind
+}
+
+// Kind returns the sentinel this error belongs to
+func (e *Error) Kind() error {
+	return e.kind
+}
+
+func New(kind error, message string) *Error {
+	return &Error{kind: kind, message: message}
+}
+
+// NotFound reports a missing resource, e.g. NotFound("user", 42). A nil id
+// leaves the identifier out of the message.
+func NotFound(resource string, id any) *Error {
+	if id == nil {
+		return New(ErrNotFound, resource+" not found")
+	}
+	return New(ErrNotFound, fmt.Sprintf("%s %v not found", resource, id))
+}
+
+func Conflict(message string) *Error {
+	return New(ErrConflict, message)
+}
+
+func Unauthorized(message string) *Error {
+	return New(ErrUnauthorized, message)
+}
+
+func Forbidden(message string) *Error {
+	return New(ErrForbidden, message)
+}
+
+// Invalid reports a validation problem with a single field
+func Invalid(field, message string) *Error {
+	return Validation(FieldError{Field: field, Message: message})
+}
+
+// Validation reports one or more field problems at once
+func Validation(fields ...FieldError) *Error {
+	messages := make([]string, 0, len(fields))
+	for _, f := range fields {
+		messages = append(messages, f.Message)
+	}
+	message := ErrValidation.Error()
+	if len(messages) > 0 {
+		message = strings.Join(messages, "; ")
+	}
+	return &Error{kind: ErrValidation, message: message, Fields: fields}
+}
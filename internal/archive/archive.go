@@ -0,0 +1,219 @@
+// Package archive defines the versioned JSON document a user's account is
+// exported to and restored from. Records refer to each other by the IDs
+// they had when exported; those IDs mean nothing elsewhere and are
+// remapped on import.
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+)
+
+// FormatVersion is the archive layout this package writes and reads. It
+// changes only when fields are renamed or removed.
+const FormatVersion = 1
+
+// maxNameLength is the longest exercise or workout name that can be restored
+const maxNameLength = 255
+
+// Archive is everything a user owns that can move between accounts: their
+// profile, their own exercises along with the shared ones their sets use,
+// and their workouts with every logged set. Secrets such as the password
+// hash are never included. Routines, programs, personal records and
+// uploaded activities are not part of this version, and the API does not
+// record body measurements yet.
+type Archive struct {
+	FormatVersion int        `json:"format_version"`
+	ExportedAt    time.Time  `json:"exported_at"`
+	Profile       Profile    `json:"profile"`
+	Exercises     []Exercise `json:"exercises"`
+	Workouts      []Workout  `json:"workouts"`
+}
+
+// Profile describes the exported account. It is informational; importing
+// never changes the target account's profile.
+type Profile struct {
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Exercise is an exercise the archive's sets refer to by ID. Custom marks
+// the account's own exercises; the others come from the global catalog or
+// were shared by other users.
+type Exercise struct {
+	ID            int                     `json:"id"`
+	Custom        bool                    `json:"custom"`
+	Slug          string                  `json:"slug,omitempty"`
+	Name          string                  `json:"name"`
+	MuscleGroup   string                  `json:"muscle_group"`
+	EquipmentType string                  `json:"equipment_type"`
+	TrackingType  string                  `json:"tracking_type"`
+	Notes         string                  `json:"notes"`
+	Instructions  string                  `json:"instructions"`
+	Muscles       []models.ExerciseMuscle `json:"muscles,omitempty"`
+}
+
+// Workout is one exported training session
+type Workout struct {
+	Name       string     `json:"name"`
+	Notes      string     `json:"notes"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	Sets       []Set      `json:"sets"`
+}
+
+// Set is one logged set. ExerciseID refers to Archive.Exercises.
+type Set struct {
+	ExerciseID      int      `json:"exercise_id"`
+	SetNumber       int      `json:"set_number"`
+	Reps            int      `json:"reps"`
+	Weight          float64  `json:"weight"`
+	RPE             *float64 `json:"rpe,omitempty"`
+	RestSeconds     *int     `json:"rest_seconds,omitempty"`
+	DurationSeconds *int     `json:"duration_seconds,omitempty"`
+	DistanceMeters  *float64 `json:"distance_meters,omitempty"`
+	AvgHeartRate    *int     `json:"avg_heart_rate,omitempty"`
+	MaxHeartRate    *int     `json:"max_heart_rate,omitempty"`
+	ElevationGain   *float64 `json:"elevation_gain_meters,omitempty"`
+	AvgPowerWatts   *int     `json:"avg_power_watts,omitempty"`
+	Calories        *int     `json:"calories,omitempty"`
+}
+
+// NewExercise converts an exported exercise; userID is the exporting account
+func NewExercise(e models.Exercise, userID int) Exercise {
+	return Exercise{
+		ID:            e.ID,
+		Custom:        e.OwnedBy(userID),
+		Slug:          e.Slug,
+		Name:          e.Name,
+		MuscleGroup:   e.MuscleGroup,
+		EquipmentType: e.EquipmentType,
+		TrackingType:  e.TrackingType,
+		Notes:         e.Notes,
+		Instructions:  e.Instructions,
+		Muscles:       e.Muscles,
+	}
+}
+
+// ToModel converts the exercise into a private exercise to be created
+func (e Exercise) ToModel() models.Exercise {
+	return models.Exercise{
+		Name:          e.Name,
+		MuscleGroup:   e.MuscleGroup,
+		EquipmentType: e.EquipmentType,
+		TrackingType:  e.TrackingType,
+		Notes:         e.Notes,
+		Instructions:  e.Instructions,
+		Muscles:       e.Muscles,
+		Visibility:    models.VisibilityPrivate,
+	}
+}
+
+// NewWorkout converts an exported workout and its sets
+func NewWorkout(w models.Workout) Workout {
+	workout := Workout{Name: w.Name, Notes: w.Notes, StartedAt: w.StartedAt, FinishedAt: w.FinishedAt, Sets: []Set{}}
+	for _, s := range w.Sets {
+		workout.Sets = append(workout.Sets, Set{
+			ExerciseID:      s.ExerciseID,
+			SetNumber:       s.SetNumber,
+			Reps:            s.Reps,
+			Weight:          s.Weight,
+			RPE:             s.RPE,
+			RestSeconds:     s.RestSeconds,
+			DurationSeconds: s.DurationSeconds,
+			DistanceMeters:  s.DistanceMeters,
+			AvgHeartRate:    s.AvgHeartRate,
+			MaxHeartRate:    s.MaxHeartRate,
+			ElevationGain:   s.ElevationGain,
+			AvgPowerWatts:   s.AvgPowerWatts,
+			Calories:        s.Calories,
+		})
+	}
+	return workout
+}
+
+// ToModel converts the set into a logged set of exerciseID
+func (s Set) ToModel(exerciseID int) models.WorkoutSet {
+	return models.WorkoutSet{
+		ExerciseID:      exerciseID,
+		Reps:            s.Reps,
+		Weight:          s.Weight,
+		RPE:             s.RPE,
+		RestSeconds:     s.RestSeconds,
+		DurationSeconds: s.DurationSeconds,
+		DistanceMeters:  s.DistanceMeters,
+		AvgHeartRate:    s.AvgHeartRate,
+		MaxHeartRate:    s.MaxHeartRate,
+		ElevationGain:   s.ElevationGain,
+		AvgPowerWatts:   s.AvgPowerWatts,
+		Calories:        s.Calories,
+	}
+}
+
+// Write encodes an archive as indented JSON
+func Write(w io.Writer, a Archive) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(a)
+}
+
+// Read decodes and checks an archive. Every set must refer to an exercise
+// in the archive, every exercise needs a name and every workout a start,
+// and names must fit in the database.
+func Read(r io.Reader) (Archive, error) {
+	var a Archive
+	if err := json.NewDecoder(r).Decode(&a); err != nil {
+		return Archive{}, apperrors.Invalid("file", "malformed archive JSON: "+err.Error())
+	}
+	if err := a.validate(); err != nil {
+		return Archive{}, err
+	}
+	return a, nil
+}
+
+func (a Archive) validate() error {
+	if a.FormatVersion != FormatVersion {
+		return apperrors.Invalid("format_version", fmt.Sprintf("unsupported archive format version %d, expected %d", a.FormatVersion, FormatVersion))
+	}
+
+	var fields []apperrors.FieldError
+	invalid := func(field, message string) {
+		fields = append(fields, apperrors.FieldError{Field: field, Message: message})
+	}
+	ids := make(map[int]bool, len(a.Exercises))
+	for i, e := range a.Exercises {
+		if ids[e.ID] {
+			invalid(fmt.Sprintf("exercises[%d].id", i), fmt.Sprintf("exercise ID %d appears more than once", e.ID))
+		}
+		ids[e.ID] = true
+		if strings.TrimSpace(e.Name) == "" {
+			invalid(fmt.Sprintf("exercises[%d].name", i), "exercise name is required")
+		} else if utf8.RuneCountInString(e.Name) > maxNameLength {
+			invalid(fmt.Sprintf("exercises[%d].name", i), fmt.Sprintf("exercise name cannot be longer than %d characters", maxNameLength))
+		}
+	}
+	for i, w := range a.Workouts {
+		if w.StartedAt.IsZero() {
+			invalid(fmt.Sprintf("workouts[%d].started_at", i), "workout start is required")
+		}
+		if utf8.RuneCountInString(w.Name) > maxNameLength {
+			invalid(fmt.Sprintf("workouts[%d].name", i), fmt.Sprintf("workout name cannot be longer than %d characters", maxNameLength))
+		}
+		for j, s := range w.Sets {
+			if !ids[s.ExerciseID] {
+				invalid(fmt.Sprintf("workouts[%d].sets[%d].exercise_id", i, j), fmt.Sprintf("exercise %d is not in the archive", s.ExerciseID))
+			}
+		}
+	}
+	if len(fields) > 0 {
+		return apperrors.Validation(fields...)
+	}
+	return nil
+}
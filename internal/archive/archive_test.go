@@ -0,0 +1,83 @@
+package archive
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteRead_RoundTrip(t *testing.T) {
+	owner := 5
+	started := time.Date(2026, 3, 2, 7, 30, 0, 0, time.UTC)
+	rpe := 8.0
+	a := Archive{
+		FormatVersion: FormatVersion,
+		ExportedAt:    started,
+		Profile:       Profile{Name: "Alex", Email: "alex@example.com"},
+		Exercises: []Exercise{
+			NewExercise(models.Exercise{ID: 3, Slug: "bench-press", Name: "Bench Press", Visibility: models.VisibilityGlobal}, 5),
+			NewExercise(models.Exercise{ID: 40, Name: "Sled Push", OwnerID: &owner, Visibility: models.VisibilityPrivate}, 5),
+		},
+		Workouts: []Workout{NewWorkout(models.Workout{ID: 7, Name: "Push Day", StartedAt: started, Sets: []models.WorkoutSet{
+			{ID: 1, ExerciseID: 3, SetNumber: 1, Reps: 5, Weight: 100, RPE: &rpe},
+		}})},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, a))
+	assert.NotContains(t, buf.String(), "password")
+
+	read, err := Read(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, a, read)
+	assert.False(t, read.Exercises[0].Custom)
+	assert.True(t, read.Exercises[1].Custom)
+
+	set := read.Workouts[0].Sets[0].ToModel(12)
+	assert.Equal(t, 12, set.ExerciseID)
+	assert.Equal(t, 8.0, *set.RPE)
+	assert.Equal(t, models.VisibilityPrivate, read.Exercises[1].ToModel().Visibility)
+}
+
+func TestRead_Invalid(t *testing.T) {
+	_, err := Read(strings.NewReader(`{"format_version": 1, "workouts": [`))
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	_, err = Read(strings.NewReader(`{"format_version": 2}`))
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	_, err = Read(strings.NewReader(`{
+		"format_version": 1,
+		"exercises": [{"id": 3, "name": "Bench Press"}, {"id": 3, "name": ""}],
+		"workouts": [{"name": "Push Day", "started_at": "2026-03-02T07:30:00Z", "sets": [{"exercise_id": 9, "reps": 5}]}]
+	}`))
+	var appErr *apperrors.Error
+	if assert.ErrorAs(t, err, &appErr) {
+		fields := make([]string, 0, len(appErr.Fields))
+		for _, f := range appErr.Fields {
+			fields = append(fields, f.Field)
+		}
+		assert.Equal(t, []string{"exercises[1].id", "exercises[1].name", "workouts[0].sets[0].exercise_id"}, fields)
+	}
+}
+
+func TestRead_NameTooLong(t *testing.T) {
+	long := strings.Repeat("x", maxNameLength+1)
+	_, err := Read(strings.NewReader(`{
+		"format_version": 1,
+		"exercises": [{"id": 3, "name": "` + long + `"}],
+		"workouts": [{"name": "` + long + `", "started_at": "2026-03-02T07:30:00Z", "sets": []}]
+	}`))
+	var appErr *apperrors.Error
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, []apperrors.FieldError{
+			{Field: "exercises[0].name", Message: "exercise name cannot be longer than 255 characters"},
+			{Field: "workouts[0].name", Message: "workout name cannot be longer than 255 characters"},
+		}, appErr.Fields)
+	}
+}
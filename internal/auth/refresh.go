@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// NewRefreshToken returns a random opaque refresh token and the hash to store for it
+func NewRefreshToken() (token string, hash string, err error) {
+	token, err = randomString(32)
+	if err != nil {
+		return "", "", err
+	}
+	return token, HashRefreshToken(token), nil
+}
+
+// NewFamilyID returns a random identifier shared by a chain of rotated refresh tokens
+func NewFamilyID() (string, error) {
+	return randomString(16)
+}
+
+// HashRefreshToken returns the hex encoded SHA-256 of a refresh token. Refresh
+// tokens carry 256 bits of entropy so a fast hash is sufficient here.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
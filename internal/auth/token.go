@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const issuer = "workout-api"
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Claims are the JWT claims carried by an access token. The user ID is
+// stored in the standard subject claim.
+type Claims struct {
+	jwt.RegisteredClaims
+	Role string `json:"role,omitempty"`
+}
+
+// UserID returns the authenticated user's ID from the subject claim
+func (c Claims) UserID() (int, error) {
+	return strconv.Atoi(c.Subject)
+}
+
+// TokenManager issues and validates HS256 signed access tokens
+type TokenManager struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewTokenManager(secret string, ttl time.Duration) *TokenManager {
+	return &TokenManager{secret: []byte(secret), ttl: ttl}
+}
+
+// Issue returns a signed access token for the user and its expiry time
+func (m *TokenManager) Issue(userID int, role string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(m.ttl)
+
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   strconv.Itoa(userID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Role: role,
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// Parse validates the signature, algorithm, issuer and expiry of a token
+func (m *TokenManager) Parse(tokenStr string) (Claims, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(tokenStr, &claims, func(token *jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	if _, err := claims.UserID(); err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	return claims, nil
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenManager_IssueAndParse(t *testing.T) {
+	manager := NewTokenManager("test-secret", time.Minute)
+
+	token, expiresAt, err := manager.Issue(42, "admin")
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, 2*time.Second)
+
+	claims, err := manager.Parse(token)
+	assert.NoError(t, err)
+	userID, err := claims.UserID()
+	assert.NoError(t, err)
+	assert.Equal(t, 42, userID)
+	assert.Equal(t, "admin", claims.Role)
+}
+
+func TestTokenManager_Parse_WrongSecret(t *testing.T) {
+	token, _, err := NewTokenManager("secret-a", time.Minute).Issue(1, "user")
+	assert.NoError(t, err)
+
+	_, err = NewTokenManager("secret-b", time.Minute).Parse(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestTokenManager_Parse_Expired(t *testing.T) {
+	manager := NewTokenManager("test-secret", -time.Minute)
+
+	token, _, err := manager.Issue(1, "user")
+	assert.NoError(t, err)
+
+	_, err = manager.Parse(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestTokenManager_Parse_Garbage(t *testing.T) {
+	_, err := NewTokenManager("test-secret", time.Minute).Parse("not-a-token")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestNewRefreshToken(t *testing.T) {
+	token, hash, err := NewRefreshToken()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, HashRefreshToken(token), hash)
+
+	other, _, err := NewRefreshToken()
+	assert.NoError(t, err)
+	assert.NotEqual(t, token, other)
+}
@@ -0,0 +1,280 @@
+// Package catalog reads versioned exercise catalog files. A catalog is a
+// list of global exercises keyed by a stable slug, supplied as JSON or CSV;
+// the server ships with a default catalog embedded in the binary.
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+)
+
+// FormatVersion is the catalog file layout this package understands. It
+// changes only when fields are renamed or removed.
+const FormatVersion = 1
+
+// Supported file formats
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// listSeparator separates multiple muscle groups within one CSV cell
+const listSeparator = "|"
+
+//go:embed default.json
+var defaultCatalog []byte
+
+// Catalog is a parsed catalog file. Version identifies the catalog
+// contents, e.g. "2026.10", and is echoed back in import reports.
+type Catalog struct {
+	FormatVersion int     `json:"format_version"`
+	Version       string  `json:"version"`
+	Exercises     []Entry `json:"exercises"`
+}
+
+// Entry is one exercise in a catalog. Muscle groups and equipment may be
+// any taxonomy alias.
+type Entry struct {
+	// Row locates the entry in its file for error reports: the 1-based
+	// index for JSON, the line number for CSV
+	Row              int      `json:"-"`
+	Slug             string   `json:"slug"`
+	Name             string   `json:"name"`
+	MuscleGroup      string   `json:"muscle_group"`
+	PrimaryMuscles   []string `json:"primary_muscles"`
+	SecondaryMuscles []string `json:"secondary_muscles"`
+	EquipmentType    string   `json:"equipment_type"`
+	TrackingType     string   `json:"tracking_type"`
+	Instructions     string   `json:"instructions"`
+	Notes            string   `json:"notes"`
+}
+
+// ToModel converts the entry into a global exercise
+func (e Entry) ToModel() models.Exercise {
+	exercise := models.Exercise{
+		Slug:          e.Slug,
+		Name:          e.Name,
+		MuscleGroup:   e.MuscleGroup,
+		EquipmentType: e.EquipmentType,
+		TrackingType:  e.TrackingType,
+		Instructions:  e.Instructions,
+		Notes:         e.Notes,
+		Visibility:    models.VisibilityGlobal,
+	}
+	for _, name := range e.PrimaryMuscles {
+		exercise.Muscles = append(exercise.Muscles, models.ExerciseMuscle{MuscleGroup: name, Role: models.MuscleRolePrimary})
+	}
+	for _, name := range e.SecondaryMuscles {
+		exercise.Muscles = append(exercise.Muscles, models.ExerciseMuscle{MuscleGroup: name, Role: models.MuscleRoleSecondary})
+	}
+	return exercise
+}
+
+// Default returns the catalog bundled with the server
+func Default() (Catalog, error) {
+	return ParseJSON(bytes.NewReader(defaultCatalog))
+}
+
+// FormatFromName picks a format from a file name's extension
+func FormatFromName(name string) (string, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return FormatJSON, nil
+	case ".csv":
+		return FormatCSV, nil
+	}
+	return "", fmt.Errorf("cannot tell the catalog format of %q, expected a .json or .csv file", name)
+}
+
+// Parse reads a catalog in the given format
+func Parse(format string, r io.Reader) (Catalog, error) {
+	switch format {
+	case FormatJSON:
+		return ParseJSON(r)
+	case FormatCSV:
+		return ParseCSV(r)
+	}
+	return Catalog{}, apperrors.Invalid("format", fmt.Sprintf("unsupported catalog format %q", format))
+}
+
+// ParseJSON reads a catalog document of the form
+//
+//	{"format_version": 1, "version": "2026.10", "exercises": [{"slug": ...}]}
+func ParseJSON(r io.Reader) (Catalog, error) {
+	var c Catalog
+	if err := json.NewDecoder(r).Decode(&c); err != nil {
+		if tooLarge := sizeError(err); tooLarge != nil {
+			return Catalog{}, tooLarge
+		}
+		return Catalog{}, apperrors.Invalid("file", "malformed catalog JSON: "+err.Error())
+	}
+	for i := range c.Exercises {
+		c.Exercises[i].Row = i + 1
+	}
+	if err := c.validate(); err != nil {
+		return Catalog{}, err
+	}
+	return c, nil
+}
+
+// sizeError reports a body cut off by http.MaxBytesReader as invalid
+func sizeError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return apperrors.Invalid("file", fmt.Sprintf("catalog exceeds %d MB", tooLarge.Limit>>20))
+	}
+	return nil
+}
+
+// csvColumns are the columns a CSV catalog may have; slug, name and
+// muscle_group are required
+var csvColumns = map[string]bool{
+	"slug": true, "name": true, "muscle_group": true, "primary_muscles": true, "secondary_muscles": true,
+	"equipment_type": true, "tracking_type": true, "instructions": true, "notes": true,
+}
+
+// ParseCSV reads a catalog with a header row naming its columns. Metadata
+// comes from leading comment lines such as "# version: 2026.10", and list
+// columns separate muscle groups with "|".
+func ParseCSV(r io.Reader) (Catalog, error) {
+	data, err := io.ReadAll(r)
+	if tooLarge := sizeError(err); tooLarge != nil {
+		return Catalog{}, tooLarge
+	}
+	if err != nil {
+		return Catalog{}, err
+	}
+
+	c := Catalog{FormatVersion: FormatVersion}
+	if err := c.readCSVMetadata(data); err != nil {
+		return Catalog{}, err
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return Catalog{}, apperrors.Invalid("file", "catalog CSV has no header row")
+	}
+	if err != nil {
+		return Catalog{}, apperrors.Invalid("file", "malformed catalog CSV: "+err.Error())
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !csvColumns[name] {
+			return Catalog{}, apperrors.Invalid("file", fmt.Sprintf("unknown catalog CSV column %q", name))
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"slug", "name", "muscle_group"} {
+		if _, ok := columns[required]; !ok {
+			return Catalog{}, apperrors.Invalid("file", fmt.Sprintf("catalog CSV is missing the %s column", required))
+		}
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Catalog{}, apperrors.Invalid("file", "malformed catalog CSV: "+err.Error())
+		}
+
+		line, _ := reader.FieldPos(0)
+		cell := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		c.Exercises = append(c.Exercises, Entry{
+			Row:              line,
+			Slug:             cell("slug"),
+			Name:             cell("name"),
+			MuscleGroup:      cell("muscle_group"),
+			PrimaryMuscles:   splitList(cell("primary_muscles")),
+			SecondaryMuscles: splitList(cell("secondary_muscles")),
+			EquipmentType:    cell("equipment_type"),
+			TrackingType:     cell("tracking_type"),
+			Instructions:     cell("instructions"),
+			Notes:            cell("notes"),
+		})
+	}
+	if err := c.validate(); err != nil {
+		return Catalog{}, err
+	}
+	return c, nil
+}
+
+// readCSVMetadata reads "# key: value" lines preceding the header
+func (c *Catalog) readCSVMetadata(data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		comment, ok := strings.CutPrefix(line, "#")
+		if !ok {
+			break
+		}
+		key, value, ok := strings.Cut(comment, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "version":
+			c.Version = value
+		case "format_version":
+			v, err := strconv.Atoi(value)
+			if err != nil {
+				return apperrors.Invalid("format_version", fmt.Sprintf("invalid format version %q", value))
+			}
+			c.FormatVersion = v
+		}
+	}
+	return nil
+}
+
+// validate checks the file as a whole; entries are validated individually
+// on import so one bad row does not reject the file
+func (c Catalog) validate() error {
+	var fields []apperrors.FieldError
+	if c.FormatVersion != FormatVersion {
+		fields = append(fields, apperrors.FieldError{Field: "format_version", Message: fmt.Sprintf("unsupported catalog format version %d, expected %d", c.FormatVersion, FormatVersion)})
+	}
+	if strings.TrimSpace(c.Version) == "" {
+		fields = append(fields, apperrors.FieldError{Field: "version", Message: "catalog version is required"})
+	}
+	if len(fields) > 0 {
+		return apperrors.Validation(fields...)
+	}
+	return nil
+}
+
+func splitList(cell string) []string {
+	var items []string
+	for _, item := range strings.Split(cell, listSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,118 @@
+package catalog
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJSON(t *testing.T) {
+	c, err := ParseJSON(strings.NewReader(`{
+		"format_version": 1,
+		"version": "2026.10",
+		"exercises": [
+			{"slug": "push-up", "name": "Push-up", "muscle_group": "chest", "secondary_muscles": ["triceps"], "equipment_type": "bodyweight"},
+			{"slug": "dip", "name": "Dip", "muscle_group": "chest"}
+		]
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "2026.10", c.Version)
+	assert.Len(t, c.Exercises, 2)
+	assert.Equal(t, 2, c.Exercises[1].Row)
+
+	exercise := c.Exercises[0].ToModel()
+	assert.Equal(t, "push-up", exercise.Slug)
+	assert.Equal(t, models.VisibilityGlobal, exercise.Visibility)
+	assert.Equal(t, []models.ExerciseMuscle{{MuscleGroup: "triceps", Role: models.MuscleRoleSecondary}}, exercise.Muscles)
+}
+
+func TestParseJSON_Invalid(t *testing.T) {
+	_, err := ParseJSON(strings.NewReader(`{"exercises": [`))
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	_, err = ParseJSON(strings.NewReader(`{"format_version": 2, "exercises": []}`))
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	var appErr *apperrors.Error
+	assert.ErrorAs(t, err, &appErr)
+	assert.Len(t, appErr.Fields, 2)
+}
+
+func TestParseCSV(t *testing.T) {
+	data := `# version: 2026.10
+# format_version: 1
+slug,name,muscle_group,secondary_muscles,equipment_type,tracking_type,instructions
+push-up,Push-up,chest,triceps | shoulders,bodyweight,reps_only,"Lower, then push"
+
+dip,Dip,chest,,,,
+`
+	c, err := ParseCSV(strings.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, "2026.10", c.Version)
+	assert.Len(t, c.Exercises, 2)
+
+	assert.Equal(t, Entry{
+		Row:              4,
+		Slug:             "push-up",
+		Name:             "Push-up",
+		MuscleGroup:      "chest",
+		SecondaryMuscles: []string{"triceps", "shoulders"},
+		EquipmentType:    "bodyweight",
+		TrackingType:     "reps_only",
+		Instructions:     "Lower, then push",
+	}, c.Exercises[0])
+	// Rows are numbered by line, so blank lines count
+	assert.Equal(t, 6, c.Exercises[1].Row)
+	assert.Empty(t, c.Exercises[1].SecondaryMuscles)
+}
+
+func TestParseCSV_BadHeader(t *testing.T) {
+	_, err := ParseCSV(strings.NewReader("# version: 1\nslug,name\npush-up,Push-up\n"))
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Contains(t, err.Error(), "missing the muscle_group column")
+
+	_, err = ParseCSV(strings.NewReader("# version: 1\nslug,name,muscle_group,difficulty\n"))
+	assert.Contains(t, err.Error(), `unknown catalog CSV column "difficulty"`)
+}
+
+func TestParse_TooLarge(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatCSV} {
+		body := io.NopCloser(strings.NewReader(`{"exercises": [` + strings.Repeat(" ", 2<<20)))
+		_, err := Parse(format, http.MaxBytesReader(httptest.NewRecorder(), body, 1<<20))
+		assert.ErrorIs(t, err, apperrors.ErrValidation, format)
+		assert.Contains(t, err.Error(), "catalog exceeds 1 MB", format)
+	}
+}
+
+func TestFormatFromName(t *testing.T) {
+	format, err := FormatFromName("catalog.CSV")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+
+	_, err = FormatFromName("catalog.xml")
+	assert.Error(t, err)
+}
+
+func TestDefault(t *testing.T) {
+	c, err := Default()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, c.Exercises)
+
+	slug := regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	seen := map[string]bool{}
+	for _, e := range c.Exercises {
+		assert.Regexp(t, slug, e.Slug)
+		assert.False(t, seen[e.Slug], "duplicate slug %s", e.Slug)
+		seen[e.Slug] = true
+		assert.NotEmpty(t, e.Name, e.Slug)
+		assert.NotEmpty(t, e.MuscleGroup, e.Slug)
+		assert.NotEmpty(t, e.Instructions, e.Slug)
+	}
+}
@@ -0,0 +1,254 @@
+// Package config loads server configuration from defaults, an optional
+// YAML file and environment variables, in increasing order of precedence.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
+	Log      LogConfig      `yaml:"log"`
+	Auth     AuthConfig     `yaml:"auth"`
+}
+
+type ServerConfig struct {
+	Addr              string        `yaml:"addr"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`
+	// DrainDelay is how long the server keeps serving after readiness starts
+	// failing, so load balancers stop routing to it before connections close
+	DrainDelay     time.Duration `yaml:"drain_delay"`
+	MaxHeaderBytes int           `yaml:"max_header_bytes"`
+}
+
+type DatabaseConfig struct {
+	DSN             string        `yaml:"dsn"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+	QueryTimeout    time.Duration `yaml:"query_timeout"`
+	// AutoMigrate applies pending migrations when the server starts
+	AutoMigrate bool `yaml:"auto_migrate"`
+}
+
+type LogConfig struct {
+	Level string `yaml:"level"`
+}
+
+type AuthConfig struct {
+	JWTSecret       string        `yaml:"jwt_secret"`
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl"`
+	BcryptCost      int           `yaml:"bcrypt_cost"`
+}
+
+// minSecretLength is the shortest accepted JWT signing secret, matching the
+// HS256 output size
+const minSecretLength = 32
+
+// Default returns the configuration used for any value not set elsewhere
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Addr:              ":8081",
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       120 * time.Second,
+			ShutdownTimeout:   20 * time.Second,
+			MaxHeaderBytes:    1 << 20,
+		},
+		Database: DatabaseConfig{
+			MaxOpenConns:    25,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+			QueryTimeout:    5 * time.Second,
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		Auth: AuthConfig{
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 30 * 24 * time.Hour,
+			BcryptCost:      10,
+		},
+	}
+}
+
+// Load builds the configuration from defaults, then the YAML file at path
+// (skipped when path is empty), then environment variables, and validates
+// the result. The database DSN has no default, since it carries
+// credentials, and must come from the file or DATABASE_DSN.
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		if ext := strings.ToLower(filepath.Ext(path)); ext != ".yaml" && ext != ".yml" {
+			return Config{}, fmt.Errorf("unsupported config file %s: only YAML (.yaml or .yml) is supported", path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return Config{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	if err := applyEnv(&cfg, os.LookupEnv); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Validate reports every invalid setting at once
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Server.Addr != "", "server.addr is required")
+	check(c.Server.ReadTimeout > 0, "server.read_timeout must be positive")
+	check(c.Server.ReadHeaderTimeout > 0, "server.read_header_timeout must be positive")
+	check(c.Server.WriteTimeout > 0, "server.write_timeout must be positive")
+	check(c.Server.IdleTimeout > 0, "server.idle_timeout must be positive")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+	check(c.Server.DrainDelay >= 0, "server.drain_delay cannot be negative")
+	check(c.Server.MaxHeaderBytes > 0, "server.max_header_bytes must be positive")
+
+	check(c.Database.DSN != "", "database.dsn is required, set it in the config file or DATABASE_DSN")
+	check(c.Database.MaxOpenConns >= 0, "database.max_open_conns cannot be negative")
+	check(c.Database.MaxIdleConns >= 0, "database.max_idle_conns cannot be negative")
+	check(c.Database.MaxOpenConns == 0 || c.Database.MaxIdleConns <= c.Database.MaxOpenConns,
+		"database.max_idle_conns (%d) cannot exceed database.max_open_conns (%d)", c.Database.MaxIdleConns, c.Database.MaxOpenConns)
+	check(c.Database.ConnMaxLifetime >= 0, "database.conn_max_lifetime cannot be negative")
+	check(c.Database.ConnMaxIdleTime >= 0, "database.conn_max_idle_time cannot be negative")
+	check(c.Database.QueryTimeout >= 0, "database.query_timeout cannot be negative")
+
+	_, err := c.Log.SlogLevel()
+	check(err == nil, "log.level must be one of debug, info, warn, error")
+
+	check(len(c.Auth.JWTSecret) >= minSecretLength, "auth.jwt_secret must be at least %d characters", minSecretLength)
+	check(c.Auth.AccessTokenTTL > 0, "auth.access_token_ttl must be positive")
+	check(c.Auth.RefreshTokenTTL > c.Auth.AccessTokenTTL, "auth.refresh_token_ttl must be longer than auth.access_token_ttl")
+	check(c.Auth.BcryptCost >= 4 && c.Auth.BcryptCost <= 31, "auth.bcrypt_cost must be between 4 and 31")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// SlogLevel parses the configured log level
+func (l LogConfig) SlogLevel() (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(strings.ToLower(l.Level)))
+	return level, err
+}
+
+type lookupFunc func(key string) (string, bool)
+
+// envBinding maps an environment variable onto a config field
+type envBinding struct {
+	key string
+	set func(value string) error
+}
+
+func applyEnv(cfg *Config, lookup lookupFunc) error {
+	bindings := []envBinding{
+		{"SERVER_ADDR", setString(&cfg.Server.Addr)},
+		{"SERVER_READ_TIMEOUT", setDuration(&cfg.Server.ReadTimeout)},
+		{"SERVER_READ_HEADER_TIMEOUT", setDuration(&cfg.Server.ReadHeaderTimeout)},
+		{"SERVER_WRITE_TIMEOUT", setDuration(&cfg.Server.WriteTimeout)},
+		{"SERVER_IDLE_TIMEOUT", setDuration(&cfg.Server.IdleTimeout)},
+		{"SERVER_SHUTDOWN_TIMEOUT", setDuration(&cfg.Server.ShutdownTimeout)},
+		{"SERVER_DRAIN_DELAY", setDuration(&cfg.Server.DrainDelay)},
+		{"SERVER_MAX_HEADER_BYTES", setInt(&cfg.Server.MaxHeaderBytes)},
+		{"DATABASE_DSN", setString(&cfg.Database.DSN)},
+		{"DATABASE_MAX_OPEN_CONNS", setInt(&cfg.Database.MaxOpenConns)},
+		{"DATABASE_MAX_IDLE_CONNS", setInt(&cfg.Database.MaxIdleConns)},
+		{"DATABASE_CONN_MAX_LIFETIME", setDuration(&cfg.Database.ConnMaxLifetime)},
+		{"DATABASE_CONN_MAX_IDLE_TIME", setDuration(&cfg.Database.ConnMaxIdleTime)},
+		{"DATABASE_QUERY_TIMEOUT", setDuration(&cfg.Database.QueryTimeout)},
+		{"DATABASE_AUTO_MIGRATE", setBool(&cfg.Database.AutoMigrate)},
+		{"LOG_LEVEL", setString(&cfg.Log.Level)},
+		{"AUTH_JWT_SECRET", setString(&cfg.Auth.JWTSecret)},
+		{"AUTH_ACCESS_TOKEN_TTL", setDuration(&cfg.Auth.AccessTokenTTL)},
+		{"AUTH_REFRESH_TOKEN_TTL", setDuration(&cfg.Auth.RefreshTokenTTL)},
+		{"AUTH_BCRYPT_COST", setInt(&cfg.Auth.BcryptCost)},
+	}
+
+	for _, b := range bindings {
+		value, ok := lookup(b.key)
+		if !ok || value == "" {
+			continue
+		}
+		if err := b.set(value); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", b.key, err)
+		}
+	}
+	return nil
+}
+
+func setString(dst *string) func(string) error {
+	return func(value string) error {
+		*dst = value
+		return nil
+	}
+}
+
+func setInt(dst *int) func(string) error {
+	return func(value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*dst = n
+		return nil
+	}
+}
+
+func setBool(dst *bool) func(string) error {
+	return func(value string) error {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*dst = b
+		return nil
+	}
+}
+
+func setDuration(dst *time.Duration) func(string) error {
+	return func(value string) error {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*dst = d
+		return nil
+	}
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func envMap(values map[string]string) lookupFunc {
+	return func(key string) (string, bool) {
+		v, ok := values[key]
+		return v, ok
+	}
+}
+
+func TestDefault_RequiresSecretAndDSN(t *testing.T) {
+	err := Default().Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "auth.jwt_secret")
+	assert.Contains(t, err.Error(), "database.dsn is required")
+}
+
+func TestApplyEnv_OverridesDefaults(t *testing.T) {
+	cfg := Default()
+
+	err := applyEnv(&cfg, envMap(map[string]string{
+		"SERVER_ADDR":             ":9090",
+		"SERVER_DRAIN_DELAY":      "3s",
+		"DATABASE_DSN":            "postgres://db/workouts",
+		"DATABASE_MAX_OPEN_CONNS": "50",
+		"DATABASE_QUERY_TIMEOUT":  "2s",
+		"DATABASE_AUTO_MIGRATE":   "true",
+		"LOG_LEVEL":               "debug",
+		"AUTH_JWT_SECRET":         testSecret,
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, ":9090", cfg.Server.Addr)
+	assert.Equal(t, 3*time.Second, cfg.Server.DrainDelay)
+	assert.Equal(t, "postgres://db/workouts", cfg.Database.DSN)
+	assert.Equal(t, 50, cfg.Database.MaxOpenConns)
+	assert.Equal(t, 2*time.Second, cfg.Database.QueryTimeout)
+	assert.True(t, cfg.Database.AutoMigrate)
+	assert.Equal(t, "debug", cfg.Log.Level)
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestApplyEnv_InvalidValue(t *testing.T) {
+	cfg := Default()
+
+	err := applyEnv(&cfg, envMap(map[string]string{"DATABASE_MAX_OPEN_CONNS": "lots"}))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "DATABASE_MAX_OPEN_CONNS")
+}
+
+func TestLoad_FileThenEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`
+server:
+  addr: ":7000"
+  write_timeout: 45s
+database:
+  dsn: "host=localhost dbname=workout_db"
+  max_idle_conns: 5
+auth:
+  jwt_secret: "`+testSecret+`"
+`), 0o600)
+	assert.NoError(t, err)
+
+	t.Setenv("SERVER_ADDR", ":7001")
+
+	cfg, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, ":7001", cfg.Server.Addr)
+	assert.Equal(t, 45*time.Second, cfg.Server.WriteTimeout)
+	assert.Equal(t, 5, cfg.Database.MaxIdleConns)
+	// Values missing from the file keep their defaults
+	assert.Equal(t, Default().Server.ReadTimeout, cfg.Server.ReadTimeout)
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestLoad_RejectsOtherFormats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	assert.NoError(t, os.WriteFile(path, []byte("[server]\naddr = \":7000\"\n"), 0o600))
+
+	_, err := Load(path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "only YAML (.yaml or .yml) is supported")
+}
+
+func TestValidate_ReportsAllProblems(t *testing.T) {
+	cfg := Default()
+	cfg.Auth.JWTSecret = testSecret
+	cfg.Database.DSN = "host=localhost dbname=workout_db"
+	cfg.Server.Addr = ""
+	cfg.Database.MaxOpenConns = 5
+	cfg.Database.MaxIdleConns = 10
+	cfg.Log.Level = "verbose"
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "server.addr is required")
+	assert.Contains(t, err.Error(), "database.max_idle_conns")
+	assert.Contains(t, err.Error(), "log.level")
+}
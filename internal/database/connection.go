@@ -1,20 +1,34 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
+	"workout-api/internal/config"
+
 	_ "github.com/lib/pq"
 )
 
-func NewConnection() (*sql.DB, error) {
-	connectionStr := "host=localhost port=5432 user=joey password=postgres dbname=workout_db sslmode=disable"
+// pingTimeout bounds the initial connectivity check
+const pingTimeout = 5 * time.Second
 
-	db, err := sql.Open("postgres", connectionStr)
+func NewConnection(cfg config.DatabaseConfig) (*sql.DB, error) {
+	db, err := sql.Open("postgres", cfg.DSN)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	if err := db.Ping(); err != nil {
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
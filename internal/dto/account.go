@@ -0,0 +1,40 @@
+package dto
+
+import "workout-api/internal/models"
+
+// AccountImportResponse reports the outcome of restoring an account archive
+type AccountImportResponse struct {
+	FormatVersion   int                      `json:"format_version"`
+	WorkoutsCreated int                      `json:"workouts_created"`
+	WorkoutsSkipped int                      `json:"workouts_skipped"`
+	SetsImported    int                      `json:"sets_imported"`
+	Exercises       []ImportedExerciseResult `json:"exercises"`
+	Skipped         []ArchiveSetIssueResult  `json:"skipped"`
+}
+
+// ArchiveSetIssueResult explains why one archived set was not imported
+type ArchiveSetIssueResult struct {
+	Workout  int    `json:"workout"`
+	Set      int    `json:"set"`
+	Exercise string `json:"exercise"`
+	Reason   string `json:"reason"`
+}
+
+func NewAccountImportResponse(report models.AccountImportReport) AccountImportResponse {
+	exercises := make([]ImportedExerciseResult, 0, len(report.Exercises))
+	for _, e := range report.Exercises {
+		exercises = append(exercises, ImportedExerciseResult(e))
+	}
+	skipped := make([]ArchiveSetIssueResult, 0, len(report.Skipped))
+	for _, s := range report.Skipped {
+		skipped = append(skipped, ArchiveSetIssueResult(s))
+	}
+	return AccountImportResponse{
+		FormatVersion:   report.FormatVersion,
+		WorkoutsCreated: report.WorkoutsCreated,
+		WorkoutsSkipped: report.WorkoutsSkipped,
+		SetsImported:    report.SetsImported,
+		Exercises:       exercises,
+		Skipped:         skipped,
+	}
+}
@@ -0,0 +1,12 @@
+package dto
+
+// LoginRequest is the body accepted by POST /auth/login
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// RefreshRequest is the body accepted by POST /auth/refresh and /auth/logout
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
@@ -0,0 +1,161 @@
+package dto
+
+import (
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+)
+
+// ExerciseRequest is the body accepted when creating or updating an
+// exercise. Muscle groups and equipment may be given as any known alias;
+// muscle_group is the main primary muscle. Visibility defaults to private
+// for users and global for admins, and tracking_type to weight_reps.
+type ExerciseRequest struct {
+	Name             string   `json:"name" binding:"required"`
+	MuscleGroup      string   `json:"muscle_group" binding:"required"`
+	PrimaryMuscles   []string `json:"primary_muscles"`
+	SecondaryMuscles []string `json:"secondary_muscles"`
+	EquipmentType    string   `json:"equipment_type"`
+	TrackingType     string   `json:"tracking_type" binding:"omitempty,oneof=weight_reps reps_only duration distance_duration"`
+	Notes            string   `json:"notes"`
+	Instructions     string   `json:"instructions"`
+	Visibility       string   `json:"visibility" binding:"omitempty,oneof=global private shared"`
+}
+
+func (r ExerciseRequest) ToModel(id int) models.Exercise {
+	exercise := models.Exercise{
+		ID:            id,
+		Name:          r.Name,
+		MuscleGroup:   r.MuscleGroup,
+		EquipmentType: r.EquipmentType,
+		TrackingType:  r.TrackingType,
+		Notes:         r.Notes,
+		Instructions:  r.Instructions,
+		Visibility:    r.Visibility,
+	}
+	for _, name := range r.PrimaryMuscles {
+		exercise.Muscles = append(exercise.Muscles, models.ExerciseMuscle{MuscleGroup: name, Role: models.MuscleRolePrimary})
+	}
+	for _, name := range r.SecondaryMuscles {
+		exercise.Muscles = append(exercise.Muscles, models.ExerciseMuscle{MuscleGroup: name, Role: models.MuscleRoleSecondary})
+	}
+	return exercise
+}
+
+// ExerciseResponse is the public representation of an exercise
+type ExerciseResponse struct {
+	ID            int             `json:"id"`
+	Slug          string          `json:"slug,omitempty"`
+	Name          string          `json:"name"`
+	MuscleGroup   string          `json:"muscle_group"`
+	EquipmentType string          `json:"equipment_type"`
+	TrackingType  string          `json:"tracking_type"`
+	Notes         string          `json:"notes"`
+	Instructions  string          `json:"instructions"`
+	Muscles       ExerciseMuscles `json:"muscles"`
+	OwnerID       *int            `json:"owner_id"`
+	Visibility    string          `json:"visibility"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// ExerciseMuscles lists the muscle group slugs an exercise trains by role
+type ExerciseMuscles struct {
+	Primary   []string `json:"primary"`
+	Secondary []string `json:"secondary"`
+}
+
+func NewExerciseResponse(exercise models.Exercise) ExerciseResponse {
+	muscles := ExerciseMuscles{Primary: []string{}, Secondary: []string{}}
+	for _, m := range exercise.Muscles {
+		if m.Role == models.MuscleRoleSecondary {
+			muscles.Secondary = append(muscles.Secondary, m.MuscleGroup)
+		} else {
+			muscles.Primary = append(muscles.Primary, m.MuscleGroup)
+		}
+	}
+
+	return ExerciseResponse{
+		ID:            exercise.ID,
+		Slug:          exercise.Slug,
+		Name:          exercise.Name,
+		MuscleGroup:   exercise.MuscleGroup,
+		EquipmentType: exercise.EquipmentType,
+		TrackingType:  exercise.TrackingType,
+		Notes:         exercise.Notes,
+		Instructions:  exercise.Instructions,
+		Muscles:       muscles,
+		OwnerID:       exercise.OwnerID,
+		Visibility:    exercise.Visibility,
+		CreatedAt:     exercise.CreatedAt,
+		UpdatedAt:     exercise.UpdatedAt,
+	}
+}
+
+func NewExerciseResponses(exercises []models.Exercise) []ExerciseResponse {
+	responses := make([]ExerciseResponse, 0, len(exercises))
+	for _, exercise := range exercises {
+		responses = append(responses, NewExerciseResponse(exercise))
+	}
+	return responses
+}
+
+// ExerciseSearchResponse is one ranked search hit
+type ExerciseSearchResponse struct {
+	ExerciseResponse
+	Rank       float64            `json:"rank"`
+	Highlights ExerciseHighlights `json:"highlights"`
+}
+
+// ExerciseHighlights holds the matched fields as escaped HTML with hits
+// wrapped in <mark>
+type ExerciseHighlights struct {
+	Name  string `json:"name"`
+	Notes string `json:"notes,omitempty"`
+}
+
+func NewExerciseSearchResponses(results []models.ExerciseSearchResult) []ExerciseSearchResponse {
+	responses := make([]ExerciseSearchResponse, 0, len(results))
+	for _, res := range results {
+		responses = append(responses, ExerciseSearchResponse{
+			ExerciseResponse: NewExerciseResponse(res.Exercise),
+			Rank:             res.Rank,
+			Highlights:       ExerciseHighlights{Name: res.NameHighlight, Notes: res.NotesHighlight},
+		})
+	}
+	return responses
+}
+
+// CatalogImportResponse reports the outcome of a catalog import
+type CatalogImportResponse struct {
+	Version string                  `json:"version"`
+	DryRun  bool                    `json:"dry_run"`
+	Total   int                     `json:"total"`
+	Created int                     `json:"created"`
+	Updated int                     `json:"updated"`
+	Failed  int                     `json:"failed"`
+	Errors  []CatalogRowErrorResult `json:"errors"`
+}
+
+// CatalogRowErrorResult lists the problems with one skipped catalog row
+type CatalogRowErrorResult struct {
+	Row    int                    `json:"row"`
+	Slug   string                 `json:"slug,omitempty"`
+	Fields []apperrors.FieldError `json:"fields"`
+}
+
+func NewCatalogImportResponse(report models.CatalogImportReport) CatalogImportResponse {
+	errs := make([]CatalogRowErrorResult, 0, len(report.Errors))
+	for _, e := range report.Errors {
+		errs = append(errs, CatalogRowErrorResult{Row: e.Row, Slug: e.Slug, Fields: e.Fields})
+	}
+	return CatalogImportResponse{
+		Version: report.Version,
+		DryRun:  report.DryRun,
+		Total:   report.Total,
+		Created: report.Created,
+		Updated: report.Updated,
+		Failed:  report.Failed,
+		Errors:  errs,
+	}
+}
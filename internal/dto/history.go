@@ -0,0 +1,51 @@
+package dto
+
+import "workout-api/internal/models"
+
+// HistoryImportResponse reports the outcome of a workout history import
+type HistoryImportResponse struct {
+	Source          string                   `json:"source"`
+	Rows            int                      `json:"rows"`
+	WorkoutsCreated int                      `json:"workouts_created"`
+	WorkoutsSkipped int                      `json:"workouts_skipped"`
+	SetsImported    int                      `json:"sets_imported"`
+	Exercises       []ImportedExerciseResult `json:"exercises"`
+	Unmapped        []ImportRowIssueResult   `json:"unmapped"`
+}
+
+// ImportedExerciseResult shows which exercise an exported name was mapped to
+type ImportedExerciseResult struct {
+	Name         string  `json:"name"`
+	ExerciseID   int     `json:"exercise_id"`
+	ExerciseName string  `json:"exercise_name"`
+	Created      bool    `json:"created"`
+	Score        float64 `json:"score"`
+	Sets         int     `json:"sets"`
+}
+
+// ImportRowIssueResult explains why one exported row was not imported
+type ImportRowIssueResult struct {
+	Row      int    `json:"row"`
+	Exercise string `json:"exercise,omitempty"`
+	Reason   string `json:"reason"`
+}
+
+func NewHistoryImportResponse(report models.HistoryImportReport) HistoryImportResponse {
+	exercises := make([]ImportedExerciseResult, 0, len(report.Exercises))
+	for _, e := range report.Exercises {
+		exercises = append(exercises, ImportedExerciseResult(e))
+	}
+	unmapped := make([]ImportRowIssueResult, 0, len(report.Unmapped))
+	for _, u := range report.Unmapped {
+		unmapped = append(unmapped, ImportRowIssueResult(u))
+	}
+	return HistoryImportResponse{
+		Source:          report.Source,
+		Rows:            report.Rows,
+		WorkoutsCreated: report.WorkoutsCreated,
+		WorkoutsSkipped: report.WorkoutsSkipped,
+		SetsImported:    report.SetsImported,
+		Exercises:       exercises,
+		Unmapped:        unmapped,
+	}
+}
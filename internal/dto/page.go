@@ -0,0 +1,22 @@
+package dto
+
+import "workout-api/internal/pagination"
+
+// PageResponse is the envelope returned by list endpoints. A cursor is
+// omitted when there is no page in that direction.
+type PageResponse[T any] struct {
+	Data       []T    `json:"data"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// NewPageResponse wraps a page, converting its items with convert
+func NewPageResponse[M, T any](page pagination.Page[M], convert func([]M) []T) PageResponse[T] {
+	return PageResponse[T]{
+		Data:       convert(page.Items),
+		Limit:      page.Limit,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	}
+}
@@ -0,0 +1,89 @@
+package dto
+
+import "workout-api/internal/models"
+
+// ProgramRequest is the body accepted when creating or replacing a
+// program. Visibility defaults to private and rounding to 2.5.
+type ProgramRequest struct {
+	Name         string                   `json:"name" binding:"required"`
+	Description  string                   `json:"description"`
+	Visibility   string                   `json:"visibility"`
+	Repeats      bool                     `json:"repeats"`
+	Rounding     float64                  `json:"rounding"`
+	Days         []ProgramDayRequest      `json:"days" binding:"required,min=1,dive"`
+	Progressions []ProgressionRuleRequest `json:"progressions" binding:"dive"`
+}
+
+// ProgramDayRequest schedules one of the caller's routines on a day of a
+// program week. Prescriptions for an exercise are numbered in the order
+// given.
+type ProgramDayRequest struct {
+	Week          int                   `json:"week" binding:"required"`
+	Day           int                   `json:"day" binding:"required"`
+	Name          string                `json:"name"`
+	RoutineID     int                   `json:"routine_id" binding:"required"`
+	Prescriptions []PrescriptionRequest `json:"prescriptions" binding:"dive"`
+}
+
+type PrescriptionRequest struct {
+	ExerciseID int     `json:"exercise_id" binding:"required"`
+	PercentTM  float64 `json:"percent_tm" binding:"required"`
+	Reps       int     `json:"reps" binding:"required"`
+	AMRAP      bool    `json:"amrap"`
+}
+
+type ProgressionRuleRequest struct {
+	ExerciseID int     `json:"exercise_id" binding:"required"`
+	Increment  float64 `json:"increment" binding:"required"`
+	Trigger    string  `json:"trigger" binding:"required"`
+}
+
+func (r ProgramRequest) ToModel(id int) models.Program {
+	program := models.Program{
+		ID:          id,
+		Name:        r.Name,
+		Description: r.Description,
+		Visibility:  r.Visibility,
+		Repeats:     r.Repeats,
+		Rounding:    r.Rounding,
+	}
+	for _, d := range r.Days {
+		day := models.ProgramDay{Week: d.Week, Day: d.Day, Name: d.Name, RoutineID: d.RoutineID}
+		for _, rx := range d.Prescriptions {
+			day.Prescriptions = append(day.Prescriptions, models.Prescription{
+				ExerciseID: rx.ExerciseID,
+				PercentTM:  rx.PercentTM,
+				Reps:       rx.Reps,
+				AMRAP:      rx.AMRAP,
+			})
+		}
+		program.Days = append(program.Days, day)
+	}
+	for _, p := range r.Progressions {
+		program.Progressions = append(program.Progressions, models.ProgressionRule{
+			ExerciseID: p.ExerciseID,
+			Increment:  p.Increment,
+			Trigger:    p.Trigger,
+		})
+	}
+	return program
+}
+
+// EnrollRequest is the body accepted when enrolling in a program. It needs
+// a training max for every exercise the program prescribes by percentage.
+type EnrollRequest struct {
+	TrainingMaxes []TrainingMaxRequest `json:"training_maxes" binding:"dive"`
+}
+
+type TrainingMaxRequest struct {
+	ExerciseID int     `json:"exercise_id" binding:"required"`
+	Weight     float64 `json:"weight" binding:"required"`
+}
+
+func (r EnrollRequest) ToModel() []models.TrainingMax {
+	maxes := make([]models.TrainingMax, 0, len(r.TrainingMaxes))
+	for _, tm := range r.TrainingMaxes {
+		maxes = append(maxes, models.TrainingMax{ExerciseID: tm.ExerciseID, Weight: tm.Weight})
+	}
+	return maxes
+}
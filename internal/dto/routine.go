@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+	"workout-api/internal/models"
+)
+
+// RoutineRequest is the body accepted when creating or replacing a routine.
+// Exercises are performed in the order given.
+type RoutineRequest struct {
+	Name      string                   `json:"name" binding:"required"`
+	Notes     string                   `json:"notes"`
+	Exercises []RoutineExerciseRequest `json:"exercises" binding:"required,min=1,dive"`
+}
+
+// RoutineExerciseRequest is one exercise of a routine. rep_max defaults to
+// rep_min for a fixed rep target.
+type RoutineExerciseRequest struct {
+	ExerciseID   int      `json:"exercise_id" binding:"required"`
+	TargetSets   int      `json:"target_sets" binding:"required"`
+	RepMin       int      `json:"rep_min" binding:"required"`
+	RepMax       int      `json:"rep_max"`
+	TargetWeight *float64 `json:"target_weight"`
+	TargetRPE    *float64 `json:"target_rpe"`
+	RestSeconds  *int     `json:"rest_seconds"`
+	Notes        string   `json:"notes"`
+}
+
+func (r RoutineRequest) ToModel(id int) models.Routine {
+	routine := models.Routine{ID: id, Name: r.Name, Notes: r.Notes}
+	for _, e := range r.Exercises {
+		routine.Exercises = append(routine.Exercises, models.RoutineExercise{
+			ExerciseID:   e.ExerciseID,
+			TargetSets:   e.TargetSets,
+			RepMin:       e.RepMin,
+			RepMax:       e.RepMax,
+			TargetWeight: e.TargetWeight,
+			TargetRPE:    e.TargetRPE,
+			RestSeconds:  e.RestSeconds,
+			Notes:        e.Notes,
+		})
+	}
+	return routine
+}
+
+// StartRoutineRequest is the optional body accepted when starting a
+// workout from a routine or a program session; the start time defaults to now
+type StartRoutineRequest struct {
+	StartedAt time.Time `json:"started_at"`
+}
@@ -0,0 +1,50 @@
+package dto
+
+import "workout-api/internal/models"
+
+// MuscleGroupResponse is the public representation of a muscle group
+type MuscleGroupResponse struct {
+	Slug    string   `json:"slug"`
+	Name    string   `json:"name"`
+	Region  string   `json:"region"`
+	Aliases []string `json:"aliases"`
+}
+
+func NewMuscleGroupResponse(mg models.MuscleGroup) MuscleGroupResponse {
+	aliases := mg.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+	return MuscleGroupResponse{Slug: mg.Slug, Name: mg.Name, Region: mg.Region, Aliases: aliases}
+}
+
+func NewMuscleGroupResponses(groups []models.MuscleGroup) []MuscleGroupResponse {
+	responses := make([]MuscleGroupResponse, 0, len(groups))
+	for _, mg := range groups {
+		responses = append(responses, NewMuscleGroupResponse(mg))
+	}
+	return responses
+}
+
+// EquipmentResponse is the public representation of a piece of equipment
+type EquipmentResponse struct {
+	Slug    string   `json:"slug"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+func NewEquipmentResponse(e models.Equipment) EquipmentResponse {
+	aliases := e.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+	return EquipmentResponse{Slug: e.Slug, Name: e.Name, Aliases: aliases}
+}
+
+func NewEquipmentResponses(equipment []models.Equipment) []EquipmentResponse {
+	responses := make([]EquipmentResponse, 0, len(equipment))
+	for _, e := range equipment {
+		responses = append(responses, NewEquipmentResponse(e))
+	}
+	return responses
+}
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+	"workout-api/internal/models"
+)
+
+// CreateUserRequest is the body accepted by POST /users
+type CreateUserRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+func (r CreateUserRequest) ToModel() models.User {
+	return models.User{Name: r.Name, Email: r.Email, Password: r.Password}
+}
+
+// UpdateUserRequest is the body accepted by PUT /users/:id. Password is
+// optional, an empty value keeps the current one.
+type UpdateUserRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"omitempty,min=8,max=72"`
+}
+
+func (r UpdateUserRequest) ToModel(id int) models.User {
+	return models.User{ID: id, Name: r.Name, Email: r.Email, Password: r.Password}
+}
+
+// UserResponse is the public representation of a user. It deliberately has
+// no password field so secrets cannot be serialized by accident.
+type UserResponse struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewUserResponse(user models.User) UserResponse {
+	return UserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+func NewUserResponses(users []models.User) []UserResponse {
+	responses := make([]UserResponse, 0, len(users))
+	for _, user := range users {
+		responses = append(responses, NewUserResponse(user))
+	}
+	return responses
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+	"workout-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUserResponse_OmitsPassword(t *testing.T) {
+	user := models.User{
+		ID:        1,
+		Name:      "John Doe",
+		Email:     "john@example.com",
+		Password:  "$2a$10$secrethash",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	body, err := json.Marshal(NewUserResponse(user))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(body), "password")
+	assert.NotContains(t, string(body), "secrethash")
+	assert.Contains(t, string(body), `"email":"john@example.com"`)
+}
+
+func TestUserModel_PasswordNotSerialized(t *testing.T) {
+	body, err := json.Marshal(models.User{ID: 1, Password: "secret"})
+	assert.NoError(t, err)
+	assert.NotContains(t, string(body), "secret")
+}
+
+func TestNewUserResponses_EmptySlice(t *testing.T) {
+	body, err := json.Marshal(NewUserResponses(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", string(body))
+}
+
+func TestCreateUserRequest_ToModel(t *testing.T) {
+	req := CreateUserRequest{Name: "John", Email: "john@example.com", Password: "password123"}
+	assert.Equal(t, models.User{Name: "John", Email: "john@example.com", Password: "password123"}, req.ToModel())
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"mime"
+	"net/http"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/archive"
+	"workout-api/internal/dto"
+	"workout-api/internal/services"
+)
+
+// maxArchiveSize bounds uploaded account archives, which hold every set
+// ever logged
+const maxArchiveSize = 50 << 20
+
+type AccountHandler struct {
+	accountService *services.AccountService
+}
+
+func NewAccountHandler(accountService *services.AccountService) *AccountHandler {
+	return &AccountHandler{accountService: accountService}
+}
+
+// ExportAccount downloads the caller's account as a JSON archive
+func (h *AccountHandler) ExportAccount(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	a, err := h.accountService.ExportAccount(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="account-%s.json"`, a.ExportedAt.Format("2006-01-02")))
+	c.JSON(http.StatusOK, a)
+}
+
+// ImportAccount restores an account archive sent as application/json into
+// the caller's account
+func (h *AccountHandler) ImportAccount(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(c.ContentType()); mediaType != "application/json" {
+		_ = c.Error(apperrors.Invalid("Content-Type", "archive must be sent as application/json"))
+		return
+	}
+
+	a, err := archive.Read(http.MaxBytesReader(c.Writer, c.Request.Body, maxArchiveSize))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	report, err := h.accountService.ImportAccount(c.Request.Context(), viewer, a)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAccountImportResponse(report))
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/dto"
+	"workout-api/internal/models"
+	"workout-api/internal/services"
+)
+
+// maxActivitySize bounds uploaded activity files; a day-long ride recorded
+// every second stays well below it
+const maxActivitySize = 50 << 20
+
+type ActivityHandler struct {
+	activityService *services.ActivityService
+}
+
+func NewActivityHandler(activityService *services.ActivityService) *ActivityHandler {
+	return &ActivityHandler{activityService: activityService}
+}
+
+// UploadActivity logs the GPX, TCX or FIT file sent as the raw body as an
+// activity of the cardio exercise given by ?exercise_id=. The format is
+// detected from the content unless ?format= names it.
+func (h *ActivityHandler) UploadActivity(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	exerciseID, ok := queryInt(c, "exercise_id")
+	if !ok {
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxActivitySize))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		_ = c.Error(apperrors.Invalid("file", fmt.Sprintf("activity file exceeds %d MB", maxActivitySize>>20)))
+		return
+	}
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	activity, err := h.activityService.UploadActivity(c.Request.Context(), viewer, exerciseID, c.Query("format"), data)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, activity)
+}
+
+func (h *ActivityHandler) GetActivity(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := paramID(c, "id", "activity")
+	if !ok {
+		return
+	}
+
+	activity, err := h.activityService.GetActivity(c.Request.Context(), userID, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, activity)
+}
+
+// ListMyActivities returns a page of the caller's activities, newest first
+// by default, filterable with ?sport=
+func (h *ActivityHandler) ListMyActivities(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	page, err := h.activityService.ListActivities(c.Request.Context(), userID, c.Query("sport"), pageQuery(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.PageResponse[models.Activity]{
+		Data:       page.Items,
+		Limit:      page.Limit,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	})
+}
+
+// GetActivityPoints returns every track point of one of the caller's
+// activities in recorded order
+func (h *ActivityHandler) GetActivityPoints(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := paramID(c, "id", "activity")
+	if !ok {
+		return
+	}
+
+	points, err := h.activityService.ActivityPoints(c.Request.Context(), userID, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, points)
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"workout-api/internal/models"
+	"workout-api/internal/services"
+)
+
+type AnalyticsHandler struct {
+	analyticsService *services.AnalyticsService
+}
+
+func NewAnalyticsHandler(analyticsService *services.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{analyticsService: analyticsService}
+}
+
+// e1rmQuery collects the exercise path parameter and the ?formula=,
+// ?bucket=, ?from= and ?to= query parameters
+func e1rmQuery(c *gin.Context) (models.E1RMQuery, bool) {
+	id, ok := paramID(c, "id", "exercise")
+	if !ok {
+		return models.E1RMQuery{}, false
+	}
+	q := models.E1RMQuery{ExerciseID: id, Formula: c.Query("formula"), Bucket: c.Query("bucket")}
+	if q.From, ok = queryTime(c, "from"); !ok {
+		return models.E1RMQuery{}, false
+	}
+	if q.To, ok = queryTime(c, "to"); !ok {
+		return models.E1RMQuery{}, false
+	}
+	return q, true
+}
+
+// GetE1RMSeries returns the caller's best estimated one-rep max of an
+// exercise per ?bucket= of day, week or month
+func (h *AnalyticsHandler) GetE1RMSeries(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	q, ok := e1rmQuery(c)
+	if !ok {
+		return
+	}
+
+	series, err := h.analyticsService.E1RMSeries(c.Request.Context(), viewer, q)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// ListE1RMSets returns the caller's sets of an exercise with their
+// estimated one-rep max, newest first, up to ?limit=
+func (h *AnalyticsHandler) ListE1RMSets(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	q, ok := e1rmQuery(c)
+	if !ok {
+		return
+	}
+	limit, ok := queryInt(c, "limit")
+	if !ok {
+		return
+	}
+
+	sets, err := h.analyticsService.E1RMSets(c.Request.Context(), viewer, q, limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": sets})
+}
+
+// GetMuscleVolume returns the caller's weekly sets, hard sets and tonnage
+// per muscle group between ?from= and ?to=. Hard sets start at ?rpe= and
+// secondary muscles count as ?secondary_weight= of a set.
+func (h *AnalyticsHandler) GetMuscleVolume(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var q models.MuscleVolumeQuery
+	if q.From, ok = queryTime(c, "from"); !ok {
+		return
+	}
+	if q.To, ok = queryTime(c, "to"); !ok {
+		return
+	}
+	if q.RPEThreshold, ok = queryFloat(c, "rpe"); !ok {
+		return
+	}
+	if q.SecondaryWeight, ok = queryFloat(c, "secondary_weight"); !ok {
+		return
+	}
+
+	report, err := h.analyticsService.MuscleVolume(c.Request.Context(), userID, q)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
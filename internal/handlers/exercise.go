@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"mime"
+	"net/http"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/catalog"
+	"workout-api/internal/dto"
+	"workout-api/internal/models"
+	"workout-api/internal/services"
+)
+
+// maxCatalogSize bounds uploaded catalog files
+const maxCatalogSize = 5 << 20
+
+type ExerciseHandler struct {
+	exerciseService *services.ExerciseService
+}
+
+func NewExerciseHandler(exerciseService *services.ExerciseService) *ExerciseHandler {
+	return &ExerciseHandler{exerciseService: exerciseService}
+}
+
+func (h *ExerciseHandler) CreateExercise(c *gin.Context) {
+	var req dto.ExerciseRequest
+
+	// Bind and validate JSON request
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	exercise, err := h.exerciseService.CreateExercise(c.Request.Context(), viewer, req.ToModel(0))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewExerciseResponse(exercise))
+}
+
+func (h *ExerciseHandler) GetExercise(c *gin.Context) {
+	// get exercise ID from URL parameter
+	id, ok := paramID(c, "id", "exercise")
+	if !ok {
+		return
+	}
+
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	exercise, err := h.exerciseService.GetExerciseByID(c.Request.Context(), viewer, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewExerciseResponse(exercise))
+}
+
+// ListExercises returns a page of the exercises visible to the caller,
+// filterable with ?name=, ?muscle_group= (primary muscles only unless
+// ?include_secondary=true), ?equipment_type=, ?visibility= and ?mine=true
+func (h *ExerciseHandler) ListExercises(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	filter := models.ExerciseFilter{
+		Name:             c.Query("name"),
+		MuscleGroup:      c.Query("muscle_group"),
+		IncludeSecondary: c.Query("include_secondary") == "true",
+		EquipmentType:    c.Query("equipment_type"),
+		Visibility:       c.Query("visibility"),
+		OwnedOnly:        c.Query("mine") == "true",
+	}
+
+	page, err := h.exerciseService.ListExercises(c.Request.Context(), viewer, filter, pageQuery(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewPageResponse(page, dto.NewExerciseResponses))
+}
+
+// SearchExercises ranks the catalog against ?q=, accepting the same
+// muscle_group and equipment_type filters as the list endpoint
+func (h *ExerciseHandler) SearchExercises(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	limit, ok := queryInt(c, "limit")
+	if !ok {
+		return
+	}
+
+	search := models.ExerciseSearch{
+		Query:         c.Query("q"),
+		MuscleGroup:   c.Query("muscle_group"),
+		EquipmentType: c.Query("equipment_type"),
+		Limit:         limit,
+	}
+
+	results, err := h.exerciseService.SearchExercises(c.Request.Context(), viewer, search)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewExerciseSearchResponses(results)})
+}
+
+func (h *ExerciseHandler) UpdateExercise(c *gin.Context) {
+	// get exercise ID from URL parameter
+	id, ok := paramID(c, "id", "exercise")
+	if !ok {
+		return
+	}
+
+	var req dto.ExerciseRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	// The URL is authoritative for which exercise is being updated
+	if err := h.exerciseService.UpdateExercise(c.Request.Context(), viewer, req.ToModel(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "exercise updated successfully"})
+}
+
+func (h *ExerciseHandler) DeleteExercise(c *gin.Context) {
+	// get exercise ID from URL parameter
+	id, ok := paramID(c, "id", "exercise")
+	if !ok {
+		return
+	}
+
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	if err := h.exerciseService.DeleteExercise(c.Request.Context(), viewer, id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "exercise deleted successfully"})
+}
+
+// PromoteExercise moves a user's exercise into the global catalog
+func (h *ExerciseHandler) PromoteExercise(c *gin.Context) {
+	id, ok := paramID(c, "id", "exercise")
+	if !ok {
+		return
+	}
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	exercise, err := h.exerciseService.PromoteExercise(c.Request.Context(), viewer, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewExerciseResponse(exercise))
+}
+
+// ImportCatalog upserts the uploaded catalog file into the global catalog.
+// The body is JSON or CSV according to its Content-Type; ?dry_run=true
+// only validates it.
+func (h *ExerciseHandler) ImportCatalog(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	var format string
+	mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+	switch mediaType {
+	case "application/json":
+		format = catalog.FormatJSON
+	case "text/csv":
+		format = catalog.FormatCSV
+	default:
+		_ = c.Error(apperrors.Invalid("Content-Type", "catalog must be sent as application/json or text/csv"))
+		return
+	}
+
+	parsed, err := catalog.Parse(format, http.MaxBytesReader(c.Writer, c.Request.Body, maxCatalogSize))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	report, err := h.exerciseService.ImportCatalog(c.Request.Context(), viewer, parsed, c.Query("dry_run") == "true")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewCatalogImportResponse(report))
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"sync/atomic"
+	"time"
+	"workout-api/internal/migrate"
+)
+
+// readinessCheckTimeout bounds each dependency check so a hung database
+// fails the probe instead of blocking it
+const readinessCheckTimeout = 2 * time.Second
+
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+)
+
+// HealthHandler serves the probe endpoints used by orchestrators
+type HealthHandler struct {
+	db       *sql.DB
+	migrator *migrate.Migrator
+	ready    atomic.Bool
+}
+
+// NewHealthHandler returns a handler that reports ready until SetReady(false)
+func NewHealthHandler(db *sql.DB, migrator *migrate.Migrator) *HealthHandler {
+	h := &HealthHandler{db: db, migrator: migrator}
+	h.ready.Store(true)
+	return h
+}
+
+// SetReady flips the readiness probe; the server marks itself not ready
+// before shutting down so new traffic is routed elsewhere
+func (h *HealthHandler) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+type readinessResponse struct {
+	Status string                     `json:"status"`
+	Checks map[string]dependencyCheck `json:"checks,omitempty"`
+}
+
+type dependencyCheck struct {
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+	Details   any    `json:"details,omitempty"`
+}
+
+type poolStats struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMS     int64 `json:"wait_duration_ms"`
+}
+
+type migrationStats struct {
+	CurrentVersion   int64 `json:"current_version"`
+	LatestVersion    int64 `json:"latest_version"`
+	Pending          int   `json:"pending"`
+	ChecksumMismatch int   `json:"checksum_mismatch"`
+}
+
+// Healthz is the liveness probe: it only confirms the process can serve
+// HTTP, so a database outage doesn't get the pod restarted
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": statusOK})
+}
+
+// Readyz reports whether the instance should receive traffic, with a
+// per-dependency breakdown
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	if !h.ready.Load() {
+		c.JSON(http.StatusServiceUnavailable, readinessResponse{Status: "shutting_down"})
+		return
+	}
+
+	checks := map[string]dependencyCheck{
+		"database": h.checkDatabase(c.Request.Context()),
+	}
+	// Migration status needs a working connection, so skip it when the
+	// database check already failed
+	if checks["database"].Status == statusOK {
+		checks["migrations"] = h.checkMigrations(c.Request.Context())
+	}
+
+	resp := readinessResponse{Status: statusOK, Checks: checks}
+	code := http.StatusOK
+	for _, check := range checks {
+		if check.Status != statusOK {
+			resp.Status = statusUnavailable
+			code = http.StatusServiceUnavailable
+		}
+	}
+
+	c.JSON(code, resp)
+}
+
+func (h *HealthHandler) checkDatabase(ctx context.Context) dependencyCheck {
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := h.db.PingContext(ctx)
+	stats := h.db.Stats()
+
+	check := dependencyCheck{
+		Status:    statusOK,
+		LatencyMS: time.Since(start).Milliseconds(),
+		Details: poolStats{
+			MaxOpenConnections: stats.MaxOpenConnections,
+			OpenConnections:    stats.OpenConnections,
+			InUse:              stats.InUse,
+			Idle:               stats.Idle,
+			WaitCount:          stats.WaitCount,
+			WaitDurationMS:     stats.WaitDuration.Milliseconds(),
+		},
+	}
+	if err != nil {
+		check.Status = statusUnavailable
+		check.Error = err.Error()
+	}
+	return check
+}
+
+// checkMigrations fails readiness while the schema is behind the binary or
+// an applied migration was edited after the fact
+func (h *HealthHandler) checkMigrations(ctx context.Context) dependencyCheck {
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	statuses, err := h.migrator.Status(ctx)
+	check := dependencyCheck{Status: statusOK, LatencyMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		check.Status = statusUnavailable
+		check.Error = err.Error()
+		return check
+	}
+
+	stats := migrationStats{LatestVersion: h.migrator.Latest()}
+	for _, s := range statuses {
+		switch {
+		case !s.Applied:
+			stats.Pending++
+		case s.ChecksumMismatch:
+			stats.ChecksumMismatch++
+		}
+		if s.Applied && s.Version > stats.CurrentVersion {
+			stats.CurrentVersion = s.Version
+		}
+	}
+	check.Details = stats
+
+	switch {
+	case stats.Pending > 0:
+		check.Status = statusUnavailable
+		check.Error = "pending migrations"
+	case stats.ChecksumMismatch > 0:
+		check.Status = statusUnavailable
+		check.Error = "applied migrations were modified"
+	}
+	return check
+}
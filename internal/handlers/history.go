@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"mime"
+	"net/http"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/dto"
+	"workout-api/internal/history"
+	"workout-api/internal/services"
+)
+
+// maxHistorySize bounds uploaded history exports, which list every set
+// ever logged
+const maxHistorySize = 20 << 20
+
+type HistoryHandler struct {
+	historyService *services.HistoryService
+}
+
+func NewHistoryHandler(historyService *services.HistoryService) *HistoryHandler {
+	return &HistoryHandler{historyService: historyService}
+}
+
+// ImportHistory imports a Strong or Hevy CSV export sent as text/csv into
+// the caller's workouts. Strong exports carry no units, so ?weight_unit=
+// (kg or lb) and ?distance_unit= (km or mi) say which the file uses.
+func (h *HistoryHandler) ImportHistory(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(c.ContentType()); mediaType != "text/csv" {
+		_ = c.Error(apperrors.Invalid("Content-Type", "history must be sent as text/csv"))
+		return
+	}
+
+	units := history.Units{
+		Weight:   c.DefaultQuery("weight_unit", history.DefaultUnits.Weight),
+		Distance: c.DefaultQuery("distance_unit", history.DefaultUnits.Distance),
+	}
+	export, err := history.Parse(http.MaxBytesReader(c.Writer, c.Request.Body, maxHistorySize), units)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	report, err := h.historyService.ImportHistory(c.Request.Context(), viewer, export)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewHistoryImportResponse(report))
+}
@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"workout-api/internal/dto"
+	"workout-api/internal/models"
+	"workout-api/internal/services"
+)
+
+type ProgramHandler struct {
+	programService *services.ProgramService
+}
+
+func NewProgramHandler(programService *services.ProgramService) *ProgramHandler {
+	return &ProgramHandler{programService: programService}
+}
+
+func (h *ProgramHandler) CreateProgram(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ProgramRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	program, err := h.programService.CreateProgram(c.Request.Context(), viewer, req.ToModel(0))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, program)
+}
+
+func (h *ProgramHandler) GetProgram(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	id, ok := paramID(c, "id", "program")
+	if !ok {
+		return
+	}
+
+	program, err := h.programService.GetProgram(c.Request.Context(), viewer, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, program)
+}
+
+// ListPrograms returns a page of the programs visible to the caller,
+// filterable with ?name= and ?mine=true
+func (h *ProgramHandler) ListPrograms(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	filter := models.ProgramFilter{
+		Name:      c.Query("name"),
+		OwnedOnly: c.Query("mine") == "true",
+	}
+
+	page, err := h.programService.ListPrograms(c.Request.Context(), viewer, filter, pageQuery(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.PageResponse[models.Program]{
+		Data:       page.Items,
+		Limit:      page.Limit,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	})
+}
+
+func (h *ProgramHandler) UpdateProgram(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	id, ok := paramID(c, "id", "program")
+	if !ok {
+		return
+	}
+
+	var req dto.ProgramRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.programService.UpdateProgram(c.Request.Context(), viewer, req.ToModel(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "program updated successfully"})
+}
+
+func (h *ProgramHandler) DeleteProgram(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	id, ok := paramID(c, "id", "program")
+	if !ok {
+		return
+	}
+
+	if err := h.programService.DeleteProgram(c.Request.Context(), viewer, id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "program deleted successfully"})
+}
+
+// Enroll starts the caller on the program's first day
+func (h *ProgramHandler) Enroll(c *gin.Context) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	id, ok := paramID(c, "id", "program")
+	if !ok {
+		return
+	}
+
+	var req dto.EnrollRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return
+	}
+
+	enrollment, err := h.programService.Enroll(c.Request.Context(), viewer, id, req.ToModel())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, enrollment)
+}
+
+// GetEnrollment returns the caller's active enrollment
+func (h *ProgramHandler) GetEnrollment(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.programService.CurrentEnrollment(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// LeaveProgram cancels the caller's active enrollment
+func (h *ProgramHandler) LeaveProgram(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.programService.LeaveProgram(c.Request.Context(), userID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "left program successfully"})
+}
+
+// GetToday returns the caller's next program session with its planned sets
+func (h *ProgramHandler) GetToday(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.programService.Today(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// StartToday opens a workout for the caller's next program session. The
+// body is optional.
+func (h *ProgramHandler) StartToday(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.StartRoutineRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return
+	}
+
+	workout, err := h.programService.StartToday(c.Request.Context(), userID, req.StartedAt)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, workout)
+}
+
+// Advance moves the caller on to their next program session, applying
+// progression rules
+func (h *ProgramHandler) Advance(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.programService.Advance(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"workout-api/internal/dto"
+	"workout-api/internal/models"
+	"workout-api/internal/services"
+)
+
+type RecordHandler struct {
+	recordService *services.RecordService
+}
+
+func NewRecordHandler(recordService *services.RecordService) *RecordHandler {
+	return &RecordHandler{recordService: recordService}
+}
+
+// ListMyRecords returns a page of the caller's personal records,
+// filterable with ?exercise_id= and ?category=
+func (h *RecordHandler) ListMyRecords(c *gin.Context) {
+	exerciseID, ok := queryInt(c, "exercise_id")
+	if !ok {
+		return
+	}
+	h.listRecords(c, models.RecordFilter{ExerciseID: exerciseID, Category: c.Query("category")})
+}
+
+// ListExerciseRecords returns a page of the caller's personal records for
+// one exercise, filterable with ?category=
+func (h *RecordHandler) ListExerciseRecords(c *gin.Context) {
+	id, ok := paramID(c, "id", "exercise")
+	if !ok {
+		return
+	}
+	h.listRecords(c, models.RecordFilter{ExerciseID: id, Category: c.Query("category")})
+}
+
+func (h *RecordHandler) listRecords(c *gin.Context, filter models.RecordFilter) {
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+
+	page, err := h.recordService.ListRecords(c.Request.Context(), viewer, filter, pageQuery(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.PageResponse[models.PersonalRecord]{
+		Data:       page.Items,
+		Limit:      page.Limit,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	})
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/middleware"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+)
+
+func init() {
+	// Report validation failures using JSON field names rather than Go ones
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// bindJSON binds and validates the request body, attaching a validation
+// error with per-field details when it fails
+func bindJSON(c *gin.Context, obj any) bool {
+	err := c.ShouldBindJSON(obj)
+	if err == nil {
+		return true
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]apperrors.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, apperrors.FieldError{Field: fe.Field(), Message: validationMessage(fe)})
+		}
+		_ = c.Error(apperrors.Validation(fields...))
+		return false
+	}
+
+	_ = c.Error(apperrors.Invalid("body", "request body is not valid JSON: "+err.Error()))
+	return false
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fe.Field() + " is required"
+	case "email":
+		return fe.Field() + " must be a valid email address"
+	case "min":
+		return fmt.Sprintf("%s must be at least %s characters", fe.Field(), fe.Param())
+	default:
+		return fmt.Sprintf("%s failed the %s check", fe.Field(), fe.Tag())
+	}
+}
+
+// paramID parses a positive integer path parameter
+func paramID(c *gin.Context, name, resource string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil || id <= 0 {
+		_ = c.Error(apperrors.Invalid(name, "invalid "+resource+" ID"))
+		return 0, false
+	}
+	return id, true
+}
+
+// currentUserID returns the authenticated user's ID, attaching an
+// unauthorized error when the request did not pass through the auth middleware
+func currentUserID(c *gin.Context) (int, bool) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		_ = c.Error(apperrors.Unauthorized("authentication required"))
+	}
+	return userID, ok
+}
+
+// currentViewer returns who the request acts for, for visibility and
+// ownership checks
+func currentViewer(c *gin.Context) (models.Viewer, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return models.Viewer{}, false
+	}
+	return models.Viewer{UserID: userID, Role: middleware.CurrentUserRole(c)}, true
+}
+
+// pageQuery collects the pagination query parameters shared by list endpoints
+func pageQuery(c *gin.Context) pagination.Query {
+	return pagination.Query{
+		Limit:  c.Query("limit"),
+		Sort:   c.Query("sort"),
+		Cursor: c.Query("cursor"),
+	}
+}
+
+// queryInt parses an optional integer query parameter, returning zero when
+// it is absent
+func queryInt(c *gin.Context, name string) (int, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		_ = c.Error(apperrors.Invalid(name, name+" must be an integer"))
+		return 0, false
+	}
+	return n, true
+}
+
+// queryFloat parses an optional decimal query parameter, returning nil when
+// it is absent
+func queryFloat(c *gin.Context, name string) (*float64, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		_ = c.Error(apperrors.Invalid(name, name+" must be a number"))
+		return nil, false
+	}
+	return &f, true
+}
+
+// queryTime parses an optional RFC 3339 timestamp or YYYY-MM-DD date query
+// parameter, attaching a validation error when it is malformed
+func queryTime(c *gin.Context, name string) (*time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, true
+		}
+	}
+	_ = c.Error(apperrors.Invalid(name, name+" must be an RFC 3339 timestamp or a YYYY-MM-DD date"))
+	return nil, false
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"workout-api/internal/dto"
+	"workout-api/internal/models"
+	"workout-api/internal/services"
+)
+
+type RoutineHandler struct {
+	routineService *services.RoutineService
+}
+
+func NewRoutineHandler(routineService *services.RoutineService) *RoutineHandler {
+	return &RoutineHandler{routineService: routineService}
+}
+
+func (h *RoutineHandler) CreateRoutine(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.RoutineRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	routine, err := h.routineService.CreateRoutine(c.Request.Context(), userID, req.ToModel(0))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, routine)
+}
+
+func (h *RoutineHandler) GetRoutine(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := paramID(c, "id", "routine")
+	if !ok {
+		return
+	}
+
+	routine, err := h.routineService.GetRoutine(c.Request.Context(), userID, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, routine)
+}
+
+// ListMyRoutines returns a page of the authenticated user's routines
+func (h *RoutineHandler) ListMyRoutines(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	page, err := h.routineService.ListRoutines(c.Request.Context(), userID, pageQuery(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.PageResponse[models.Routine]{
+		Data:       page.Items,
+		Limit:      page.Limit,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	})
+}
+
+func (h *RoutineHandler) UpdateRoutine(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := paramID(c, "id", "routine")
+	if !ok {
+		return
+	}
+
+	var req dto.RoutineRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.routineService.UpdateRoutine(c.Request.Context(), userID, req.ToModel(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "routine updated successfully"})
+}
+
+func (h *RoutineHandler) DeleteRoutine(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := paramID(c, "id", "routine")
+	if !ok {
+		return
+	}
+
+	if err := h.routineService.DeleteRoutine(c.Request.Context(), userID, id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "routine deleted successfully"})
+}
+
+// StartRoutine opens a workout from the routine with its sets planned from
+// the user's last performance. The body is optional.
+func (h *RoutineHandler) StartRoutine(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := paramID(c, "id", "routine")
+	if !ok {
+		return
+	}
+
+	var req dto.StartRoutineRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return
+	}
+
+	workout, err := h.routineService.StartRoutine(c.Request.Context(), userID, id, req.StartedAt)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, workout)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"workout-api/internal/dto"
+	"workout-api/internal/services"
+)
+
+// TaxonomyHandler serves the muscle group and equipment browse endpoints
+type TaxonomyHandler struct {
+	taxonomyService *services.TaxonomyService
+}
+
+func NewTaxonomyHandler(taxonomyService *services.TaxonomyService) *TaxonomyHandler {
+	return &TaxonomyHandler{taxonomyService: taxonomyService}
+}
+
+func (h *TaxonomyHandler) ListMuscleGroups(c *gin.Context) {
+	groups, err := h.taxonomyService.ListMuscleGroups(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewMuscleGroupResponses(groups)})
+}
+
+// GetMuscleGroup accepts a slug or any alias, e.g. /muscle-groups/pecs
+func (h *TaxonomyHandler) GetMuscleGroup(c *gin.Context) {
+	mg, err := h.taxonomyService.GetMuscleGroup(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewMuscleGroupResponse(mg))
+}
+
+func (h *TaxonomyHandler) ListEquipment(c *gin.Context) {
+	equipment, err := h.taxonomyService.ListEquipment(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewEquipmentResponses(equipment)})
+}
+
+// GetEquipment accepts a slug or any alias, e.g. /equipment/db
+func (h *TaxonomyHandler) GetEquipment(c *gin.Context) {
+	equipment, err := h.taxonomyService.GetEquipment(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewEquipmentResponse(equipment))
+}
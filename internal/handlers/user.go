@@ -3,7 +3,8 @@ package handlers
 import (
 	"github.com/gin-gonic/gin"
 	"net/http"
-	"strconv"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/dto"
 	"workout-api/internal/models"
 	"workout-api/internal/services"
 )
@@ -17,17 +18,16 @@ func NewUserHandler(userService *services.UserService) *UserHandler {
 }
 
 func (h *UserHandler) CreateUser(c *gin.Context) {
-	var user models.User
+	var req dto.CreateUserRequest
 
 	// Bind and validate JSON request
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	// Create user using service
-	if err := h.userService.CreateUser(user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.userService.CreateUser(c.Request.Context(), req.ToModel()); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
@@ -37,49 +37,104 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 
 func (h *UserHandler) GetUser(c *gin.Context) {
 	// get user ID from URL parameter
-	idStr := c.Param("id")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+	id, ok := paramID(c, "id", "user")
+	if !ok {
+		return
+	}
+
+	// Users may only look up their own account; admins may look up any
+	viewer, ok := currentViewer(c)
+	if !ok {
+		return
+	}
+	if viewer.UserID != id && !viewer.IsAdmin() {
+		_ = c.Error(apperrors.Forbidden("you can only view your own account"))
 		return
 	}
 
-	user, err := h.userService.GetUserByID(id)
+	user, err := h.userService.GetUserByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, dto.NewUserResponse(user))
 }
 
-func (h *UserHandler) GetAllUsers(c *gin.Context) {
-	users, err := h.userService.GetAllUsers()
+// ListUsers returns a page of users, filterable with ?name= and ?email=. Only
+// admins may list users.
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	filter := models.UserFilter{
+		Name:  c.Query("name"),
+		Email: c.Query("email"),
+	}
+
+	page, err := h.userService.ListUsers(c.Request.Context(), filter, pageQuery(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get users"})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, users)
+	c.JSON(http.StatusOK, dto.NewPageResponse(page, dto.NewUserResponses))
+}
+
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	// get user ID from URL parameter
+	id, ok := paramID(c, "id", "user")
+	if !ok {
+		return
+	}
+
+	// Users may only modify their own account
+	if !h.authorizeSelf(c, id) {
+		return
+	}
+
+	var req dto.UpdateUserRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.userService.UpdateUser(c.Request.Context(), req.ToModel(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user updated successfully"})
 }
 
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	// get user ID from URL parameter
-	idStr := c.Param("id")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+	id, ok := paramID(c, "id", "user")
+	if !ok {
 		return
 	}
 
-	if err := h.userService.DeleteUser(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete user"})
+	// Users may only modify their own account
+	if !h.authorizeSelf(c, id) {
+		return
+	}
+
+	if err := h.userService.DeleteUser(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "user deleted successfully"})
 }
 
+func (h *UserHandler) authorizeSelf(c *gin.Context, id int) bool {
+	currentID, ok := currentUserID(c)
+	if !ok {
+		return false
+	}
+	if currentID != id {
+		_ = c.Error(apperrors.Forbidden("you can only modify your own account"))
+		return false
+	}
+	return true
+}
+
 // Ping handler for health check
 func Ping(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "pong"})
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/dto"
+	"workout-api/internal/models"
+	"workout-api/internal/services"
+)
+
+type WorkoutHandler struct {
+	workoutService *services.WorkoutService
+}
+
+func NewWorkoutHandler(workoutService *services.WorkoutService) *WorkoutHandler {
+	return &WorkoutHandler{workoutService: workoutService}
+}
+
+func (h *WorkoutHandler) StartWorkout(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var workout models.Workout
+	if !bindJSON(c, &workout) {
+		return
+	}
+
+	// Workouts always belong to the authenticated user
+	workout.UserID = userID
+
+	created, err := h.workoutService.StartWorkout(c.Request.Context(), workout)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *WorkoutHandler) GetWorkout(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := paramID(c, "id", "workout")
+	if !ok {
+		return
+	}
+
+	workout, err := h.workoutService.GetWorkoutByID(c.Request.Context(), userID, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, workout)
+}
+
+// ListMyWorkouts returns a page of the authenticated user's workouts, newest
+// first by default, filterable with ?from=, ?to= and ?status=active|finished
+func (h *WorkoutHandler) ListMyWorkouts(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var filter models.WorkoutFilter
+	if filter.StartedFrom, ok = queryTime(c, "from"); !ok {
+		return
+	}
+	if filter.StartedTo, ok = queryTime(c, "to"); !ok {
+		return
+	}
+	switch status := c.Query("status"); status {
+	case "":
+	case "active", "finished":
+		finished := status == "finished"
+		filter.Finished = &finished
+	default:
+		_ = c.Error(apperrors.Invalid("status", "status must be active or finished"))
+		return
+	}
+
+	page, err := h.workoutService.ListWorkouts(c.Request.Context(), userID, filter, pageQuery(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.PageResponse[models.Workout]{
+		Data:       page.Items,
+		Limit:      page.Limit,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	})
+}
+
+func (h *WorkoutHandler) LogSet(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	workoutID, ok := paramID(c, "id", "workout")
+	if !ok {
+		return
+	}
+
+	var set models.WorkoutSet
+	if !bindJSON(c, &set) {
+		return
+	}
+	set.WorkoutID = workoutID
+
+	logged, err := h.workoutService.LogSet(c.Request.Context(), userID, set)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, logged)
+}
+
+func (h *WorkoutHandler) DeleteSet(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	workoutID, ok := paramID(c, "id", "workout")
+	if !ok {
+		return
+	}
+	setID, ok := paramID(c, "setId", "set")
+	if !ok {
+		return
+	}
+
+	if err := h.workoutService.DeleteSet(c.Request.Context(), userID, workoutID, setID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "set deleted successfully"})
+}
+
+func (h *WorkoutHandler) FinishWorkout(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := paramID(c, "id", "workout")
+	if !ok {
+		return
+	}
+
+	workout, err := h.workoutService.FinishWorkout(c.Request.Context(), userID, id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, workout)
+}
+
+func (h *WorkoutHandler) DeleteWorkout(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	id, ok := paramID(c, "id", "workout")
+	if !ok {
+		return
+	}
+
+	if err := h.workoutService.DeleteWorkout(c.Request.Context(), userID, id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "workout deleted successfully"})
+}
@@ -0,0 +1,458 @@
+// Package history reads workout history exported from other training apps.
+// Strong and Hevy CSV exports are supported; both list one set per row
+// with the workout repeated on every row, so rows are grouped back into
+// workouts here and exercise names are normalized for matching.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"workout-api/internal/apperrors"
+)
+
+// Supported export sources
+const (
+	SourceStrong = "strong"
+	SourceHevy   = "hevy"
+)
+
+// Units an export may record weights and distances in. Stored weights are
+// kilograms and distances meters.
+const (
+	UnitKilograms  = "kg"
+	UnitPounds     = "lb"
+	UnitKilometers = "km"
+	UnitMiles      = "mi"
+)
+
+const (
+	kilogramsPerPound = 0.45359237
+	metersPerMile     = 1609.344
+)
+
+// Units says how an export records weights and distances where the file
+// itself does not. Strong writes whatever the user had configured; Hevy
+// names the unit in its column headers.
+type Units struct {
+	Weight   string
+	Distance string
+}
+
+// DefaultUnits are assumed when the uploader does not say otherwise
+var DefaultUnits = Units{Weight: UnitKilograms, Distance: UnitKilometers}
+
+// Export is a parsed history file
+type Export struct {
+	Source string
+	// Rows counts the data rows read, excluding the header
+	Rows     int
+	Workouts []Workout
+	// Skipped lists rows that could not be turned into a set
+	Skipped []Issue
+}
+
+// Workout is one session from an export. Key identifies it within its
+// source so re-uploading the same file can recognise it.
+type Workout struct {
+	Key        string
+	Name       string
+	Notes      string
+	StartedAt  time.Time
+	FinishedAt *time.Time
+	Sets       []Set
+}
+
+// Set is one logged set. Exercise is the name used by the source app.
+type Set struct {
+	// Row is the line number of the set in its file
+	Row             int
+	Exercise        string
+	Reps            int
+	Weight          float64
+	RPE             *float64
+	RestSeconds     *int
+	DurationSeconds *int
+	DistanceMeters  *float64
+}
+
+// Issue explains why a row was not imported
+type Issue struct {
+	Row      int
+	Exercise string
+	Reason   string
+}
+
+// Parse reads a Strong or Hevy CSV export, telling them apart by their
+// header row. Problems with individual rows are reported in Skipped rather
+// than failing the file.
+func Parse(r io.Reader, units Units) (Export, error) {
+	if err := units.validate(); err != nil {
+		return Export{}, err
+	}
+	data, err := io.ReadAll(r)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return Export{}, apperrors.Invalid("file", fmt.Sprintf("history CSV exceeds %d MB", tooLarge.Limit>>20))
+	}
+	if err != nil {
+		return Export{}, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return Export{}, apperrors.Invalid("file", "history CSV has no header row")
+	}
+	if err != nil {
+		return Export{}, apperrors.Invalid("file", "malformed history CSV: "+err.Error())
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	p := parser{units: units, columns: columns, decimalComma: reader.Comma == ';', index: map[string]int{}}
+	switch {
+	case p.has("date", "workout name", "exercise name", "reps"):
+		p.export.Source = SourceStrong
+	case p.has("title", "start_time", "exercise_title", "reps"):
+		p.export.Source = SourceHevy
+		if err := p.hevyUnits(); err != nil {
+			return Export{}, err
+		}
+	default:
+		return Export{}, apperrors.Invalid("file", "unrecognised history CSV, expected a Strong or Hevy export")
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Export{}, apperrors.Invalid("file", "malformed history CSV: "+err.Error())
+		}
+		line, _ := reader.FieldPos(0)
+		p.export.Rows++
+		if p.export.Source == SourceStrong {
+			p.strongRow(line, record)
+		} else {
+			p.hevyRow(line, record)
+		}
+	}
+	return p.export, nil
+}
+
+func (u Units) validate() error {
+	var fields []apperrors.FieldError
+	if u.Weight != UnitKilograms && u.Weight != UnitPounds {
+		fields = append(fields, apperrors.FieldError{Field: "weight_unit", Message: fmt.Sprintf("weight unit must be %s or %s", UnitKilograms, UnitPounds)})
+	}
+	if u.Distance != UnitKilometers && u.Distance != UnitMiles {
+		fields = append(fields, apperrors.FieldError{Field: "distance_unit", Message: fmt.Sprintf("distance unit must be %s or %s", UnitKilometers, UnitMiles)})
+	}
+	if len(fields) > 0 {
+		return apperrors.Validation(fields...)
+	}
+	return nil
+}
+
+// detectDelimiter recognises older Strong exports, which separate fields
+// with semicolons and may write decimal commas
+func detectDelimiter(data []byte) rune {
+	line, _, _ := bufio.NewReader(bytes.NewReader(data)).ReadLine()
+	if bytes.Count(line, []byte(";")) > bytes.Count(line, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+type parser struct {
+	units        Units
+	columns      map[string]int
+	decimalComma bool
+	export       Export
+	// index maps workout keys to their position in export.Workouts
+	index map[string]int
+}
+
+func (p *parser) has(names ...string) bool {
+	for _, name := range names {
+		if _, ok := p.columns[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// hevyUnits reads the units from Hevy's column names, e.g. weight_lbs
+func (p *parser) hevyUnits() error {
+	switch {
+	case p.has("weight_kg"):
+		p.units.Weight = UnitKilograms
+	case p.has("weight_lbs"):
+		p.units.Weight = UnitPounds
+	default:
+		return apperrors.Invalid("file", "Hevy export has no weight_kg or weight_lbs column")
+	}
+	if p.has("distance_miles") {
+		p.units.Distance = UnitMiles
+	} else {
+		p.units.Distance = UnitKilometers
+	}
+	return nil
+}
+
+// row wraps one CSV record for reading cells by column name
+type row struct {
+	p      *parser
+	line   int
+	record []string
+	issue  string
+}
+
+func (r *row) cell(name string) string {
+	if i, ok := r.p.columns[name]; ok && i < len(r.record) {
+		return strings.TrimSpace(r.record[i])
+	}
+	return ""
+}
+
+// number parses a numeric cell, treating an empty cell as zero. The first
+// unparseable cell is kept as the row's issue.
+func (r *row) number(name string) float64 {
+	value := r.cell(name)
+	if value == "" {
+		return 0
+	}
+	if r.p.decimalComma {
+		value = strings.ReplaceAll(value, ",", ".")
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n < 0 || math.IsInf(n, 0) || math.IsNaN(n) {
+		if r.issue == "" {
+			r.issue = fmt.Sprintf("invalid %s %q", name, value)
+		}
+		return 0
+	}
+	return n
+}
+
+func (p *parser) skip(line int, exercise, reason string) {
+	p.export.Skipped = append(p.export.Skipped, Issue{Row: line, Exercise: exercise, Reason: reason})
+}
+
+// workout returns the workout a row belongs to, adding it on first sight
+func (p *parser) workout(identity []string, build func() Workout) *Workout {
+	sum := sha256.Sum256([]byte(p.export.Source + "\x00" + strings.Join(identity, "\x00")))
+	key := hex.EncodeToString(sum[:])
+	if i, ok := p.index[key]; ok {
+		return &p.export.Workouts[i]
+	}
+	w := build()
+	w.Key = key
+	p.index[key] = len(p.export.Workouts)
+	p.export.Workouts = append(p.export.Workouts, w)
+	return &p.export.Workouts[len(p.export.Workouts)-1]
+}
+
+// strongRow reads a row of a Strong export. Weights and distances follow
+// the row's unit columns in older exports and the uploader's units
+// otherwise. Rest timer rows carry the rest after the preceding set.
+func (p *parser) strongRow(line int, record []string) {
+	r := &row{p: p, line: line, record: record}
+	exercise := r.cell("exercise name")
+	started, err := parseTime(r.cell("date"), strongTimeLayouts)
+	if err != nil {
+		p.skip(line, exercise, fmt.Sprintf("invalid date %q", r.cell("date")))
+		return
+	}
+	name := r.cell("workout name")
+	w := p.workout([]string{r.cell("date"), name}, func() Workout {
+		w := Workout{Name: name, Notes: r.cell("workout notes"), StartedAt: started}
+		duration := r.cell("duration")
+		if duration == "" {
+			duration = r.cell("workout duration")
+		}
+		if d, ok := parseStrongDuration(duration); ok {
+			finished := started.Add(d)
+			w.FinishedAt = &finished
+		}
+		return w
+	})
+
+	order := strings.ToLower(r.cell("set order"))
+	switch order {
+	case "rest timer":
+		if n := len(w.Sets); n > 0 && w.Sets[n-1].Exercise == exercise {
+			if rest := int(r.number("seconds")); rest > 0 {
+				w.Sets[n-1].RestSeconds = &rest
+			}
+		}
+		return
+	case "w":
+		p.skip(line, exercise, "warm-up sets are not imported")
+		return
+	}
+
+	weightUnit, distanceUnit := p.units.Weight, p.units.Distance
+	if unit := strings.ToLower(r.cell("weight unit")); unit != "" {
+		weightUnit = strings.TrimSuffix(unit, "s")
+	}
+	if unit := strings.ToLower(r.cell("distance unit")); unit != "" {
+		distanceUnit = UnitKilometers
+		if strings.HasPrefix(unit, "mi") {
+			distanceUnit = UnitMiles
+		}
+	}
+	p.addSet(r, w, exercise, Set{
+		Reps:            int(r.number("reps")),
+		Weight:          toKilograms(r.number("weight"), weightUnit),
+		RPE:             positive(r.number("rpe")),
+		DurationSeconds: positiveInt(r.number("seconds")),
+		DistanceMeters:  positive(toMeters(r.number("distance"), distanceUnit)),
+	})
+}
+
+// hevyRow reads a row of a Hevy export
+func (p *parser) hevyRow(line int, record []string) {
+	r := &row{p: p, line: line, record: record}
+	exercise := r.cell("exercise_title")
+	started, err := parseTime(r.cell("start_time"), hevyTimeLayouts)
+	if err != nil {
+		p.skip(line, exercise, fmt.Sprintf("invalid start_time %q", r.cell("start_time")))
+		return
+	}
+	title := r.cell("title")
+	w := p.workout([]string{r.cell("start_time"), title}, func() Workout {
+		w := Workout{Name: title, Notes: r.cell("description"), StartedAt: started}
+		if finished, err := parseTime(r.cell("end_time"), hevyTimeLayouts); err == nil && !finished.Before(started) {
+			w.FinishedAt = &finished
+		}
+		return w
+	})
+
+	if strings.EqualFold(r.cell("set_type"), "warmup") {
+		p.skip(line, exercise, "warm-up sets are not imported")
+		return
+	}
+
+	weight, distance := r.number("weight_kg"), r.number("distance_km")
+	if p.units.Weight == UnitPounds {
+		weight = r.number("weight_lbs")
+	}
+	if p.units.Distance == UnitMiles {
+		distance = r.number("distance_miles")
+	}
+	p.addSet(r, w, exercise, Set{
+		Reps:            int(r.number("reps")),
+		Weight:          toKilograms(weight, p.units.Weight),
+		RPE:             positive(r.number("rpe")),
+		DurationSeconds: positiveInt(r.number("duration_seconds")),
+		DistanceMeters:  positive(toMeters(distance, p.units.Distance)),
+	})
+}
+
+// addSet appends a set to w unless its row was unreadable or recorded
+// nothing at all, as both apps export for sets left unfilled
+func (p *parser) addSet(r *row, w *Workout, exercise string, set Set) {
+	switch {
+	case exercise == "":
+		p.skip(r.line, exercise, "exercise name is missing")
+	case r.issue != "":
+		p.skip(r.line, exercise, r.issue)
+	case set.Reps == 0 && set.Weight == 0 && set.DurationSeconds == nil && set.DistanceMeters == nil:
+		p.skip(r.line, exercise, "set has no reps, weight, distance or duration")
+	default:
+		set.Row = r.line
+		set.Exercise = exercise
+		w.Sets = append(w.Sets, set)
+	}
+}
+
+var (
+	strongTimeLayouts = []string{"2006-01-02 15:04:05", "2006-01-02 15:04", time.RFC3339}
+	hevyTimeLayouts   = []string{"2 Jan 2006, 15:04", "2 Jan 2006 15:04", "2006-01-02 15:04:05", time.RFC3339}
+)
+
+// parseTime reads a timestamp in the first matching layout. Exports carry
+// no zone, so times are taken as UTC.
+func parseTime(value string, layouts []string) (time.Time, error) {
+	var err error
+	for _, layout := range layouts {
+		var t time.Time
+		if t, err = time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, err
+}
+
+var durationPart = regexp.MustCompile(`(\d+)\s*([hms])`)
+
+// parseStrongDuration reads Strong's workout durations such as "1h 5m"
+func parseStrongDuration(value string) (time.Duration, bool) {
+	parts := durationPart.FindAllStringSubmatch(strings.ToLower(value), -1)
+	if len(parts) == 0 {
+		return 0, false
+	}
+	var d time.Duration
+	for _, part := range parts {
+		n, _ := strconv.Atoi(part[1])
+		switch part[2] {
+		case "h":
+			d += time.Duration(n) * time.Hour
+		case "m":
+			d += time.Duration(n) * time.Minute
+		case "s":
+			d += time.Duration(n) * time.Second
+		}
+	}
+	return d, true
+}
+
+func toKilograms(weight float64, unit string) float64 {
+	if unit == UnitPounds {
+		weight *= kilogramsPerPound
+	}
+	return math.Round(weight*100) / 100
+}
+
+func toMeters(distance float64, unit string) float64 {
+	if unit == UnitMiles {
+		return math.Round(distance*metersPerMile*100) / 100
+	}
+	return math.Round(distance*1000*100) / 100
+}
+
+func positive(n float64) *float64 {
+	if n <= 0 {
+		return nil
+	}
+	return &n
+}
+
+func positiveInt(n float64) *int {
+	if n <= 0 {
+		return nil
+	}
+	i := int(math.Round(n))
+	return &i
+}
@@ -0,0 +1,141 @@
+package history
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"workout-api/internal/apperrors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_Strong(t *testing.T) {
+	data := `Date,Workout Name,Duration,Exercise Name,Set Order,Weight,Reps,Distance,Seconds,Notes,Workout Notes,RPE
+2026-03-02 07:30:00,Push Day,1h 5m,Bench Press (Barbell),W,40,10,0,0,,Felt strong,
+2026-03-02 07:30:00,Push Day,1h 5m,Bench Press (Barbell),1,100,5,0,0,,Felt strong,8
+2026-03-02 07:30:00,Push Day,1h 5m,Bench Press (Barbell),Rest Timer,0,0,0,120,,Felt strong,
+2026-03-02 07:30:00,Push Day,1h 5m,Bench Press (Barbell),2,100,abc,0,0,,Felt strong,
+2026-03-04 18:00:00,Run,30m,Running,1,0,0,5,1500,,,
+not a date,Legs,45m,Squat (Barbell),1,120,5,0,0,,,
+`
+	export, err := Parse(strings.NewReader(data), Units{Weight: UnitPounds, Distance: UnitKilometers})
+	assert.NoError(t, err)
+	assert.Equal(t, SourceStrong, export.Source)
+	assert.Equal(t, 6, export.Rows)
+	assert.Len(t, export.Workouts, 2)
+
+	push := export.Workouts[0]
+	assert.Equal(t, "Push Day", push.Name)
+	assert.Equal(t, "Felt strong", push.Notes)
+	assert.Equal(t, time.Date(2026, 3, 2, 7, 30, 0, 0, time.UTC), push.StartedAt)
+	assert.Equal(t, time.Date(2026, 3, 2, 8, 35, 0, 0, time.UTC), *push.FinishedAt)
+	assert.Len(t, push.Key, 64)
+	if assert.Len(t, push.Sets, 1) {
+		set := push.Sets[0]
+		assert.Equal(t, 3, set.Row)
+		assert.Equal(t, "Bench Press (Barbell)", set.Exercise)
+		assert.Equal(t, 45.36, set.Weight)
+		assert.Equal(t, 5, set.Reps)
+		assert.Equal(t, 8.0, *set.RPE)
+		assert.Equal(t, 120, *set.RestSeconds)
+		assert.Nil(t, set.DistanceMeters)
+	}
+
+	run := export.Workouts[1].Sets[0]
+	assert.Equal(t, 5000.0, *run.DistanceMeters)
+	assert.Equal(t, 1500, *run.DurationSeconds)
+
+	assert.Equal(t, []Issue{
+		{Row: 2, Exercise: "Bench Press (Barbell)", Reason: "warm-up sets are not imported"},
+		{Row: 5, Exercise: "Bench Press (Barbell)", Reason: `invalid reps "abc"`},
+		{Row: 7, Exercise: "Squat (Barbell)", Reason: `invalid date "not a date"`},
+	}, export.Skipped)
+}
+
+func TestParse_StrongSemicolons(t *testing.T) {
+	data := `Date;Workout Name;Exercise Name;Set Order;Weight;Weight Unit;Reps;RPE;Distance;Distance Unit;Seconds;Notes;Workout Notes;Workout Duration
+2019-05-01 06:00:00;Morning;Deadlift (Barbell);1;225;lbs;5;;;;;;;1h
+2019-05-01 06:00:00;Morning;Deadlift (Barbell);2;102,5;kg;3;;;;;;;1h
+`
+	export, err := Parse(strings.NewReader(data), DefaultUnits)
+	assert.NoError(t, err)
+	assert.Empty(t, export.Skipped)
+	if assert.Len(t, export.Workouts, 1) {
+		sets := export.Workouts[0].Sets
+		assert.Equal(t, 102.06, sets[0].Weight)
+		assert.Equal(t, 102.5, sets[1].Weight)
+		assert.Equal(t, time.Date(2019, 5, 1, 7, 0, 0, 0, time.UTC), *export.Workouts[0].FinishedAt)
+	}
+}
+
+func TestParse_Hevy(t *testing.T) {
+	data := `"title","start_time","end_time","description","exercise_title","superset_id","exercise_notes","set_index","set_type","weight_lbs","reps","distance_miles","duration_seconds","rpe"
+"Pull","2 Mar 2026, 07:30","2 Mar 2026, 08:30","","Lat Pulldown (Cable)",,"",0,"warmup",50,12,,,
+"Pull","2 Mar 2026, 07:30","2 Mar 2026, 08:30","","Lat Pulldown (Cable)",,"",1,"normal",120,10,,,9
+"Pull","2 Mar 2026, 07:30","2 Mar 2026, 08:30","","Plank",,"",0,"normal",,,,60,
+"Pull","2 Mar 2026, 07:30","2 Mar 2026, 08:30","","Plank",,"",1,"normal",,,,,
+`
+	export, err := Parse(strings.NewReader(data), DefaultUnits)
+	assert.NoError(t, err)
+	assert.Equal(t, SourceHevy, export.Source)
+	if assert.Len(t, export.Workouts, 1) {
+		w := export.Workouts[0]
+		assert.Equal(t, time.Date(2026, 3, 2, 8, 30, 0, 0, time.UTC), *w.FinishedAt)
+		assert.Len(t, w.Sets, 2)
+		assert.Equal(t, 54.43, w.Sets[0].Weight)
+		assert.Equal(t, 60, *w.Sets[1].DurationSeconds)
+	}
+	assert.Equal(t, []Issue{
+		{Row: 2, Exercise: "Lat Pulldown (Cable)", Reason: "warm-up sets are not imported"},
+		{Row: 5, Exercise: "Plank", Reason: "set has no reps, weight, distance or duration"},
+	}, export.Skipped)
+}
+
+func TestParse_KeysAreStable(t *testing.T) {
+	data := "Date,Workout Name,Exercise Name,Set Order,Weight,Reps\n2026-03-02 07:30:00,Push Day,Dip,1,0,10\n"
+	first, err := Parse(strings.NewReader(data), DefaultUnits)
+	assert.NoError(t, err)
+	second, err := Parse(strings.NewReader(data+"2026-03-03 07:30:00,Push Day,Dip,1,0,12\n"), DefaultUnits)
+	assert.NoError(t, err)
+	assert.Equal(t, first.Workouts[0].Key, second.Workouts[0].Key)
+	assert.NotEqual(t, second.Workouts[0].Key, second.Workouts[1].Key)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	_, err := Parse(strings.NewReader("name,reps\nPush-up,10\n"), DefaultUnits)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	_, err = Parse(strings.NewReader(""), DefaultUnits)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	_, err = Parse(strings.NewReader(""), Units{Weight: "stone", Distance: "furlong"})
+	var appErr *apperrors.Error
+	assert.ErrorAs(t, err, &appErr)
+	assert.Len(t, appErr.Fields, 2)
+}
+
+func TestParse_TooLarge(t *testing.T) {
+	body := io.NopCloser(strings.NewReader(strings.Repeat("x", 2<<20)))
+	_, err := Parse(http.MaxBytesReader(httptest.NewRecorder(), body, 1<<20), DefaultUnits)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Contains(t, err.Error(), "history CSV exceeds 1 MB")
+}
+
+func TestNormalizeName(t *testing.T) {
+	cases := map[string]string{
+		"Bench Press (Barbell)":          "barbell bench press",
+		"Incline Bench Press (Dumbbell)": "dumbbell incline bench press",
+		"Pull Up":                        "pull up",
+		"Squat (Barbell)":                "back squat",
+		"Lat Pulldown (Cable)":           "lat pulldown",
+		"  Running ":                     "treadmill run",
+	}
+	for name, want := range cases {
+		assert.Equal(t, want, NormalizeName(name), name)
+	}
+	assert.Equal(t, "Dumbbell", Equipment("Bench Press (Dumbbell)"))
+	assert.Equal(t, "", Equipment("Pull Up"))
+}
@@ -0,0 +1,67 @@
+package history
+
+import (
+	"regexp"
+	"strings"
+)
+
+// qualifier matches the trailing "(Barbell)" style equipment both apps
+// append to exercise names
+var qualifier = regexp.MustCompile(`\s*\(([^)]*)\)\s*$`)
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+// aliases map normalized names that differ from our catalog's wording by
+// more than fuzzy matching tolerates
+var aliases = map[string]string{
+	"barbell squat":              "back squat",
+	"barbell deadlift":           "deadlift",
+	"barbell overhead press":     "overhead press",
+	"barbell bent over row":      "barbell row",
+	"dumbbell bent over row":     "one arm dumbbell row",
+	"cable lat pulldown":         "lat pulldown",
+	"machine lat pulldown":       "lat pulldown",
+	"cable seated row":           "seated cable row",
+	"cable triceps pushdown":     "triceps pushdown",
+	"cable face pull":            "face pull",
+	"dumbbell lateral raise":     "lateral raise",
+	"dumbbell hammer curl":       "hammer curl",
+	"barbell romanian deadlift":  "romanian deadlift",
+	"dumbbell romanian deadlift": "romanian deadlift",
+	"barbell skullcrusher":       "skull crusher",
+	"dumbbell goblet squat":      "goblet squat",
+	"machine leg press":          "leg press",
+	"machine leg extension":      "leg extension",
+	"machine lying leg curl":     "lying leg curl",
+	"running":                    "treadmill run",
+	"treadmill running":          "treadmill run",
+	"cycling":                    "stationary bike",
+	"indoor cycling":             "stationary bike",
+	"rowing machine":             "rowing",
+	"machine rowing":             "rowing",
+	"skipping":                   "jump rope",
+}
+
+// NormalizeName rewrites an exported exercise name into our catalog's
+// wording for matching: lowercase words with the equipment qualifier moved
+// to the front, so "Bench Press (Barbell)" becomes "barbell bench press".
+func NormalizeName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if m := qualifier.FindStringSubmatch(name); m != nil {
+		name = m[1] + " " + name[:len(name)-len(m[0])]
+	}
+	name = strings.TrimSpace(nonAlphanumeric.ReplaceAllString(name, " "))
+	if alias, ok := aliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// Equipment returns the equipment qualifier of an exported exercise name,
+// e.g. "Dumbbell" for "Bench Press (Dumbbell)", or "" when there is none
+func Equipment(name string) string {
+	if m := qualifier.FindStringSubmatch(strings.TrimSpace(name)); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	return ""
+}
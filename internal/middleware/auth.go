@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"strings"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/auth"
+)
+
+const (
+	userIDKey   = "userID"
+	userRoleKey = "userRole"
+)
+
+// RequireAuth rejects requests without a valid bearer access token and
+// stores the authenticated user's ID and role in the gin context
+func RequireAuth(tokens *auth.TokenManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		tokenStr, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || tokenStr == "" {
+			_ = c.Error(apperrors.Unauthorized("missing bearer token"))
+			c.Abort()
+			return
+		}
+
+		claims, err := tokens.Parse(tokenStr)
+		if err != nil {
+			_ = c.Error(apperrors.Unauthorized(err.Error()))
+			c.Abort()
+			return
+		}
+
+		userID, _ := claims.UserID()
+		c.Set(userIDKey, userID)
+		c.Set(userRoleKey, claims.Role)
+		c.Next()
+	}
+}
+
+// RequireRole rejects authenticated requests from users without role. It
+// must run after RequireAuth.
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if CurrentUserRole(c) != role {
+			_ = c.Error(apperrors.Forbidden("insufficient permissions"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// CurrentUserID returns the ID of the authenticated user set by RequireAuth
+func CurrentUserID(c *gin.Context) (int, bool) {
+	userID, ok := c.Get(userIDKey)
+	if !ok {
+		return 0, false
+	}
+	id, ok := userID.(int)
+	return id, ok
+}
+
+// CurrentUserRole returns the role of the authenticated user set by
+// RequireAuth. Tokens issued before roles existed carry none.
+func CurrentUserRole(c *gin.Context) string {
+	return c.GetString(userRoleKey)
+}
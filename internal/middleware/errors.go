@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"workout-api/internal/apperrors"
+)
+
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body
+type Problem struct {
+	Type     string                 `json:"type"`
+	Title    string                 `json:"title"`
+	Status   int                    `json:"status"`
+	Detail   string                 `json:"detail,omitempty"`
+	Instance string                 `json:"instance,omitempty"`
+	Errors   []apperrors.FieldError `json:"errors,omitempty"`
+}
+
+type problemKind struct {
+	kind   error
+	status int
+	slug   string
+}
+
+var problemKinds = []problemKind{
+	{apperrors.ErrValidation, http.StatusBadRequest, "validation-error"},
+	{apperrors.ErrNotFound, http.StatusNotFound, "not-found"},
+	{apperrors.ErrConflict, http.StatusConflict, "conflict"},
+	{apperrors.ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+	{apperrors.ErrForbidden, http.StatusForbidden, "forbidden"},
+}
+
+// ErrorHandler renders the last error attached with c.Error as
+// application/problem+json. Handlers only attach errors and return; the
+// status code is derived from the domain error kind.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		problem := NewProblem(c.Errors.Last().Err)
+		problem.Instance = c.Request.URL.Path
+		if problem.Status == http.StatusInternalServerError {
+			log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, c.Errors.Last().Err)
+		}
+
+		c.Header("Content-Type", problemContentType)
+		c.AbortWithStatusJSON(problem.Status, problem)
+	}
+}
+
+// NewProblem builds problem details for an error. Errors that are not
+// domain errors become a generic 500 so internal details never leak.
+func NewProblem(err error) Problem {
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) {
+		for _, k := range problemKinds {
+			if errors.Is(appErr, k.kind) {
+				return Problem{
+					Type:   "/problems/" + k.slug,
+					Title:  http.StatusText(k.status),
+					Status: k.status,
+					Detail: appErr.Error(),
+					Errors: appErr.Fields,
+				}
+			}
+		}
+	}
+
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusInternalServerError),
+		Status: http.StatusInternalServerError,
+		Detail: "an unexpected error occurred",
+	}
+}
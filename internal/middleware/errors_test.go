@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"workout-api/internal/apperrors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func performWithError(err error) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.GET("/test", func(c *gin.Context) {
+		_ = c.Error(err)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestErrorHandler_StatusMapping(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{apperrors.NotFound("user", 1), http.StatusNotFound},
+		{apperrors.Conflict("user already exists"), http.StatusConflict},
+		{apperrors.Invalid("name", "name is required"), http.StatusBadRequest},
+		{apperrors.Unauthorized("missing bearer token"), http.StatusUnauthorized},
+		{apperrors.Forbidden("not yours"), http.StatusForbidden},
+		{errors.New("pq: connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		w := performWithError(tc.err)
+		assert.Equal(t, tc.status, w.Code, tc.err.Error())
+		assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+	}
+}
+
+func TestErrorHandler_ValidationFields(t *testing.T) {
+	w := performWithError(apperrors.Validation(
+		apperrors.FieldError{Field: "name", Message: "exercise name is required"},
+		apperrors.FieldError{Field: "muscle_group", Message: "muscle group is required"},
+	))
+
+	var problem Problem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "/problems/validation-error", problem.Type)
+	assert.Equal(t, "/test", problem.Instance)
+	assert.Len(t, problem.Errors, 2)
+	assert.Equal(t, "muscle_group", problem.Errors[1].Field)
+}
+
+func TestErrorHandler_HidesInternalErrors(t *testing.T) {
+	w := performWithError(errors.New("pq: password authentication failed"))
+
+	assert.NotContains(t, w.Body.String(), "password authentication")
+}
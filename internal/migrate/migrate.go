@@ -0,0 +1,363 @@
+// Package migrate applies the versioned SQL migrations embedded in the
+// migrations package and records them in the schema_migrations table.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// lockKey identifies the Postgres advisory lock held while migrating so
+// concurrently starting instances apply migrations one at a time
+const lockKey int64 = 0x776f726b6f7574 // "workout"
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration is one versioned schema change
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// Status describes a known migration and whether it has been applied
+type Status struct {
+	Version          int64      `json:"version"`
+	Name             string     `json:"name"`
+	Applied          bool       `json:"applied"`
+	AppliedAt        *time.Time `json:"applied_at,omitempty"`
+	ChecksumMismatch bool       `json:"checksum_mismatch,omitempty"`
+}
+
+type appliedMigration struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New loads the migrations from fsys, failing on malformed file names,
+// duplicate versions or up migrations without a matching down migration
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Load reads and orders the migrations found at the root of fsys
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(body)
+			sum := sha256.Sum256(body)
+			m.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		if m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s has no down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Latest returns the highest known migration version, or 0 when there are none
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies every pending migration in order
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	return m.To(ctx, m.Latest())
+}
+
+// Down rolls back the given number of most recently applied migrations
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, errors.New("steps must be positive")
+	}
+
+	var rolledBack []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if err := m.revert(ctx, conn, mig); err != nil {
+				return err
+			}
+			rolledBack = append(rolledBack, mig)
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// To migrates up or down until exactly the migrations up to and including
+// version are applied. Version 0 rolls everything back.
+func (m *Migrator) To(ctx context.Context, version int64) ([]Migration, error) {
+	if version != 0 && !m.known(version) {
+		return nil, fmt.Errorf("unknown migration version %d", version)
+	}
+
+	var changed []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verifyChecksums(applied); err != nil {
+			return err
+		}
+
+		// Roll back anything newer than the target, newest first
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok || mig.Version <= version {
+				continue
+			}
+			if err := m.revert(ctx, conn, mig); err != nil {
+				return err
+			}
+			changed = append(changed, mig)
+		}
+
+		// Then apply anything missing up to the target, oldest first
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok || mig.Version > version {
+				continue
+			}
+			if err := m.apply(ctx, conn, mig); err != nil {
+				return err
+			}
+			changed = append(changed, mig)
+		}
+		return nil
+	})
+	return changed, err
+}
+
+// Status lists every known migration along with whether it is applied. It
+// only reads, so it takes no lock; before the first migration has created
+// schema_migrations every migration is pending.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var exists bool
+	if err := conn.QueryRowContext(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists); err != nil {
+		return nil, err
+	}
+	applied := map[int64]appliedMigration{}
+	if exists {
+		if applied, err = m.applied(ctx, conn); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		s := Status{Version: mig.Version, Name: mig.Name}
+		if a, ok := applied[mig.Version]; ok {
+			appliedAt := a.appliedAt
+			s.Applied = true
+			s.AppliedAt = &appliedAt
+			s.ChecksumMismatch = a.checksum != mig.Checksum
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// Pending returns how many known migrations have not been applied
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+	pending := 0
+	for _, s := range statuses {
+		if !s.Applied {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+func (m *Migrator) known(version int64) bool {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// withLock runs fn on a single connection holding the migration advisory lock
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		// Use a fresh context so the lock is released even if ctx was cancelled
+		_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey)
+	}()
+
+	if err := ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func ensureTable(ctx context.Context, conn *sql.Conn) error {
+	query := `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		checksum CHAR(64) NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]appliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int64]appliedMigration{}
+	for rows.Next() {
+		var version int64
+		var a appliedMigration
+		if err := rows.Scan(&version, &a.checksum, &a.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = a
+	}
+	return applied, rows.Err()
+}
+
+// verifyChecksums refuses to migrate when an applied migration file has
+// been edited since it ran, because the schema no longer matches the file
+func (m *Migrator) verifyChecksums(applied map[int64]appliedMigration) error {
+	for _, mig := range m.migrations {
+		if a, ok := applied[mig.Version]; ok && a.checksum != mig.Checksum {
+			return fmt.Errorf("checksum mismatch for applied migration %d_%s", mig.Version, mig.Name)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mig Migration) error {
+	return inTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, mig.Up); err != nil {
+			return fmt.Errorf("migration %d_%s failed: %w", mig.Version, mig.Name, err)
+		}
+		_, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)",
+			mig.Version, mig.Name, mig.Checksum)
+		return err
+	})
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, mig Migration) error {
+	return inTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, mig.Down); err != nil {
+			return fmt.Errorf("rollback of %d_%s failed: %w", mig.Version, mig.Name, err)
+		}
+		_, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", mig.Version)
+		return err
+	})
+}
+
+// inTx runs fn in a transaction so a migration and its bookkeeping row are
+// applied atomically
+func inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,188 @@
+package migrate
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+	"workout-api/migrations"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id SERIAL);")},
+		"001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		"002_create_posts.up.sql":   {Data: []byte("CREATE TABLE posts (id SERIAL);")},
+		"002_create_posts.down.sql": {Data: []byte("DROP TABLE posts;")},
+		"README.md":                 {Data: []byte("ignored")},
+	}
+}
+
+func TestLoad_OrdersAndPairsFiles(t *testing.T) {
+	loaded, err := Load(testFS())
+	assert.NoError(t, err)
+	assert.Len(t, loaded, 2)
+	assert.Equal(t, int64(1), loaded[0].Version)
+	assert.Equal(t, "create_users", loaded[0].Name)
+	assert.Equal(t, "DROP TABLE users;", loaded[0].Down)
+	assert.Len(t, loaded[0].Checksum, 64)
+	assert.Equal(t, int64(2), loaded[1].Version)
+}
+
+func TestLoad_MissingDown(t *testing.T) {
+	fsys := fstest.MapFS{
+		"001_create_users.up.sql": {Data: []byte("CREATE TABLE users (id SERIAL);")},
+	}
+
+	_, err := Load(fsys)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no down file")
+}
+
+func TestLoad_EmbeddedMigrations(t *testing.T) {
+	loaded, err := Load(migrations.FS)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, loaded)
+	for i, m := range loaded {
+		assert.Equal(t, int64(i+1), m.Version, "migration versions should be contiguous")
+	}
+}
+
+func expectLocked(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectUnlocked(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestMigrator_Up_AppliesPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := New(db, testFS())
+	assert.NoError(t, err)
+	first := migrator.migrations[0]
+	second := migrator.migrations[1]
+
+	expectLocked(mock)
+	mock.ExpectQuery("SELECT version, checksum, applied_at FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "checksum", "applied_at"}).AddRow(1, first.Checksum, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(second.Up)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").
+		WithArgs(int64(2), "create_posts", second.Checksum).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlocked(mock)
+
+	applied, err := migrator.Up(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, applied, 1)
+	assert.Equal(t, int64(2), applied[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_ChecksumMismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := New(db, testFS())
+	assert.NoError(t, err)
+
+	expectLocked(mock)
+	mock.ExpectQuery("SELECT version, checksum, applied_at FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "checksum", "applied_at"}).AddRow(1, "edited", time.Now()))
+	expectUnlocked(mock)
+
+	_, err = migrator.Up(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down_RevertsLatest(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := New(db, testFS())
+	assert.NoError(t, err)
+
+	expectLocked(mock)
+	mock.ExpectQuery("SELECT version, checksum, applied_at FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "checksum", "applied_at"}).
+			AddRow(1, migrator.migrations[0].Checksum, time.Now()).
+			AddRow(2, migrator.migrations[1].Checksum, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec("DROP TABLE posts").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM schema_migrations WHERE version = \\$1").
+		WithArgs(int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlocked(mock)
+
+	rolledBack, err := migrator.Down(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, rolledBack, 1)
+	assert.Equal(t, int64(2), rolledBack[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_To_UnknownVersion(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := New(db, testFS())
+	assert.NoError(t, err)
+
+	_, err = migrator.To(context.Background(), 99)
+	assert.Error(t, err)
+}
+
+func TestMigrator_Status(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := New(db, testFS())
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT to_regclass('schema_migrations') IS NOT NULL")).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("SELECT version, checksum, applied_at FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "checksum", "applied_at"}).AddRow(1, migrator.migrations[0].Checksum, time.Now()))
+
+	statuses, err := migrator.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+	assert.True(t, statuses[0].Applied)
+	assert.False(t, statuses[1].Applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Status_NoTable(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := New(db, testFS())
+	assert.NoError(t, err)
+
+	// Status must not create the table, that is left to the locked paths
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT to_regclass('schema_migrations') IS NOT NULL")).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	pending, err := migrator.Pending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pending)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
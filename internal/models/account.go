@@ -0,0 +1,22 @@
+package models
+
+// AccountImportReport summarizes restoring an account archive. Workouts the
+// account already has are skipped, so restoring an archive twice is
+// harmless.
+type AccountImportReport struct {
+	FormatVersion   int
+	WorkoutsCreated int
+	WorkoutsSkipped int
+	SetsImported    int
+	Exercises       []ImportedExercise
+	Skipped         []ArchiveSetIssue
+}
+
+// ArchiveSetIssue explains why one set of an archive was not imported.
+// Workout and Set are 1-based positions in the archive.
+type ArchiveSetIssue struct {
+	Workout  int
+	Set      int
+	Exercise string
+	Reason   string
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// Activity file formats
+const (
+	ActivityGPX = "gpx"
+	ActivityTCX = "tcx"
+	ActivityFIT = "fit"
+)
+
+// Activity is a recorded run, ride or other cardio session uploaded from a
+// device file. It is logged as a workout with a single set of ExerciseID
+// carrying the summary metrics; the track points are kept alongside.
+// Distances are meters, durations seconds and elevations meters.
+type Activity struct {
+	ID              int             `json:"id"`
+	UserID          int             `json:"user_id"`
+	WorkoutID       int             `json:"workout_id"`
+	SetID           int             `json:"set_id"`
+	ExerciseID      int             `json:"exercise_id"`
+	Format          string          `json:"format"`
+	Sport           string          `json:"sport"`
+	Name            string          `json:"name"`
+	StartedAt       time.Time       `json:"started_at"`
+	DurationSeconds int             `json:"duration_seconds"`
+	DistanceMeters  float64         `json:"distance_meters"`
+	ElevationGain   *float64        `json:"elevation_gain_meters"`
+	AvgHeartRate    *int            `json:"avg_heart_rate"`
+	MaxHeartRate    *int            `json:"max_heart_rate"`
+	AvgPowerWatts   *int            `json:"avg_power_watts"`
+	Calories        *int            `json:"calories"`
+	Splits          []ActivitySplit `json:"splits"`
+	PointCount      int             `json:"point_count"`
+	// FileHash is the SHA-256 of the uploaded file, which may be uploaded once
+	FileHash  string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Points are only loaded on request, see the track points endpoint
+	Points []ActivityPoint `json:"-"`
+	// Records are the personal records the activity broke when it was uploaded
+	Records []PersonalRecord `json:"records,omitempty"`
+}
+
+// ActivitySplit is one kilometer of an activity; the last split covers
+// whatever distance is left over
+type ActivitySplit struct {
+	Number           int     `json:"number"`
+	DistanceMeters   float64 `json:"distance_meters"`
+	DurationSeconds  float64 `json:"duration_seconds"`
+	PaceSecondsPerKm float64 `json:"pace_seconds_per_km"`
+	ElevationGain    float64 `json:"elevation_gain_meters"`
+	AvgHeartRate     *int    `json:"avg_heart_rate"`
+}
+
+// ActivityPoint is one recorded track point. DistanceMeters is the
+// distance covered since the start. Any measurement the device did not
+// record is nil.
+type ActivityPoint struct {
+	Seq            int       `json:"seq"`
+	RecordedAt     time.Time `json:"recorded_at"`
+	Latitude       *float64  `json:"latitude"`
+	Longitude      *float64  `json:"longitude"`
+	Elevation      *float64  `json:"elevation"`
+	DistanceMeters *float64  `json:"distance_meters"`
+	HeartRate      *int      `json:"heart_rate"`
+	Cadence        *int      `json:"cadence"`
+	PowerWatts     *int      `json:"power_watts"`
+}
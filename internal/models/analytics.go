@@ -0,0 +1,143 @@
+package models
+
+import "time"
+
+// Estimated one-rep max formulas
+const (
+	FormulaEpley   = "epley"
+	FormulaBrzycki = "brzycki"
+)
+
+// Time series bucket sizes. Weeks start on Monday.
+const (
+	BucketDay   = "day"
+	BucketWeek  = "week"
+	BucketMonth = "month"
+)
+
+// MaxE1RMReps is the highest rep count a set may have to be used for an
+// estimate; both formulas lose accuracy well before that
+const MaxE1RMReps = 12
+
+// E1RMQuery selects a user's sets of one exercise for estimated one-rep
+// max analytics. Sets are dated by their workout's start; From is
+// inclusive and To exclusive.
+type E1RMQuery struct {
+	ExerciseID int
+	Formula    string
+	Bucket     string
+	From       *time.Time
+	To         *time.Time
+}
+
+// SetE1RM is a logged set with its estimated one-rep max
+type SetE1RM struct {
+	SetID       int       `json:"set_id"`
+	WorkoutID   int       `json:"workout_id"`
+	PerformedAt time.Time `json:"performed_at"`
+	Weight      float64   `json:"weight"`
+	Reps        int       `json:"reps"`
+	E1RM        float64   `json:"e1rm"`
+}
+
+// E1RMPoint is the best estimated one-rep max within one bucket, with the
+// set that produced it and the number of sets considered
+type E1RMPoint struct {
+	Bucket    time.Time `json:"bucket"`
+	E1RM      float64   `json:"e1rm"`
+	Weight    float64   `json:"weight"`
+	Reps      int       `json:"reps"`
+	WorkoutID int       `json:"workout_id"`
+	Sets      int       `json:"sets"`
+}
+
+// E1RMSeries is an estimated one-rep max time series for one exercise
+type E1RMSeries struct {
+	ExerciseID int         `json:"exercise_id"`
+	Formula    string      `json:"formula"`
+	Bucket     string      `json:"bucket"`
+	From       time.Time   `json:"from"`
+	To         time.Time   `json:"to"`
+	Points     []E1RMPoint `json:"points"`
+}
+
+// How a muscle's weekly hard sets compare with its volume landmarks
+const (
+	VolumeBelowMaintenance = "below_maintenance"
+	VolumeMaintenance      = "maintenance"
+	VolumeProductive       = "productive"
+	VolumeHigh             = "high"
+	VolumeAboveMRV         = "above_mrv"
+)
+
+// MuscleVolumeQuery selects a user's sets for weekly volume per muscle
+// group. Sets with an RPE below RPEThreshold are not hard sets; sets
+// without one are. Secondary muscles count each set as SecondaryWeight of
+// a set.
+type MuscleVolumeQuery struct {
+	From            *time.Time
+	To              *time.Time
+	RPEThreshold    *float64
+	SecondaryWeight *float64
+}
+
+// VolumeLandmarks are recommended weekly hard sets for a muscle group:
+// maintenance, minimum effective, top of the maximum adaptive range and
+// maximum recoverable volume
+type VolumeLandmarks struct {
+	MV  int `json:"mv"`
+	MEV int `json:"mev"`
+	MAV int `json:"mav"`
+	MRV int `json:"mrv"`
+}
+
+// Status places hardSets against the landmarks
+func (l VolumeLandmarks) Status(hardSets float64) string {
+	switch {
+	case hardSets < float64(l.MV):
+		return VolumeBelowMaintenance
+	case hardSets < float64(l.MEV):
+		return VolumeMaintenance
+	case hardSets <= float64(l.MAV):
+		return VolumeProductive
+	case hardSets <= float64(l.MRV):
+		return VolumeHigh
+	default:
+		return VolumeAboveMRV
+	}
+}
+
+// MuscleVolume is one muscle group's training volume in a week. Sets,
+// HardSets and Tonnage are weighted by the muscle's role in each exercise.
+type MuscleVolume struct {
+	MuscleGroup string           `json:"muscle_group"`
+	Sets        float64          `json:"sets"`
+	HardSets    float64          `json:"hard_sets"`
+	Tonnage     float64          `json:"tonnage"`
+	Landmarks   *VolumeLandmarks `json:"landmarks,omitempty"`
+	Status      string           `json:"status,omitempty"`
+}
+
+// MuscleVolumeRow is a MuscleVolume with the week it belongs to
+type MuscleVolumeRow struct {
+	WeekStart time.Time
+	MuscleVolume
+}
+
+// MuscleVolumeWeek is the volume of every muscle group trained in one ISO
+// week, which starts on Monday
+type MuscleVolumeWeek struct {
+	WeekStart time.Time      `json:"week_start"`
+	ISOYear   int            `json:"iso_year"`
+	ISOWeek   int            `json:"iso_week"`
+	Muscles   []MuscleVolume `json:"muscles"`
+}
+
+// MuscleVolumeReport is a user's weekly volume per muscle group
+type MuscleVolumeReport struct {
+	From            time.Time          `json:"from"`
+	To              time.Time          `json:"to"`
+	RPEThreshold    float64            `json:"rpe_threshold"`
+	SecondaryWeight float64            `json:"secondary_weight"`
+	Weeks           []MuscleVolumeWeek `json:"weeks"`
+}
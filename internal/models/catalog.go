@@ -0,0 +1,29 @@
+package models
+
+import "workout-api/internal/apperrors"
+
+// CatalogUpsert is the outcome of writing one catalog entry
+type CatalogUpsert struct {
+	ID      int
+	Created bool
+}
+
+// CatalogImportReport summarizes a catalog import. Rows that fail
+// validation are skipped and listed in Errors; the rest are written.
+type CatalogImportReport struct {
+	Version string
+	DryRun  bool
+	Total   int
+	Created int
+	Updated int
+	Failed  int
+	Errors  []CatalogRowError
+}
+
+// CatalogRowError lists the problems with one catalog row. Row is the
+// 1-based entry index for JSON and the line number for CSV.
+type CatalogRowError struct {
+	Row    int
+	Slug   string
+	Fields []apperrors.FieldError
+}
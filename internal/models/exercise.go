@@ -2,12 +2,71 @@ package models
 
 import "time"
 
+// Exercise visibilities
+const (
+	VisibilityGlobal  = "global"
+	VisibilityPrivate = "private"
+	VisibilityShared  = "shared"
+)
+
+// Tracking types say which measurements a logged set of an exercise needs
+const (
+	TrackingWeightReps       = "weight_reps"
+	TrackingRepsOnly         = "reps_only"
+	TrackingDuration         = "duration"
+	TrackingDistanceDuration = "distance_duration"
+)
+
+// Exercise is a catalog entry. MuscleGroup and EquipmentType hold canonical
+// taxonomy slugs; MuscleGroup is the main primary muscle and also appears
+// in Muscles alongside any other primary and secondary muscles.
 type Exercise struct {
-	ID            int       `json:"id"`
-	Name          string    `json:"name"`
-	MuscleGroup   string    `json:"muscle_group"`
-	EquipmentType string    `json:"equipment_type"`
-	Notes         string    `json:"notes"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID int `json:"id"`
+	// Slug is the stable catalog key used by imports; empty for exercises
+	// created through the API
+	Slug          string           `json:"slug,omitempty"`
+	Name          string           `json:"name"`
+	MuscleGroup   string           `json:"muscle_group"`
+	EquipmentType string           `json:"equipment_type"`
+	TrackingType  string           `json:"tracking_type"`
+	Notes         string           `json:"notes"`
+	Instructions  string           `json:"instructions"`
+	Muscles       []ExerciseMuscle `json:"muscles,omitempty"`
+	// OwnerID is nil for global catalog entries
+	OwnerID    *int      `json:"owner_id"`
+	Visibility string    `json:"visibility"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// VisibleTo reports whether v may see the exercise. Admins see everything.
+func (e Exercise) VisibleTo(v Viewer) bool {
+	return e.Visibility != VisibilityPrivate || e.OwnedBy(v.UserID) || v.IsAdmin()
+}
+
+// EditableBy reports whether v may change or delete the exercise: its
+// owner, or an admin, who alone may touch global entries
+func (e Exercise) EditableBy(v Viewer) bool {
+	return e.OwnedBy(v.UserID) || v.IsAdmin()
+}
+
+func (e Exercise) OwnedBy(userID int) bool {
+	return e.OwnerID != nil && *e.OwnerID == userID
+}
+
+// ExerciseSearch is a ranked search over the catalog
+type ExerciseSearch struct {
+	Query         string
+	MuscleGroup   string
+	EquipmentType string
+	Limit         int
+}
+
+// ExerciseSearchResult is a matched exercise with its relevance score and
+// the name and notes with matched terms wrapped in <mark> tags
+type ExerciseSearchResult struct {
+	Exercise
+	Rank           float64
+	NameHighlight  string
+	NotesHighlight string
 }
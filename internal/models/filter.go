@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// UserFilter narrows a user listing; zero values match everything
+type UserFilter struct {
+	// Name matches users whose name contains it, case-insensitively
+	Name  string
+	Email string
+}
+
+// ExerciseFilter narrows an exercise listing; zero values match everything
+type ExerciseFilter struct {
+	// Name matches exercises whose name contains it, case-insensitively
+	Name string
+	// MuscleGroup matches exercises training it as a primary muscle, or as
+	// any muscle when IncludeSecondary is set
+	MuscleGroup      string
+	IncludeSecondary bool
+	EquipmentType    string
+	// Visibility narrows to one visibility; OwnedOnly to the viewer's own
+	// exercises. Results are always limited to what the viewer may see.
+	Visibility string
+	OwnedOnly  bool
+}
+
+// WorkoutFilter narrows a user's workout listing; zero values match everything
+type WorkoutFilter struct {
+	StartedFrom *time.Time
+	StartedTo   *time.Time
+	// Finished selects finished (true) or in-progress (false) workouts
+	Finished *bool
+}
+
+// ProgramFilter narrows a program listing; zero values match everything.
+// Results are always limited to what the viewer may see.
+type ProgramFilter struct {
+	// Name matches programs whose name contains it, case-insensitively
+	Name      string
+	OwnedOnly bool
+}
+
+// RecordFilter narrows a user's personal record history; zero values match
+// everything
+type RecordFilter struct {
+	ExerciseID int
+	Category   string
+}
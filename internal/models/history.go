@@ -0,0 +1,40 @@
+package models
+
+// ExerciseMatch is the exercise an imported exercise name was mapped to.
+// Score is the name similarity, 1 for an exact match.
+type ExerciseMatch struct {
+	ExerciseID   int
+	ExerciseName string
+	TrackingType string
+	Score        float64
+}
+
+// HistoryImportReport summarizes a workout history import. Workouts seen
+// in an earlier upload are skipped, so importing a file twice is harmless.
+type HistoryImportReport struct {
+	Source          string
+	Rows            int
+	WorkoutsCreated int
+	WorkoutsSkipped int
+	SetsImported    int
+	Exercises       []ImportedExercise
+	Unmapped        []ImportRowIssue
+}
+
+// ImportedExercise records how one exported exercise name was mapped.
+// Created is set when no exercise matched and a private one was added.
+type ImportedExercise struct {
+	Name         string
+	ExerciseID   int
+	ExerciseName string
+	Created      bool
+	Score        float64
+	Sets         int
+}
+
+// ImportRowIssue explains why one row of an export was not imported
+type ImportRowIssue struct {
+	Row      int
+	Exercise string
+	Reason   string
+}
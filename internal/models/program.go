@@ -0,0 +1,131 @@
+package models
+
+import "time"
+
+// Progression rule triggers
+const (
+	ProgressOnSession = "session"
+	ProgressOnWeek    = "week"
+	ProgressOnCycle   = "cycle"
+)
+
+// Enrollment statuses
+const (
+	EnrollmentActive    = "active"
+	EnrollmentCompleted = "completed"
+	EnrollmentCancelled = "cancelled"
+)
+
+// Program is a multi-week training block. Its days run the owner's
+// routines in week and day order; a repeating program starts a new cycle
+// after its last day. Prescribed weights are rounded to Rounding.
+type Program struct {
+	ID           int               `json:"id"`
+	OwnerID      int               `json:"owner_id"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	Visibility   string            `json:"visibility"`
+	Repeats      bool              `json:"repeats"`
+	Rounding     float64           `json:"rounding"`
+	Days         []ProgramDay      `json:"days,omitempty"`
+	Progressions []ProgressionRule `json:"progressions,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+// VisibleTo reports whether v may see and enroll in the program
+func (p Program) VisibleTo(v Viewer) bool {
+	return p.Visibility == VisibilityShared || p.OwnerID == v.UserID || v.IsAdmin()
+}
+
+// EditableBy reports whether v may change or delete the program
+func (p Program) EditableBy(v Viewer) bool {
+	return p.OwnerID == v.UserID || v.IsAdmin()
+}
+
+// PercentExercises returns the exercises the program prescribes by
+// percentage of training max, in first-use order
+func (p Program) PercentExercises() []int {
+	var ids []int
+	seen := map[int]bool{}
+	for _, d := range p.Days {
+		for _, rx := range d.Prescriptions {
+			if !seen[rx.ExerciseID] {
+				seen[rx.ExerciseID] = true
+				ids = append(ids, rx.ExerciseID)
+			}
+		}
+	}
+	return ids
+}
+
+// ProgramDay is one training day of a program week
+type ProgramDay struct {
+	ID            int            `json:"id"`
+	ProgramID     int            `json:"program_id"`
+	Week          int            `json:"week"`
+	Day           int            `json:"day"`
+	Name          string         `json:"name"`
+	RoutineID     int            `json:"routine_id"`
+	Prescriptions []Prescription `json:"prescriptions"`
+}
+
+// Prescription replaces the routine's sets for an exercise on a program
+// day. Weight is PercentTM of the trainee's training max.
+type Prescription struct {
+	ID           int     `json:"id"`
+	ProgramDayID int     `json:"program_day_id"`
+	ExerciseID   int     `json:"exercise_id"`
+	SetNumber    int     `json:"set_number"`
+	PercentTM    float64 `json:"percent_tm"`
+	Reps         int     `json:"reps"`
+	AMRAP        bool    `json:"amrap"`
+}
+
+// ProgressionRule raises an exercise's training max by Increment when
+// Trigger fires: after a session where every prescribed set was completed,
+// or when a new week or cycle begins
+type ProgressionRule struct {
+	ID         int     `json:"id"`
+	ProgramID  int     `json:"program_id"`
+	ExerciseID int     `json:"exercise_id"`
+	Increment  float64 `json:"increment"`
+	Trigger    string  `json:"trigger"`
+}
+
+// Enrollment is a user's progress through a program. Week and Day point at
+// the next session; WorkoutID is the workout started for it, if any.
+type Enrollment struct {
+	ID            int           `json:"id"`
+	UserID        int           `json:"user_id"`
+	ProgramID     int           `json:"program_id"`
+	Week          int           `json:"week"`
+	Day           int           `json:"day"`
+	Cycle         int           `json:"cycle"`
+	Status        string        `json:"status"`
+	WorkoutID     *int          `json:"workout_id"`
+	TrainingMaxes []TrainingMax `json:"training_maxes"`
+	StartedAt     time.Time     `json:"started_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	EndedAt       *time.Time    `json:"ended_at"`
+}
+
+// TrainingMax is the weight percentage prescriptions are computed from
+type TrainingMax struct {
+	ExerciseID int     `json:"exercise_id"`
+	Weight     float64 `json:"weight"`
+}
+
+// ProgramSession is the workout an enrolled user is due to do next
+type ProgramSession struct {
+	EnrollmentID int          `json:"enrollment_id"`
+	ProgramID    int          `json:"program_id"`
+	ProgramName  string       `json:"program_name"`
+	Week         int          `json:"week"`
+	Day          int          `json:"day"`
+	Cycle        int          `json:"cycle"`
+	Name         string       `json:"name"`
+	RoutineID    int          `json:"routine_id"`
+	WorkoutID    *int         `json:"workout_id"`
+	PlannedSets  []PlannedSet `json:"planned_sets"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Personal record categories
+const (
+	RecordHeaviestWeight  = "heaviest_weight"
+	RecordMostReps        = "most_reps"
+	RecordBestE1RM        = "best_e1rm"
+	RecordSessionVolume   = "session_volume"
+	RecordLongestDistance = "longest_distance"
+	RecordFastestTime     = "fastest_time"
+)
+
+// PersonalRecord is a new best in one category, set by a logged set.
+// Weight, Reps, DistanceMeters and DurationSeconds describe that set; for
+// most_reps the record is the rep count at exactly that weight and for
+// fastest_time the duration over exactly that distance. Previous is the
+// best it beat.
+type PersonalRecord struct {
+	ID              int       `json:"id"`
+	UserID          int       `json:"user_id"`
+	ExerciseID      int       `json:"exercise_id"`
+	WorkoutID       int       `json:"workout_id"`
+	SetID           int       `json:"set_id"`
+	Category        string    `json:"category"`
+	Value           float64   `json:"value"`
+	Previous        float64   `json:"previous"`
+	Weight          float64   `json:"weight"`
+	Reps            int       `json:"reps"`
+	DistanceMeters  *float64  `json:"distance_meters,omitempty"`
+	DurationSeconds *int      `json:"duration_seconds,omitempty"`
+	AchievedAt      time.Time `json:"achieved_at"`
+}
+
+// RecordBests are a user's bests for an exercise from every set but the
+// one being checked. SessionVolume is the best of other workouts;
+// CurrentVolume the total of the set's own workout, the set included.
+// TimeAtDistance is the shortest duration over exactly the set's distance.
+type RecordBests struct {
+	Weight         float64
+	RepsAtWeight   int
+	E1RM           float64
+	SessionVolume  float64
+	CurrentVolume  float64
+	Distance       float64
+	TimeAtDistance int
+}
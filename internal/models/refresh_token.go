@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// RefreshToken is the persisted side of an issued refresh token. Only the
+// SHA-256 of the token is stored; tokens rotated from the same login share
+// a FamilyID so a replayed token can revoke the whole chain.
+type RefreshToken struct {
+	ID         int
+	UserID     int
+	TokenHash  string
+	FamilyID   string
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+	ReplacedBy *int
+	CreatedAt  time.Time
+}
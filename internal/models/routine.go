@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Routine is a reusable workout template owned by a user
+type Routine struct {
+	ID        int               `json:"id"`
+	UserID    int               `json:"user_id"`
+	Name      string            `json:"name"`
+	Notes     string            `json:"notes"`
+	Exercises []RoutineExercise `json:"exercises"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// RoutineExercise is one exercise in a routine with its targets. Position
+// orders the exercises starting at 1; load, RPE and rest are optional.
+type RoutineExercise struct {
+	ID           int      `json:"id"`
+	RoutineID    int      `json:"routine_id"`
+	ExerciseID   int      `json:"exercise_id"`
+	Position     int      `json:"position"`
+	TargetSets   int      `json:"target_sets"`
+	RepMin       int      `json:"rep_min"`
+	RepMax       int      `json:"rep_max"`
+	TargetWeight *float64 `json:"target_weight"`
+	TargetRPE    *float64 `json:"target_rpe"`
+	RestSeconds  *int     `json:"rest_seconds"`
+	Notes        string   `json:"notes"`
+}
+
+// Sources of a planned set's prefilled reps and weight
+const (
+	PlannedFromHistory  = "history"
+	PlannedFromTemplate = "template"
+	PlannedFromProgram  = "program"
+)
+
+// PlannedSet is a set suggested when a workout is started from a routine.
+// Reps and Weight are prefilled from the user's last performance of the
+// exercise when there is one, and from the routine's targets otherwise.
+// Sets prescribed by a program carry the percentage of training max their
+// weight was computed from; AMRAP sets ask for as many reps as possible.
+type PlannedSet struct {
+	ID          int      `json:"id"`
+	WorkoutID   int      `json:"workout_id"`
+	ExerciseID  int      `json:"exercise_id"`
+	Position    int      `json:"position"`
+	SetNumber   int      `json:"set_number"`
+	RepMin      int      `json:"rep_min"`
+	RepMax      int      `json:"rep_max"`
+	Reps        int      `json:"reps"`
+	Weight      float64  `json:"weight"`
+	RPE         *float64 `json:"rpe"`
+	RestSeconds *int     `json:"rest_seconds"`
+	PercentTM   *float64 `json:"percent_tm,omitempty"`
+	AMRAP       bool     `json:"amrap,omitempty"`
+	Source      string   `json:"source"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// Roles a muscle group can play in an exercise
+const (
+	MuscleRolePrimary   = "primary"
+	MuscleRoleSecondary = "secondary"
+)
+
+// MuscleGroup is a canonical muscle group. Aliases are the alternative
+// spellings that resolve to it, e.g. "pecs" for chest.
+type MuscleGroup struct {
+	ID      int      `json:"id"`
+	Slug    string   `json:"slug"`
+	Name    string   `json:"name"`
+	Region  string   `json:"region"`
+	Aliases []string `json:"aliases"`
+}
+
+// Equipment is a canonical piece of equipment
+type Equipment struct {
+	ID      int      `json:"id"`
+	Slug    string   `json:"slug"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+// ExerciseMuscle maps an exercise onto a muscle group it trains
+type ExerciseMuscle struct {
+	MuscleGroup string `json:"muscle_group"`
+	Role        string `json:"role"`
+}
@@ -2,11 +2,30 @@ package models
 
 import "time"
 
+// User roles. Admins curate the global exercise catalog.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// User is the persisted user record. Password holds the bcrypt hash and is
+// never serialized; API payloads use the types in the dto package.
 type User struct {
 	ID        int       `json:"id"`
-	Name      string    `json:"name" binding:"required"`
-	Email     string    `json:"email" binding:"required,email"`
-	Password  string    `json:"password" binding:"required"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Password  string    `json:"-"`
+	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+// Viewer identifies who a request acts for, for visibility and ownership checks
+type Viewer struct {
+	UserID int
+	Role   string
+}
+
+func (v Viewer) IsAdmin() bool {
+	return v.Role == RoleAdmin
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+)
+
+// Workout is a training session. RoutineID and PlannedSets are set when the
+// session was started from a routine.
+type Workout struct {
+	ID          int          `json:"id"`
+	UserID      int          `json:"user_id"`
+	RoutineID   *int         `json:"routine_id"`
+	Name        string       `json:"name"`
+	Notes       string       `json:"notes"`
+	StartedAt   time.Time    `json:"started_at"`
+	FinishedAt  *time.Time   `json:"finished_at"`
+	Sets        []WorkoutSet `json:"sets,omitempty"`
+	PlannedSets []PlannedSet `json:"planned_sets,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+
+	// ImportSource and ImportKey identify a workout imported from another
+	// app; both are empty for workouts logged here
+	ImportSource string `json:"-"`
+	ImportKey    string `json:"-"`
+}
+
+// WorkoutSet is a single logged set of an exercise within a workout.
+// RPE, RestSeconds and the cardio measurements are optional and stay nil
+// when not recorded; which ones a set needs depends on its exercise's
+// tracking type. Distances are meters and durations seconds.
+type WorkoutSet struct {
+	ID              int       `json:"id"`
+	WorkoutID       int       `json:"workout_id"`
+	ExerciseID      int       `json:"exercise_id"`
+	SetNumber       int       `json:"set_number"`
+	Reps            int       `json:"reps"`
+	Weight          float64   `json:"weight"`
+	RPE             *float64  `json:"rpe"`
+	RestSeconds     *int      `json:"rest_seconds"`
+	DurationSeconds *int      `json:"duration_seconds,omitempty"`
+	DistanceMeters  *float64  `json:"distance_meters,omitempty"`
+	AvgHeartRate    *int      `json:"avg_heart_rate,omitempty"`
+	MaxHeartRate    *int      `json:"max_heart_rate,omitempty"`
+	ElevationGain   *float64  `json:"elevation_gain_meters,omitempty"`
+	AvgPowerWatts   *int      `json:"avg_power_watts,omitempty"`
+	Calories        *int      `json:"calories,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+
+	// Records are the personal records the set broke when it was logged
+	Records []PersonalRecord `json:"records,omitempty"`
+}
+
+// Pace returns seconds per kilometer for sets with a distance and duration
+func (s WorkoutSet) Pace() *float64 {
+	if s.DistanceMeters == nil || s.DurationSeconds == nil || *s.DistanceMeters <= 0 || *s.DurationSeconds <= 0 {
+		return nil
+	}
+	pace := math.Round(float64(*s.DurationSeconds)/(*s.DistanceMeters/1000)*100) / 100
+	return &pace
+}
+
+// Speed returns kilometers per hour for sets with a distance and duration
+func (s WorkoutSet) Speed() *float64 {
+	if s.DistanceMeters == nil || s.DurationSeconds == nil || *s.DistanceMeters <= 0 || *s.DurationSeconds <= 0 {
+		return nil
+	}
+	speed := math.Round(*s.DistanceMeters/1000/(float64(*s.DurationSeconds)/3600)*100) / 100
+	return &speed
+}
+
+// MarshalJSON adds the derived pace and speed, which are never stored
+func (s WorkoutSet) MarshalJSON() ([]byte, error) {
+	type set WorkoutSet
+	return json.Marshal(struct {
+		set
+		PaceSecondsPerKm *float64 `json:"pace_seconds_per_km,omitempty"`
+		SpeedKmh         *float64 `json:"speed_kmh,omitempty"`
+	}{set(s), s.Pace(), s.Speed()})
+}
@@ -0,0 +1,215 @@
+// Package pagination implements the keyset (cursor) pagination shared by
+// the list endpoints. Cursors are opaque to clients: base64url-encoded JSON
+// recording the sort order and the boundary row of the page that issued them.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"workout-api/internal/apperrors"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Query is a page request as received from a client, before validation
+type Query struct {
+	Limit  string
+	Sort   string
+	Cursor string
+}
+
+// Params is a page request validated against a Spec
+type Params struct {
+	Limit  int
+	Sort   string
+	Desc   bool
+	Cursor *Cursor
+}
+
+// Cursor marks the row a page starts after (or, when Before is set, ends
+// before) in a given sort order
+type Cursor struct {
+	Sort   string `json:"s"`
+	Desc   bool   `json:"d,omitempty"`
+	Value  string `json:"v"`
+	ID     int    `json:"i"`
+	Before bool   `json:"b,omitempty"`
+}
+
+// Encode returns the opaque string handed to clients
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor produced by Encode
+func DecodeCursor(s string) (Cursor, error) {
+	var c Cursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, err
+	}
+	if c.Sort == "" || c.ID <= 0 {
+		return c, errors.New("incomplete cursor")
+	}
+	return c, nil
+}
+
+// Page is one slice of a listing along with the cursors around it
+type Page[T any] struct {
+	Items      []T
+	Limit      int
+	NextCursor string
+	PrevCursor string
+}
+
+// Field is a sortable column. Type is the Postgres type cursor values are
+// cast to when compared against the column.
+type Field struct {
+	Column string
+	Type   string
+}
+
+// Spec whitelists what a listing may be sorted by. Rows are always
+// tie-broken by the id column so keyset positions are unique.
+type Spec struct {
+	Fields      map[string]Field
+	DefaultSort string
+	DefaultDesc bool
+}
+
+// Parse validates a client query, rejecting unknown sort fields and cursors
+// issued for a different sort order. Limits above MaxLimit are capped.
+func (s Spec) Parse(q Query) (Params, error) {
+	p := Params{Limit: DefaultLimit, Sort: s.DefaultSort, Desc: s.DefaultDesc}
+
+	if q.Limit != "" {
+		limit, err := strconv.Atoi(q.Limit)
+		if err != nil || limit <= 0 {
+			return Params{}, apperrors.Invalid("limit", "limit must be a positive integer")
+		}
+		p.Limit = min(limit, MaxLimit)
+	}
+
+	if q.Sort != "" {
+		field, desc := strings.CutPrefix(q.Sort, "-")
+		if _, ok := s.Fields[field]; !ok {
+			return Params{}, apperrors.Invalid("sort", "sort must be one of "+strings.Join(s.sortOptions(), ", "))
+		}
+		p.Sort, p.Desc = field, desc
+	}
+
+	if q.Cursor != "" {
+		c, err := DecodeCursor(q.Cursor)
+		if err != nil {
+			return Params{}, apperrors.Invalid("cursor", "invalid cursor")
+		}
+		if _, ok := s.Fields[c.Sort]; !ok {
+			return Params{}, apperrors.Invalid("cursor", "invalid cursor")
+		}
+		// A cursor carries its own sort; an explicit sort must agree with it
+		if q.Sort != "" && (c.Sort != p.Sort || c.Desc != p.Desc) {
+			return Params{}, apperrors.Invalid("cursor", "cursor was issued for a different sort order")
+		}
+		p.Sort, p.Desc = c.Sort, c.Desc
+		p.Cursor = &c
+	}
+
+	return p, nil
+}
+
+func (s Spec) sortOptions() []string {
+	options := make([]string, 0, len(s.Fields)*2)
+	for name := range s.Fields {
+		options = append(options, name, "-"+name)
+	}
+	slices.Sort(options)
+	return options
+}
+
+// Apply appends the keyset condition, ordering and limit to a query. where
+// holds the caller's filter conditions and args their parameters; the
+// returned args include the cursor parameters. One extra row is requested
+// so NewPage can tell whether another page exists.
+func (s Spec) Apply(query string, where []string, args []any, p Params) (string, []any) {
+	field := s.Fields[p.Sort]
+
+	// Walking backwards flips both the comparison and the ordering; NewPage
+	// restores the requested order afterwards
+	desc := p.Desc
+	if p.Cursor != nil && p.Cursor.Before {
+		desc = !desc
+	}
+
+	if p.Cursor != nil {
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		args = append(args, p.Cursor.Value, p.Cursor.ID)
+		where = append(where, fmt.Sprintf("(%s, id) %s ($%d::%s, $%d)", field.Column, op, len(args)-1, field.Type, len(args)))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(query)
+	if len(where) > 0 {
+		sb.WriteString(" WHERE ")
+		sb.WriteString(strings.Join(where, " AND "))
+	}
+
+	dir := "ASC"
+	if desc {
+		dir = "DESC"
+	}
+	fmt.Fprintf(&sb, " ORDER BY %s %s, id %s LIMIT %d", field.Column, dir, dir, p.Limit+1)
+
+	return sb.String(), args
+}
+
+// NewPage trims the extra row requested by Apply, restores the requested
+// order and builds the cursors. key returns an item's sort value (in a form
+// Postgres can cast back to the field's type) and its ID.
+func NewPage[T any](items []T, p Params, key func(item T, sort string) (string, int)) Page[T] {
+	hasMore := len(items) > p.Limit
+	if hasMore {
+		items = items[:p.Limit]
+	}
+	backward := p.Cursor != nil && p.Cursor.Before
+	if backward {
+		slices.Reverse(items)
+	}
+	if items == nil {
+		items = []T{}
+	}
+
+	page := Page[T]{Items: items, Limit: p.Limit}
+	if len(items) == 0 {
+		return page
+	}
+
+	cursorAt := func(item T, before bool) string {
+		value, id := key(item, p.Sort)
+		return Cursor{Sort: p.Sort, Desc: p.Desc, Value: value, ID: id, Before: before}.Encode()
+	}
+
+	// Going forward there is a previous page whenever we started from a
+	// cursor; going backward there is always a next page (the one we came from)
+	if hasMore || backward {
+		page.NextCursor = cursorAt(items[len(items)-1], false)
+	}
+	if (hasMore && backward) || (!backward && p.Cursor != nil) {
+		page.PrevCursor = cursorAt(items[0], true)
+	}
+	return page
+}
@@ -0,0 +1,147 @@
+package pagination
+
+import (
+	"testing"
+	"workout-api/internal/apperrors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSpec = Spec{
+	Fields: map[string]Field{
+		"id":   {Column: "id", Type: "integer"},
+		"name": {Column: "name", Type: "text"},
+	},
+	DefaultSort: "name",
+}
+
+type item struct {
+	ID   int
+	Name string
+}
+
+func itemKey(i item, _ string) (string, int) {
+	return i.Name, i.ID
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	c := Cursor{Sort: "name", Desc: true, Value: "Bench Press", ID: 42, Before: true}
+
+	decoded, err := DecodeCursor(c.Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, c, decoded)
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	for _, raw := range []string{"%%%", "bm90IGpzb24", Cursor{Sort: "name"}.Encode()} {
+		_, err := DecodeCursor(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestSpec_Parse_Defaults(t *testing.T) {
+	p, err := testSpec.Parse(Query{})
+	assert.NoError(t, err)
+	assert.Equal(t, Params{Limit: DefaultLimit, Sort: "name"}, p)
+}
+
+func TestSpec_Parse_SortAndLimit(t *testing.T) {
+	p, err := testSpec.Parse(Query{Limit: "500", Sort: "-id"})
+	assert.NoError(t, err)
+	assert.Equal(t, Params{Limit: MaxLimit, Sort: "id", Desc: true}, p)
+}
+
+func TestSpec_Parse_Rejects(t *testing.T) {
+	otherSort := Cursor{Sort: "id", Value: "3", ID: 3}.Encode()
+
+	tests := []struct {
+		name  string
+		query Query
+		field string
+	}{
+		{"zero limit", Query{Limit: "0"}, "limit"},
+		{"non-numeric limit", Query{Limit: "ten"}, "limit"},
+		{"unknown sort", Query{Sort: "password"}, "sort"},
+		{"garbage cursor", Query{Cursor: "garbage"}, "cursor"},
+		{"unknown cursor sort", Query{Cursor: Cursor{Sort: "password", Value: "x", ID: 1}.Encode()}, "cursor"},
+		{"cursor for another sort", Query{Sort: "name", Cursor: otherSort}, "cursor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := testSpec.Parse(tt.query)
+			assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+			var appErr *apperrors.Error
+			assert.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tt.field, appErr.Fields[0].Field)
+		})
+	}
+}
+
+func TestSpec_Parse_CursorCarriesSort(t *testing.T) {
+	c := Cursor{Sort: "id", Desc: true, Value: "3", ID: 3}
+
+	p, err := testSpec.Parse(Query{Cursor: c.Encode()})
+	assert.NoError(t, err)
+	assert.Equal(t, "id", p.Sort)
+	assert.True(t, p.Desc)
+	assert.Equal(t, &c, p.Cursor)
+}
+
+func TestSpec_Apply(t *testing.T) {
+	p := Params{Limit: 10, Sort: "name", Desc: true, Cursor: &Cursor{Sort: "name", Desc: true, Value: "M", ID: 5}}
+
+	query, args := testSpec.Apply("SELECT id, name FROM things", []string{"owner_id = $1"}, []any{9}, p)
+	assert.Equal(t, "SELECT id, name FROM things WHERE owner_id = $1 AND (name, id) < ($2::text, $3) ORDER BY name DESC, id DESC LIMIT 11", query)
+	assert.Equal(t, []any{9, "M", 5}, args)
+}
+
+func TestSpec_Apply_Backward(t *testing.T) {
+	p := Params{Limit: 10, Sort: "name", Cursor: &Cursor{Sort: "name", Value: "M", ID: 5, Before: true}}
+
+	query, _ := testSpec.Apply("SELECT id, name FROM things", nil, nil, p)
+	assert.Equal(t, "SELECT id, name FROM things WHERE (name, id) < ($1::text, $2) ORDER BY name DESC, id DESC LIMIT 11", query)
+}
+
+func TestNewPage_FirstPage(t *testing.T) {
+	items := []item{{1, "A"}, {2, "B"}, {3, "C"}}
+
+	page := NewPage(items, Params{Limit: 2, Sort: "name"}, itemKey)
+	assert.Equal(t, []item{{1, "A"}, {2, "B"}}, page.Items)
+	assert.Empty(t, page.PrevCursor)
+
+	next, err := DecodeCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, Cursor{Sort: "name", Value: "B", ID: 2}, next)
+}
+
+func TestNewPage_LastPage(t *testing.T) {
+	items := []item{{3, "C"}}
+	p := Params{Limit: 2, Sort: "name", Cursor: &Cursor{Sort: "name", Value: "B", ID: 2}}
+
+	page := NewPage(items, p, itemKey)
+	assert.Empty(t, page.NextCursor)
+
+	prev, err := DecodeCursor(page.PrevCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, Cursor{Sort: "name", Value: "C", ID: 3, Before: true}, prev)
+}
+
+func TestNewPage_Backward(t *testing.T) {
+	// Rows arrive in reverse order when walking backwards
+	items := []item{{4, "D"}, {3, "C"}, {2, "B"}}
+	p := Params{Limit: 2, Sort: "name", Cursor: &Cursor{Sort: "name", Value: "E", ID: 5, Before: true}}
+
+	page := NewPage(items, p, itemKey)
+	assert.Equal(t, []item{{3, "C"}, {4, "D"}}, page.Items)
+	assert.NotEmpty(t, page.PrevCursor)
+	assert.NotEmpty(t, page.NextCursor)
+}
+
+func TestNewPage_Empty(t *testing.T) {
+	page := NewPage[item](nil, Params{Limit: 2, Sort: "name"}, itemKey)
+	assert.NotNil(t, page.Items)
+	assert.Empty(t, page.NextCursor)
+	assert.Empty(t, page.PrevCursor)
+}
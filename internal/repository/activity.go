@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+)
+
+const activityColumns = `id, user_id, workout_id, set_id, exercise_id, format, sport, name, started_at, duration_seconds, distance_meters,
+	elevation_gain_meters, avg_heart_rate, max_heart_rate, avg_power_watts, calories, splits, point_count, file_hash, created_at`
+
+// pointBatchSize is the number of track points written per insert, keeping
+// each statement well under the Postgres bind parameter limit
+const pointBatchSize = 500
+
+type ActivityRepository struct {
+	db *sql.DB
+}
+
+func NewActivityRepository(db *sql.DB) *ActivityRepository {
+	return &ActivityRepository{db: db}
+}
+
+// Create stores an uploaded activity in one transaction: the finished
+// workout with its single summary set, the activity and its track points.
+// Uploading a file the user already uploaded is a conflict.
+func (r *ActivityRepository) Create(ctx context.Context, workout models.Workout, activity models.Activity) (models.Activity, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	splits, err := json.Marshal(activity.Splits)
+	if err != nil {
+		return models.Activity{}, err
+	}
+
+	err = inTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := "INSERT INTO workouts (user_id, name, notes, started_at, finished_at) VALUES ($1, $2, $3, $4, $5) RETURNING id"
+		err := tx.QueryRowContext(ctx, query, workout.UserID, workout.Name, workout.Notes, workout.StartedAt, workout.FinishedAt).
+			Scan(&activity.WorkoutID)
+		if err != nil {
+			return mapError(err, "workout", workout.UserID)
+		}
+
+		s := workout.Sets[0]
+		setQuery := `INSERT INTO workout_sets (workout_id, exercise_id, set_number, reps, weight,
+				duration_seconds, distance_meters, avg_heart_rate, max_heart_rate, elevation_gain_meters, avg_power_watts, calories)
+			VALUES ($1, $2, 1, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
+		err = tx.QueryRowContext(ctx, setQuery, activity.WorkoutID, s.ExerciseID, s.Reps, s.Weight, s.DurationSeconds, s.DistanceMeters,
+			s.AvgHeartRate, s.MaxHeartRate, s.ElevationGain, s.AvgPowerWatts, s.Calories).Scan(&activity.SetID)
+		if err != nil {
+			return mapError(err, "workout set", activity.WorkoutID)
+		}
+
+		activityQuery := `INSERT INTO activities (user_id, workout_id, set_id, exercise_id, format, sport, name, started_at,
+				duration_seconds, distance_meters, elevation_gain_meters, avg_heart_rate, max_heart_rate, avg_power_watts, calories,
+				splits, point_count, file_hash)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18) RETURNING id, created_at`
+		err = tx.QueryRowContext(ctx, activityQuery, activity.UserID, activity.WorkoutID, activity.SetID, activity.ExerciseID,
+			activity.Format, activity.Sport, activity.Name, activity.StartedAt, activity.DurationSeconds, activity.DistanceMeters,
+			activity.ElevationGain, activity.AvgHeartRate, activity.MaxHeartRate, activity.AvgPowerWatts, activity.Calories,
+			splits, activity.PointCount, activity.FileHash).Scan(&activity.ID, &activity.CreatedAt)
+		if err != nil {
+			return mapError(err, "activity", activity.FileHash)
+		}
+		return insertPoints(ctx, tx, activity.ID, activity.Points)
+	})
+	if err != nil {
+		return models.Activity{}, err
+	}
+	return activity, nil
+}
+
+// insertPoints writes track points in multi-row batches
+func insertPoints(ctx context.Context, tx *sql.Tx, activityID int, points []models.ActivityPoint) error {
+	for start := 0; start < len(points); start += pointBatchSize {
+		batch := points[start:min(start+pointBatchSize, len(points))]
+		values := make([]string, len(batch))
+		args := make([]any, 0, len(batch)*10)
+		for i, p := range batch {
+			n := len(args)
+			values[i] = fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9, n+10)
+			args = append(args, activityID, p.Seq, p.RecordedAt, p.Latitude, p.Longitude, p.Elevation, p.DistanceMeters,
+				p.HeartRate, p.Cadence, p.PowerWatts)
+		}
+		query := `INSERT INTO activity_points
+				(activity_id, seq, recorded_at, latitude, longitude, elevation, distance_meters, heart_rate, cadence, power_watts)
+			VALUES ` + strings.Join(values, ", ")
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return mapError(err, "activity point", activityID)
+		}
+	}
+	return nil
+}
+
+func (r *ActivityRepository) GetById(ctx context.Context, id int) (models.Activity, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var a models.Activity
+	err := scanActivity(r.db.QueryRowContext(ctx, "SELECT "+activityColumns+" FROM activities WHERE id = $1", id), &a)
+	if err != nil {
+		return models.Activity{}, mapError(err, "activity", id)
+	}
+	return a, nil
+}
+
+// ActivityListSpec whitelists the sort orders accepted by ListByUser
+var ActivityListSpec = pagination.Spec{
+	Fields: map[string]pagination.Field{
+		"id":         {Column: "id", Type: "integer"},
+		"started_at": {Column: "started_at", Type: "timestamp"},
+	},
+	DefaultSort: "started_at",
+	DefaultDesc: true,
+}
+
+// ListByUser returns one page of a user's activities, optionally of one sport
+func (r *ActivityRepository) ListByUser(ctx context.Context, userID int, sport string, params pagination.Params) (pagination.Page[models.Activity], error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	where := []string{"user_id = $1"}
+	args := []any{userID}
+	if sport != "" {
+		args = append(args, sport)
+		where = append(where, fmt.Sprintf("sport = $%d", len(args)))
+	}
+
+	query, args := ActivityListSpec.Apply("SELECT "+activityColumns+" FROM activities", where, args, params)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return pagination.Page[models.Activity]{}, err
+	}
+	defer rows.Close()
+
+	var activities []models.Activity
+	for rows.Next() {
+		var a models.Activity
+		if err := scanActivity(rows, &a); err != nil {
+			return pagination.Page[models.Activity]{}, err
+		}
+		activities = append(activities, a)
+	}
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.Activity]{}, err
+	}
+	return pagination.NewPage(activities, params, activityKey), nil
+}
+
+func activityKey(a models.Activity, sort string) (string, int) {
+	if sort == "started_at" {
+		return timeKey(a.StartedAt), a.ID
+	}
+	return strconv.Itoa(a.ID), a.ID
+}
+
+// Points returns an activity's track points in recorded order
+func (r *ActivityRepository) Points(ctx context.Context, activityID int) ([]models.ActivityPoint, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT seq, recorded_at, latitude, longitude, elevation, distance_meters, heart_rate, cadence, power_watts
+		FROM activity_points WHERE activity_id = $1 ORDER BY seq`
+	rows, err := r.db.QueryContext(ctx, query, activityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []models.ActivityPoint{}
+	for rows.Next() {
+		var p models.ActivityPoint
+		err := rows.Scan(&p.Seq, &p.RecordedAt, &p.Latitude, &p.Longitude, &p.Elevation, &p.DistanceMeters, &p.HeartRate, &p.Cadence, &p.PowerWatts)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+// scanActivity reads a row selected with activityColumns
+func scanActivity(row interface{ Scan(dest ...any) error }, a *models.Activity) error {
+	var splits []byte
+	err := row.Scan(&a.ID, &a.UserID, &a.WorkoutID, &a.SetID, &a.ExerciseID, &a.Format, &a.Sport, &a.Name, &a.StartedAt,
+		&a.DurationSeconds, &a.DistanceMeters, &a.ElevationGain, &a.AvgHeartRate, &a.MaxHeartRate, &a.AvgPowerWatts, &a.Calories,
+		&splits, &a.PointCount, &a.FileHash, &a.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(splits, &a.Splits)
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var activityRowColumns = []string{"id", "user_id", "workout_id", "set_id", "exercise_id", "format", "sport", "name", "started_at",
+	"duration_seconds", "distance_meters", "elevation_gain_meters", "avg_heart_rate", "max_heart_rate", "avg_power_watts", "calories",
+	"splits", "point_count", "file_hash", "created_at"}
+
+func testActivityUpload() (models.Workout, models.Activity) {
+	start := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+	finish := start.Add(10 * time.Minute)
+	duration := 600
+	distance := 2000.0
+	workout := models.Workout{UserID: 1, Name: "Morning Run", StartedAt: start, FinishedAt: &finish,
+		Sets: []models.WorkoutSet{{ExerciseID: 4, DurationSeconds: &duration, DistanceMeters: &distance}}}
+
+	d0, d1 := 0.0, 2000.0
+	activity := models.Activity{UserID: 1, ExerciseID: 4, Format: models.ActivityGPX, Sport: "running", Name: "Morning Run",
+		StartedAt: start, DurationSeconds: 600, DistanceMeters: 2000, PointCount: 2, FileHash: "abc",
+		Splits: []models.ActivitySplit{{Number: 1, DistanceMeters: 1000, DurationSeconds: 300, PaceSecondsPerKm: 300}},
+		Points: []models.ActivityPoint{
+			{Seq: 0, RecordedAt: start, DistanceMeters: &d0},
+			{Seq: 1, RecordedAt: finish, DistanceMeters: &d1},
+		}}
+	return workout, activity
+}
+
+func TestActivityRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewActivityRepository(db)
+	workout, activity := testActivityUpload()
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO workouts").
+		WithArgs(1, "Morning Run", "", workout.StartedAt, workout.FinishedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+	mock.ExpectQuery("INSERT INTO workout_sets").
+		WithArgs(10, 4, 0, 0.0, workout.Sets[0].DurationSeconds, workout.Sets[0].DistanceMeters, nil, nil, nil, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(20))
+	mock.ExpectQuery("INSERT INTO activities").
+		WithArgs(1, 10, 20, 4, models.ActivityGPX, "running", "Morning Run", activity.StartedAt, 600, 2000.0,
+			nil, nil, nil, nil, nil, sqlmock.AnyArg(), 2, "abc").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(30, now))
+	mock.ExpectExec(regexp.QuoteMeta("VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10), ($11, ")).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	created, err := repo.Create(context.Background(), workout, activity)
+	assert.NoError(t, err)
+	assert.Equal(t, 30, created.ID)
+	assert.Equal(t, 10, created.WorkoutID)
+	assert.Equal(t, 20, created.SetID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestActivityRepository_Create_Duplicate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewActivityRepository(db)
+	workout, activity := testActivityUpload()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO workouts").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+	mock.ExpectQuery("INSERT INTO workout_sets").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(20))
+	mock.ExpectQuery("INSERT INTO activities").WillReturnError(&pq.Error{Code: "23505"})
+	mock.ExpectRollback()
+
+	_, err = repo.Create(context.Background(), workout, activity)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestActivityRepository_ListByUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewActivityRepository(db)
+	start := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE user_id = $1 AND sport = $2")+"(.+)"+regexp.QuoteMeta("ORDER BY started_at DESC")).
+		WithArgs(1, "running").
+		WillReturnRows(sqlmock.NewRows(activityRowColumns).
+			AddRow(30, 1, 10, 20, 4, "gpx", "running", "Morning Run", start, 600, 2000.0, 12.5, 150, 170, nil, nil,
+				[]byte(`[{"number":1,"distance_meters":1000,"duration_seconds":300,"pace_seconds_per_km":300,"elevation_gain_meters":0,"avg_heart_rate":null}]`),
+				2, "abc", start))
+
+	page, err := repo.ListByUser(context.Background(), 1, "running", pagination.Params{Limit: 10, Sort: "started_at", Desc: true})
+	assert.NoError(t, err)
+	if assert.Len(t, page.Items, 1) {
+		a := page.Items[0]
+		assert.Equal(t, 12.5, *a.ElevationGain)
+		assert.Len(t, a.Splits, 1)
+		assert.Equal(t, 300.0, a.Splits[0].PaceSecondsPerKm)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestActivityRepository_Points(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewActivityRepository(db)
+	start := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("FROM activity_points WHERE activity_id = \\$1 ORDER BY seq").
+		WithArgs(30).
+		WillReturnRows(sqlmock.NewRows([]string{"seq", "recorded_at", "latitude", "longitude", "elevation", "distance_meters", "heart_rate", "cadence", "power_watts"}).
+			AddRow(0, start, 51.5, -0.12, 10.0, 0.0, 140, nil, nil).
+			AddRow(1, start.Add(time.Second), 51.5001, -0.12, 10.0, 11.1, 141, nil, nil))
+
+	points, err := repo.Points(context.Background(), 30)
+	assert.NoError(t, err)
+	assert.Len(t, points, 2)
+	assert.Equal(t, 141, *points[1].HeartRate)
+	assert.Nil(t, points[1].Cadence)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"workout-api/internal/models"
+)
+
+// e1rmFormulas maps each formula to its SQL over workout_sets aliased s.
+// Both give the lifted weight back for a single.
+var e1rmFormulas = map[string]string{
+	models.FormulaEpley:   "CASE WHEN s.reps = 1 THEN s.weight ELSE s.weight * (1 + s.reps / 30.0) END",
+	models.FormulaBrzycki: "s.weight * 36.0 / (37 - s.reps)",
+}
+
+type AnalyticsRepository struct {
+	db *sql.DB
+}
+
+func NewAnalyticsRepository(db *sql.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+// e1rmSets builds a query selecting the user's qualifying sets in q with
+// their estimated one-rep max, and its arguments. q must have a known
+// formula and both range bounds set.
+func e1rmSets(userID int, q models.E1RMQuery) (string, []any, error) {
+	formula, ok := e1rmFormulas[q.Formula]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown e1RM formula %q", q.Formula)
+	}
+	query := fmt.Sprintf(`SELECT s.id, s.workout_id, w.started_at, s.weight, s.reps, ROUND((%s)::numeric, 2) AS e1rm
+		FROM workout_sets s
+		JOIN workouts w ON w.id = s.workout_id
+		WHERE w.user_id = $1 AND s.exercise_id = $2 AND s.weight > 0 AND s.reps BETWEEN 1 AND %d
+			AND w.started_at >= $3 AND w.started_at < $4`, formula, models.MaxE1RMReps)
+	return query, []any{userID, q.ExerciseID, *q.From, *q.To}, nil
+}
+
+// E1RMSets returns up to limit of the user's sets in q with their
+// estimated one-rep max, newest first
+func (r *AnalyticsRepository) E1RMSets(ctx context.Context, userID int, q models.E1RMQuery, limit int) ([]models.SetE1RM, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	base, args, err := e1rmSets(userID, q)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, limit)
+	query := base + fmt.Sprintf(" ORDER BY w.started_at DESC, s.id DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sets := []models.SetE1RM{}
+	for rows.Next() {
+		var s models.SetE1RM
+		if err := rows.Scan(&s.SetID, &s.WorkoutID, &s.PerformedAt, &s.Weight, &s.Reps, &s.E1RM); err != nil {
+			return nil, err
+		}
+		sets = append(sets, s)
+	}
+	return sets, rows.Err()
+}
+
+// E1RMSeries returns the best estimated one-rep max per bucket of q, oldest
+// first. Empty buckets are left out. Ties go to the earliest set.
+func (r *AnalyticsRepository) E1RMSeries(ctx context.Context, userID int, q models.E1RMQuery) ([]models.E1RMPoint, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	base, args, err := e1rmSets(userID, q)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, q.Bucket)
+	query := fmt.Sprintf(`
+		WITH sets AS (%s),
+		ranked AS (
+			SELECT date_trunc($%[2]d, started_at) AS bucket, workout_id, weight, reps, e1rm,
+				ROW_NUMBER() OVER (PARTITION BY date_trunc($%[2]d, started_at) ORDER BY e1rm DESC, started_at, id) AS rank,
+				COUNT(*) OVER (PARTITION BY date_trunc($%[2]d, started_at)) AS sets
+			FROM sets
+		)
+		SELECT bucket, e1rm, weight, reps, workout_id, sets FROM ranked WHERE rank = 1 ORDER BY bucket`, base, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []models.E1RMPoint{}
+	for rows.Next() {
+		var p models.E1RMPoint
+		if err := rows.Scan(&p.Bucket, &p.E1RM, &p.Weight, &p.Reps, &p.WorkoutID, &p.Sets); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+// MuscleVolume returns the user's volume per muscle group per week in q,
+// oldest week first. q must have every field set.
+func (r *AnalyticsRepository) MuscleVolume(ctx context.Context, userID int, q models.MuscleVolumeQuery) ([]models.MuscleVolumeRow, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `
+		WITH weighted AS (
+			SELECT date_trunc('week', w.started_at) AS week, em.muscle_group_id, s.weight, s.reps, s.rpe,
+				CASE WHEN em.role = 'primary' THEN 1 ELSE $4::numeric END AS factor
+			FROM workout_sets s
+			JOIN workouts w ON w.id = s.workout_id
+			JOIN exercise_muscles em ON em.exercise_id = s.exercise_id
+			WHERE w.user_id = $1 AND w.started_at >= $2 AND w.started_at < $3
+		)
+		SELECT v.week, mg.slug,
+			ROUND(SUM(v.factor), 2),
+			ROUND(COALESCE(SUM(v.factor) FILTER (WHERE v.rpe IS NULL OR v.rpe >= $5), 0), 2),
+			ROUND(SUM(v.weight * v.reps * v.factor), 2),
+			mg.volume_mv, mg.volume_mev, mg.volume_mav, mg.volume_mrv
+		FROM weighted v
+		JOIN muscle_groups mg ON mg.id = v.muscle_group_id
+		WHERE v.factor > 0
+		GROUP BY v.week, mg.id
+		ORDER BY v.week, mg.slug`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, *q.From, *q.To, *q.SecondaryWeight, *q.RPEThreshold)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	volumes := []models.MuscleVolumeRow{}
+	for rows.Next() {
+		var v models.MuscleVolumeRow
+		var mv, mev, mav, mrv sql.NullInt64
+		if err := rows.Scan(&v.WeekStart, &v.MuscleGroup, &v.Sets, &v.HardSets, &v.Tonnage, &mv, &mev, &mav, &mrv); err != nil {
+			return nil, err
+		}
+		// The schema keeps the landmarks all set or all NULL
+		if mv.Valid {
+			v.Landmarks = &models.VolumeLandmarks{MV: int(mv.Int64), MEV: int(mev.Int64), MAV: int(mav.Int64), MRV: int(mrv.Int64)}
+		}
+		volumes = append(volumes, v)
+	}
+	return volumes, rows.Err()
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+	"workout-api/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func testE1RMQuery(formula, bucket string) models.E1RMQuery {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	return models.E1RMQuery{ExerciseID: 3, Formula: formula, Bucket: bucket, From: &from, To: &to}
+}
+
+func TestAnalyticsRepository_E1RMSeries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAnalyticsRepository(db)
+	q := testE1RMQuery(models.FormulaEpley, models.BucketWeek)
+	monday := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("CASE WHEN s.reps = 1 THEN s.weight ELSE s.weight * (1 + s.reps / 30.0) END")+
+		"(.+)"+regexp.QuoteMeta("s.reps BETWEEN 1 AND 12")+
+		"(.+)"+regexp.QuoteMeta("PARTITION BY date_trunc($5, started_at) ORDER BY e1rm DESC")).
+		WithArgs(1, 3, *q.From, *q.To, "week").
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "e1rm", "weight", "reps", "workout_id", "sets"}).
+			AddRow(monday, 116.67, 100.0, 5, 8, 6).
+			AddRow(monday.AddDate(0, 0, 7), 120.0, 120.0, 1, 9, 4))
+
+	points, err := repo.E1RMSeries(context.Background(), 1, q)
+	assert.NoError(t, err)
+	assert.Len(t, points, 2)
+	assert.Equal(t, 116.67, points[0].E1RM)
+	assert.Equal(t, 6, points[0].Sets)
+	assert.Equal(t, 9, points[1].WorkoutID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnalyticsRepository_E1RMSeries_Empty(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAnalyticsRepository(db)
+
+	mock.ExpectQuery("WITH sets AS").
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "e1rm", "weight", "reps", "workout_id", "sets"}))
+
+	points, err := repo.E1RMSeries(context.Background(), 1, testE1RMQuery(models.FormulaBrzycki, models.BucketDay))
+	assert.NoError(t, err)
+	assert.NotNil(t, points)
+	assert.Empty(t, points)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnalyticsRepository_E1RMSets(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAnalyticsRepository(db)
+	q := testE1RMQuery(models.FormulaBrzycki, "")
+	performed := time.Date(2024, 6, 3, 18, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("s.weight * 36.0 / (37 - s.reps)")+"(.+)"+regexp.QuoteMeta("ORDER BY w.started_at DESC, s.id DESC LIMIT $5")).
+		WithArgs(1, 3, *q.From, *q.To, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "workout_id", "started_at", "weight", "reps", "e1rm"}).
+			AddRow(21, 8, performed, 100.0, 5, 112.5))
+
+	sets, err := repo.E1RMSets(context.Background(), 1, q, 50)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.SetE1RM{{SetID: 21, WorkoutID: 8, PerformedAt: performed, Weight: 100, Reps: 5, E1RM: 112.5}}, sets)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnalyticsRepository_UnknownFormula(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAnalyticsRepository(db)
+
+	_, err = repo.E1RMSeries(context.Background(), 1, testE1RMQuery("lombardi", models.BucketDay))
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnalyticsRepository_MuscleVolume(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAnalyticsRepository(db)
+	from := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 17, 0, 0, 0, 0, time.UTC)
+	rpe, weight := 7.0, 0.5
+	q := models.MuscleVolumeQuery{From: &from, To: &to, RPEThreshold: &rpe, SecondaryWeight: &weight}
+
+	mock.ExpectQuery(regexp.QuoteMeta("CASE WHEN em.role = 'primary' THEN 1 ELSE $4::numeric END")+
+		"(.+)"+regexp.QuoteMeta("FILTER (WHERE v.rpe IS NULL OR v.rpe >= $5)")).
+		WithArgs(1, from, to, 0.5, 7.0).
+		WillReturnRows(sqlmock.NewRows([]string{"week", "slug", "sets", "hard_sets", "tonnage", "mv", "mev", "mav", "mrv"}).
+			AddRow(from, "chest", 10.0, 8.0, 6400.0, 8, 10, 20, 22).
+			AddRow(from, "neck", 3.0, 3.0, 150.0, nil, nil, nil, nil))
+
+	volumes, err := repo.MuscleVolume(context.Background(), 1, q)
+	assert.NoError(t, err)
+	assert.Len(t, volumes, 2)
+	assert.Equal(t, "chest", volumes[0].MuscleGroup)
+	assert.Equal(t, &models.VolumeLandmarks{MV: 8, MEV: 10, MAV: 20, MRV: 22}, volumes[0].Landmarks)
+	assert.Nil(t, volumes[1].Landmarks)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultQueryTimeout bounds every repository call. A shorter deadline
+// already on the incoming context, e.g. from a cancelled request, wins.
+// Zero disables the default bound.
+var DefaultQueryTimeout = 5 * time.Second
+
+// withTimeout derives the context a single repository call runs under
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if DefaultQueryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, DefaultQueryTimeout)
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"workout-api/internal/models"
+)
+
+type EnrollmentRepository struct {
+	db *sql.DB
+}
+
+func NewEnrollmentRepository(db *sql.DB) *EnrollmentRepository {
+	return &EnrollmentRepository{db: db}
+}
+
+// Create inserts the enrollment with its training maxes. A user with an
+// active enrollment gets a conflict.
+func (r *EnrollmentRepository) Create(ctx context.Context, enrollment models.Enrollment) (models.Enrollment, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := `INSERT INTO program_enrollments (user_id, program_id, week, day, cycle, status)
+			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, started_at, updated_at`
+		err := tx.QueryRowContext(ctx, query, enrollment.UserID, enrollment.ProgramID, enrollment.Week, enrollment.Day, enrollment.Cycle, enrollment.Status).
+			Scan(&enrollment.ID, &enrollment.StartedAt, &enrollment.UpdatedAt)
+		if err != nil {
+			return mapError(err, "active enrollment", enrollment.UserID)
+		}
+		return saveTrainingMaxes(ctx, tx, enrollment)
+	})
+	if err != nil {
+		return models.Enrollment{}, err
+	}
+	return enrollment, nil
+}
+
+// GetActive returns the user's active enrollment with its training maxes
+func (r *EnrollmentRepository) GetActive(ctx context.Context, userID int) (models.Enrollment, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT id, user_id, program_id, week, day, cycle, status, workout_id, started_at, updated_at, ended_at
+		FROM program_enrollments WHERE user_id = $1 AND status = 'active'`
+	var e models.Enrollment
+	err := r.db.QueryRowContext(ctx, query, userID).
+		Scan(&e.ID, &e.UserID, &e.ProgramID, &e.Week, &e.Day, &e.Cycle, &e.Status, &e.WorkoutID, &e.StartedAt, &e.UpdatedAt, &e.EndedAt)
+	if err != nil {
+		return models.Enrollment{}, mapError(err, "active enrollment", nil)
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT exercise_id, weight FROM enrollment_training_maxes WHERE enrollment_id = $1 ORDER BY exercise_id", e.ID)
+	if err != nil {
+		return models.Enrollment{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tm models.TrainingMax
+		if err := rows.Scan(&tm.ExerciseID, &tm.Weight); err != nil {
+			return models.Enrollment{}, err
+		}
+		e.TrainingMaxes = append(e.TrainingMaxes, tm)
+	}
+	if err := rows.Err(); err != nil {
+		return models.Enrollment{}, err
+	}
+	return e, nil
+}
+
+// Update saves the enrollment's position, status and workout together with
+// its training maxes
+func (r *EnrollmentRepository) Update(ctx context.Context, enrollment models.Enrollment) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := `UPDATE program_enrollments SET week = $1, day = $2, cycle = $3, status = $4, workout_id = $5, ended_at = $6,
+			updated_at = CURRENT_TIMESTAMP WHERE id = $7`
+		result, err := tx.ExecContext(ctx, query, enrollment.Week, enrollment.Day, enrollment.Cycle, enrollment.Status,
+			enrollment.WorkoutID, enrollment.EndedAt, enrollment.ID)
+		if err != nil {
+			return mapError(err, "enrollment", enrollment.ID)
+		}
+		if err := expectAffected(result, "enrollment", enrollment.ID); err != nil {
+			return err
+		}
+		return saveTrainingMaxes(ctx, tx, enrollment)
+	})
+}
+
+// saveTrainingMaxes upserts the enrollment's training maxes
+func saveTrainingMaxes(ctx context.Context, tx *sql.Tx, enrollment models.Enrollment) error {
+	query := `INSERT INTO enrollment_training_maxes (enrollment_id, exercise_id, weight) VALUES ($1, $2, $3)
+		ON CONFLICT (enrollment_id, exercise_id) DO UPDATE SET weight = EXCLUDED.weight`
+	for _, tm := range enrollment.TrainingMaxes {
+		if _, err := tx.ExecContext(ctx, query, enrollment.ID, tm.ExerciseID, tm.Weight); err != nil {
+			return mapError(err, "training max", tm.ExerciseID)
+		}
+	}
+	return nil
+}
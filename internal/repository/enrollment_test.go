@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnrollmentRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewEnrollmentRepository(db)
+	now := time.Now()
+	enrollment := models.Enrollment{
+		UserID:        1,
+		ProgramID:     2,
+		Week:          1,
+		Day:           1,
+		Cycle:         1,
+		Status:        models.EnrollmentActive,
+		TrainingMaxes: []models.TrainingMax{{ExerciseID: 3, Weight: 140}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO program_enrollments").
+		WithArgs(1, 2, 1, 1, 1, models.EnrollmentActive).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "started_at", "updated_at"}).AddRow(9, now, now))
+	mock.ExpectExec("INSERT INTO enrollment_training_maxes (.+) ON CONFLICT").
+		WithArgs(9, 3, 140.0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	created, err := repo.Create(context.Background(), enrollment)
+	assert.NoError(t, err)
+	assert.Equal(t, 9, created.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEnrollmentRepository_Create_AlreadyEnrolled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewEnrollmentRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO program_enrollments").
+		WillReturnError(&pq.Error{Code: "23505"})
+	mock.ExpectRollback()
+
+	_, err = repo.Create(context.Background(), models.Enrollment{UserID: 1, ProgramID: 2, Week: 1, Day: 1, Cycle: 1, Status: models.EnrollmentActive})
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEnrollmentRepository_GetActive(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewEnrollmentRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("FROM program_enrollments WHERE user_id = \\$1 AND status = 'active'").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "program_id", "week", "day", "cycle", "status", "workout_id", "started_at", "updated_at", "ended_at"}).
+			AddRow(9, 1, 2, 3, 2, 1, "active", 12, now, now, nil))
+	mock.ExpectQuery("FROM enrollment_training_maxes WHERE enrollment_id = \\$1").
+		WithArgs(9).
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_id", "weight"}).AddRow(3, 140.0).AddRow(6, 95.0))
+
+	enrollment, err := repo.GetActive(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, enrollment.Week)
+	assert.Equal(t, 12, *enrollment.WorkoutID)
+	assert.Len(t, enrollment.TrainingMaxes, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEnrollmentRepository_GetActive_NotEnrolled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewEnrollmentRepository(db)
+
+	mock.ExpectQuery("FROM program_enrollments WHERE user_id = \\$1").
+		WithArgs(1).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetActive(context.Background(), 1)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEnrollmentRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewEnrollmentRepository(db)
+	enrollment := models.Enrollment{
+		ID:            9,
+		Week:          2,
+		Day:           1,
+		Cycle:         1,
+		Status:        models.EnrollmentActive,
+		TrainingMaxes: []models.TrainingMax{{ExerciseID: 3, Weight: 145}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE program_enrollments SET").
+		WithArgs(2, 1, 1, models.EnrollmentActive, nil, nil, 9).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO enrollment_training_maxes").
+		WithArgs(9, 3, 145.0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.Update(context.Background(), enrollment)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"workout-api/internal/apperrors"
+
+	"github.com/lib/pq"
+)
+
+// Postgres error codes that map onto domain errors
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+	stringTooLong       = "22001"
+	numericOutOfRange   = "22003"
+)
+
+// mapError translates driver errors into domain errors for the given
+// resource. Errors that have no domain meaning are returned unchanged.
+func mapError(err error, resource string, id any) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperrors.NotFound(resource, id)
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case uniqueViolation:
+			return apperrors.Conflict(resource + " already exists")
+		case foreignKeyViolation:
+			return apperrors.Conflict(resource + " references or is referenced by other records")
+		case stringTooLong, numericOutOfRange:
+			return apperrors.New(apperrors.ErrValidation, resource+" has a value too large to store")
+		}
+	}
+	return err
+}
+
+// expectAffected returns a not found error when a write touched no rows
+func expectAffected(result sql.Result, resource string, id any) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return apperrors.NotFound(resource, id)
+	}
+	return nil
+}
@@ -1,8 +1,17 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
 	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+
+	"github.com/lib/pq"
 )
 
 type ExerciseRepository struct {
@@ -13,70 +22,473 @@ func NewExerciseRepository(db *sql.DB) *ExerciseRepository {
 	return &ExerciseRepository{db: db}
 }
 
-func (r *ExerciseRepository) Create(exercise models.Exercise) error {
-	query := "INSERT INTO exercises (name, muscle_group, equipment_type, notes) VALUES ($1, $2, $3, $4)"
-	_, err := r.db.Exec(query, exercise.Name, exercise.MuscleGroup, exercise.EquipmentType, exercise.Notes)
-	return err
+// Create inserts the exercise and its muscle mappings in one transaction
+func (r *ExerciseRepository) Create(ctx context.Context, exercise models.Exercise) (models.Exercise, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := "INSERT INTO exercises (name, muscle_group, equipment_type, tracking_type, notes, instructions, owner_id, visibility) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at, updated_at"
+		err := tx.QueryRowContext(ctx, query, exercise.Name, exercise.MuscleGroup, exercise.EquipmentType, exercise.TrackingType, exercise.Notes, exercise.Instructions, exercise.OwnerID, exercise.Visibility).
+			Scan(&exercise.ID, &exercise.CreatedAt, &exercise.UpdatedAt)
+		if err != nil {
+			return mapError(err, "exercise", exercise.Name)
+		}
+		return insertMuscles(ctx, tx, exercise.ID, exercise.Muscles)
+	})
+	if err != nil {
+		return models.Exercise{}, err
+	}
+	return exercise, nil
 }
 
-func (r *ExerciseRepository) GetById(id int) (models.Exercise, error) {
-	query := "SELECT id, name, muscle_group, equipment_type, notes, created_at, updated_at FROM exercises WHERE id = $1"
+func (r *ExerciseRepository) GetById(ctx context.Context, id int) (models.Exercise, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + exerciseColumns + " FROM exercises WHERE id = $1"
 	e := &models.Exercise{}
-	err := r.db.QueryRow(query, id).Scan(&e.ID, &e.Name, &e.MuscleGroup, &e.EquipmentType, &e.Notes, &e.CreatedAt, &e.UpdatedAt)
-	if err == sql.ErrNoRows {
-		return models.Exercise{}, nil
+	err := scanExercise(r.db.QueryRowContext(ctx, query, id), e)
+	if err != nil {
+		return models.Exercise{}, mapError(err, "exercise", id)
+	}
+
+	muscles, err := r.loadMuscles(ctx, []int{id})
+	if err != nil {
+		return models.Exercise{}, err
 	}
-	return *e, err
+	e.Muscles = muscles[id]
+	return *e, nil
 }
 
-func (r *ExerciseRepository) GetAll() ([]models.Exercise, error) {
-	query := "SELECT id, name, muscle_group, equipment_type, notes, created_at, updated_at FROM exercises"
-	rows, err := r.db.Query(query)
+// ListUsedBy returns the user's own exercises together with every other
+// exercise their logged sets use, for exporting the account
+func (r *ExerciseRepository) ListUsedBy(ctx context.Context, userID int) ([]models.Exercise, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + exerciseColumns + ` FROM exercises
+		WHERE owner_id = $1 OR id IN (SELECT s.exercise_id FROM workout_sets s JOIN workouts w ON w.id = s.workout_id WHERE w.user_id = $1)
+		ORDER BY id`
+	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var exercises []models.Exercise
+	exercises := []models.Exercise{}
 	for rows.Next() {
 		var e models.Exercise
-		err := rows.Scan(&e.ID, &e.Name, &e.MuscleGroup, &e.EquipmentType, &e.Notes, &e.CreatedAt, &e.UpdatedAt)
-		if err != nil {
+		if err := scanExercise(rows, &e); err != nil {
 			return nil, err
 		}
 		exercises = append(exercises, e)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.attachMuscles(ctx, exercises); err != nil {
+		return nil, err
+	}
 	return exercises, nil
 }
 
-func (r *ExerciseRepository) GetByMuscleGroup(muscleGroup string) ([]models.Exercise, error) {
-	query := "SELECT id, name, muscle_group, equipment_type, notes, created_at, updated_at FROM exercises WHERE muscle_group = $1"
-	rows, err := r.db.Query(query, muscleGroup)
+// ExerciseListSpec whitelists the sort orders accepted by List
+var ExerciseListSpec = pagination.Spec{
+	Fields: map[string]pagination.Field{
+		"id":         {Column: "id", Type: "integer"},
+		"name":       {Column: "name", Type: "text"},
+		"created_at": {Column: "created_at", Type: "timestamp"},
+	},
+	DefaultSort: "name",
+}
+
+// List returns one page of the exercises viewer can see matching filter
+func (r *ExerciseRepository) List(ctx context.Context, viewer models.Viewer, filter models.ExerciseFilter, params pagination.Params) (pagination.Page[models.Exercise], error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var (
+		where []string
+		args  []any
+	)
+	if !viewer.IsAdmin() {
+		args = append(args, viewer.UserID)
+		where = append(where, visibleTo("visibility", "owner_id", len(args)))
+	}
+	if filter.OwnedOnly {
+		args = append(args, viewer.UserID)
+		where = append(where, fmt.Sprintf("owner_id = $%d", len(args)))
+	}
+	if filter.Visibility != "" {
+		args = append(args, filter.Visibility)
+		where = append(where, fmt.Sprintf("visibility = $%d", len(args)))
+	}
+	if filter.Name != "" {
+		args = append(args, filter.Name)
+		where = append(where, fmt.Sprintf("strpos(lower(name), lower($%d)) > 0", len(args)))
+	}
+	if filter.MuscleGroup != "" {
+		args = append(args, filter.MuscleGroup)
+		where = append(where, trainsMuscle("exercises.id", len(args), filter.IncludeSecondary))
+	}
+	if filter.EquipmentType != "" {
+		args = append(args, filter.EquipmentType)
+		where = append(where, fmt.Sprintf("equipment_type = $%d", len(args)))
+	}
+
+	query, args := ExerciseListSpec.Apply("SELECT "+exerciseColumns+" FROM exercises", where, args, params)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.Exercise]{}, err
 	}
 	defer rows.Close()
 
 	var exercises []models.Exercise
 	for rows.Next() {
 		var e models.Exercise
-		err := rows.Scan(&e.ID, &e.Name, &e.MuscleGroup, &e.EquipmentType, &e.Notes, &e.CreatedAt, &e.UpdatedAt)
+		if err := scanExercise(rows, &e); err != nil {
+			return pagination.Page[models.Exercise]{}, err
+		}
+		exercises = append(exercises, e)
+	}
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.Exercise]{}, err
+	}
+
+	page := pagination.NewPage(exercises, params, exerciseKey)
+	if err := r.attachMuscles(ctx, page.Items); err != nil {
+		return pagination.Page[models.Exercise]{}, err
+	}
+	return page, nil
+}
+
+func exerciseKey(e models.Exercise, sort string) (string, int) {
+	switch sort {
+	case "name":
+		return e.Name, e.ID
+	case "created_at":
+		return timeKey(e.CreatedAt), e.ID
+	default:
+		return strconv.Itoa(e.ID), e.ID
+	}
+}
+
+// searchSimilarityThreshold is the minimum trigram word similarity for a
+// name to count as a fuzzy match, low enough to catch "dumbell" for "Dumbbell"
+const searchSimilarityThreshold = 0.4
+
+// ts_headline copies exercise text as it is, so hits are marked with control
+// characters and the text is HTML-escaped before they become <mark> tags
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+var highlightMarks = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+
+// markHighlight escapes a ts_headline result and wraps its hits in <mark>
+func markHighlight(s string) string {
+	return highlightMarks.Replace(html.EscapeString(s))
+}
+
+// Search ranks exercises against a free-text query. Matches come from the
+// full-text index over name and notes or from trigram similarity on the
+// name, so misspellings and partial words still find results.
+func (r *ExerciseRepository) Search(ctx context.Context, viewer models.Viewer, search models.ExerciseSearch) ([]models.ExerciseSearchResult, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	args := []any{search.Query, searchSimilarityThreshold}
+	where := []string{"(e.search_vector @@ q.tsq OR word_similarity($1, e.name) >= $2)"}
+	if !viewer.IsAdmin() {
+		args = append(args, viewer.UserID)
+		where = append(where, visibleTo("e.visibility", "e.owner_id", len(args)))
+	}
+	if search.MuscleGroup != "" {
+		args = append(args, search.MuscleGroup)
+		where = append(where, trainsMuscle("e.id", len(args), false))
+	}
+	if search.EquipmentType != "" {
+		args = append(args, search.EquipmentType)
+		where = append(where, fmt.Sprintf("e.equipment_type = $%d", len(args)))
+	}
+	args = append(args, search.Limit)
+	selectors := "StartSel=" + highlightStart + ", StopSel=" + highlightStop
+	args = append(args, selectors+", HighlightAll=true", selectors+", MaxFragments=2")
+
+	query := fmt.Sprintf(`WITH q AS (SELECT websearch_to_tsquery('english', $1) AS tsq)
+		SELECT e.id, COALESCE(e.slug, ''), e.name, e.muscle_group, e.equipment_type, e.tracking_type, e.notes, e.instructions, e.owner_id, e.visibility, e.created_at, e.updated_at,
+			ts_rank(e.search_vector, q.tsq) + word_similarity($1, e.name) AS rank,
+			ts_headline('english', e.name, q.tsq, $%d),
+			ts_headline('english', coalesce(e.notes, ''), q.tsq, $%d)
+		FROM exercises e, q
+		WHERE %s
+		ORDER BY rank DESC, e.id
+		LIMIT $%d`, len(args)-1, len(args), strings.Join(where, " AND "), len(args)-2)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []models.ExerciseSearchResult
+	for rows.Next() {
+		var res models.ExerciseSearchResult
+		err := rows.Scan(&res.ID, &res.Slug, &res.Name, &res.MuscleGroup, &res.EquipmentType, &res.TrackingType, &res.Notes, &res.Instructions, &res.OwnerID, &res.Visibility, &res.CreatedAt, &res.UpdatedAt,
+			&res.Rank, &res.NameHighlight, &res.NotesHighlight)
 		if err != nil {
 			return nil, err
 		}
-		exercises = append(exercises, e)
+		res.NameHighlight, res.NotesHighlight = markHighlight(res.NameHighlight), markHighlight(res.NotesHighlight)
+		results = append(results, res)
 	}
-	return exercises, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(results))
+	for _, res := range results {
+		ids = append(ids, res.ID)
+	}
+	muscles, err := r.loadMuscles(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Muscles = muscles[results[i].ID]
+	}
+	return results, nil
 }
 
-func (r *ExerciseRepository) Update(exercise models.Exercise) error {
-	query := "UPDATE exercises SET name = $1, muscle_group = $2, equipment_type = $3, notes = $4, updated_at = CURRENT_TIMESTAMP WHERE id = $5"
-	_, err := r.db.Exec(query, exercise.Name, exercise.MuscleGroup, exercise.EquipmentType, exercise.Notes, exercise.ID)
-	return err
+// MatchNames maps exported exercise names onto the global exercises and
+// the user's own. names maps each name to its normalized form; a name
+// matches an exercise with the same name exactly, or whose normalized name
+// is at least threshold similar. Unmatched names are left out.
+func (r *ExerciseRepository) MatchNames(ctx context.Context, userID int, names map[string]string, threshold float64) (map[string]models.ExerciseMatch, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	originals := make([]string, 0, len(names))
+	for name := range names {
+		originals = append(originals, name)
+	}
+	sort.Strings(originals)
+	normalized := make([]string, 0, len(names))
+	for _, name := range originals {
+		normalized = append(normalized, names[name])
+	}
+
+	// Exact names win, then similarity; the user's own exercises break ties
+	query := `WITH candidates AS (
+			SELECT n.name, e.id, e.name AS exercise_name, e.tracking_type, e.owner_id,
+				CASE WHEN lower(e.name) = lower(n.name) THEN 1
+					ELSE similarity(trim(regexp_replace(lower(e.name), '[^a-z0-9]+', ' ', 'g')), n.normalized) END AS score
+			FROM unnest($1::text[], $2::text[]) AS n(name, normalized)
+			JOIN exercises e ON e.visibility = 'global' OR e.owner_id = $3
+		)
+		SELECT DISTINCT ON (name) name, id, exercise_name, tracking_type, score
+		FROM candidates
+		WHERE score >= $4
+		ORDER BY name, score DESC, owner_id IS NULL, id`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(originals), pq.Array(normalized), userID, threshold)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := map[string]models.ExerciseMatch{}
+	for rows.Next() {
+		var name string
+		var m models.ExerciseMatch
+		if err := rows.Scan(&name, &m.ExerciseID, &m.ExerciseName, &m.TrackingType, &m.Score); err != nil {
+			return nil, err
+		}
+		matches[name] = m
+	}
+	return matches, rows.Err()
 }
 
-func (r *ExerciseRepository) Delete(id int) error {
+// Update rewrites the exercise and replaces its muscle mappings
+func (r *ExerciseRepository) Update(ctx context.Context, exercise models.Exercise) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := "UPDATE exercises SET name = $1, muscle_group = $2, equipment_type = $3, tracking_type = $4, notes = $5, instructions = $6, visibility = $7, updated_at = CURRENT_TIMESTAMP WHERE id = $8"
+		result, err := tx.ExecContext(ctx, query, exercise.Name, exercise.MuscleGroup, exercise.EquipmentType, exercise.TrackingType, exercise.Notes, exercise.Instructions, exercise.Visibility, exercise.ID)
+		if err != nil {
+			return mapError(err, "exercise", exercise.ID)
+		}
+		if err := expectAffected(result, "exercise", exercise.ID); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM exercise_muscles WHERE exercise_id = $1", exercise.ID); err != nil {
+			return err
+		}
+		return insertMuscles(ctx, tx, exercise.ID, exercise.Muscles)
+	})
+}
+
+// UpsertCatalog writes global catalog entries keyed by slug in one
+// transaction, inserting new slugs and overwriting existing entries along
+// with their muscle mappings
+func (r *ExerciseRepository) UpsertCatalog(ctx context.Context, exercises []models.Exercise) ([]models.CatalogUpsert, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	results := make([]models.CatalogUpsert, 0, len(exercises))
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := `
+			INSERT INTO exercises (slug, name, muscle_group, equipment_type, tracking_type, notes, instructions, visibility)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, 'global')
+			ON CONFLICT (slug) WHERE slug IS NOT NULL DO UPDATE SET
+				name = EXCLUDED.name,
+				muscle_group = EXCLUDED.muscle_group,
+				equipment_type = EXCLUDED.equipment_type,
+				tracking_type = EXCLUDED.tracking_type,
+				notes = EXCLUDED.notes,
+				instructions = EXCLUDED.instructions,
+				updated_at = CURRENT_TIMESTAMP
+			RETURNING id, xmax = 0`
+
+		for _, e := range exercises {
+			var res models.CatalogUpsert
+			err := tx.QueryRowContext(ctx, query, e.Slug, e.Name, e.MuscleGroup, e.EquipmentType, e.TrackingType, e.Notes, e.Instructions).
+				Scan(&res.ID, &res.Created)
+			if err != nil {
+				return mapError(err, "exercise", e.Slug)
+			}
+
+			if _, err := tx.ExecContext(ctx, "DELETE FROM exercise_muscles WHERE exercise_id = $1", res.ID); err != nil {
+				return err
+			}
+			if err := insertMuscles(ctx, tx, res.ID, e.Muscles); err != nil {
+				return err
+			}
+			results = append(results, res)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Promote moves a user exercise into the global catalog, detaching it from
+// its owner
+func (r *ExerciseRepository) Promote(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE exercises SET visibility = 'global', owner_id = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = $1"
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return mapError(err, "exercise", id)
+	}
+	return expectAffected(result, "exercise", id)
+}
+
+func (r *ExerciseRepository) Delete(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := "DELETE FROM exercises WHERE id = $1"
-	_, err := r.db.Exec(query, id)
-	return err
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return mapError(err, "exercise", id)
+	}
+	return expectAffected(result, "exercise", id)
+}
+
+const exerciseColumns = "id, COALESCE(slug, ''), name, muscle_group, equipment_type, tracking_type, notes, instructions, owner_id, visibility, created_at, updated_at"
+
+// scanExercise reads a row selected with exerciseColumns
+func scanExercise(row interface{ Scan(dest ...any) error }, e *models.Exercise) error {
+	return row.Scan(&e.ID, &e.Slug, &e.Name, &e.MuscleGroup, &e.EquipmentType, &e.TrackingType, &e.Notes, &e.Instructions, &e.OwnerID, &e.Visibility, &e.CreatedAt, &e.UpdatedAt)
+}
+
+// visibleTo is a filter condition limiting exercises to those the user in
+// parameter argN may see: everything not private, plus their own
+func visibleTo(visibility, ownerID string, argN int) string {
+	return fmt.Sprintf("(%s <> 'private' OR %s = $%d)", visibility, ownerID, argN)
+}
+
+// trainsMuscle is a filter condition matching exercises that train the
+// muscle group slug in parameter argN
+func trainsMuscle(exerciseID string, argN int, includeSecondary bool) string {
+	role := " AND em.role = 'primary'"
+	if includeSecondary {
+		role = ""
+	}
+	return fmt.Sprintf(`EXISTS (SELECT 1 FROM exercise_muscles em JOIN muscle_groups mg ON mg.id = em.muscle_group_id
+		WHERE em.exercise_id = %s AND mg.slug = $%d%s)`, exerciseID, argN, role)
+}
+
+func insertMuscles(ctx context.Context, tx *sql.Tx, exerciseID int, muscles []models.ExerciseMuscle) error {
+	if len(muscles) == 0 {
+		return nil
+	}
+
+	slugs := make([]string, 0, len(muscles))
+	roles := make([]string, 0, len(muscles))
+	for _, m := range muscles {
+		slugs = append(slugs, m.MuscleGroup)
+		roles = append(roles, m.Role)
+	}
+
+	query := `INSERT INTO exercise_muscles (exercise_id, muscle_group_id, role)
+		SELECT $1, mg.id, m.role FROM unnest($2::text[], $3::text[]) AS m(slug, role)
+		JOIN muscle_groups mg ON mg.slug = m.slug`
+	_, err := tx.ExecContext(ctx, query, exerciseID, pq.Array(slugs), pq.Array(roles))
+	return mapError(err, "exercise muscle", exerciseID)
+}
+
+// loadMuscles fetches the muscle mappings of several exercises in one query,
+// primary muscles first
+func (r *ExerciseRepository) loadMuscles(ctx context.Context, exerciseIDs []int) (map[int][]models.ExerciseMuscle, error) {
+	muscles := make(map[int][]models.ExerciseMuscle, len(exerciseIDs))
+	if len(exerciseIDs) == 0 {
+		return muscles, nil
+	}
+
+	query := `SELECT em.exercise_id, mg.slug, em.role FROM exercise_muscles em
+		JOIN muscle_groups mg ON mg.id = em.muscle_group_id
+		WHERE em.exercise_id = ANY($1)
+		ORDER BY em.exercise_id, em.role, mg.slug`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(exerciseIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id int
+			m  models.ExerciseMuscle
+		)
+		if err := rows.Scan(&id, &m.MuscleGroup, &m.Role); err != nil {
+			return nil, err
+		}
+		muscles[id] = append(muscles[id], m)
+	}
+	return muscles, rows.Err()
+}
+
+func (r *ExerciseRepository) attachMuscles(ctx context.Context, exercises []models.Exercise) error {
+	ids := make([]int, 0, len(exercises))
+	for _, e := range exercises {
+		ids = append(ids, e.ID)
+	}
+	muscles, err := r.loadMuscles(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range exercises {
+		exercises[i].Muscles = muscles[exercises[i].ID]
+	}
+	return nil
 }
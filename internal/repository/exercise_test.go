@@ -1,34 +1,88 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"regexp"
 	"testing"
 	"time"
+	"workout-api/internal/apperrors"
 	"workout-api/internal/models"
+	"workout-api/internal/pagination"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
+var exerciseColumnNames = []string{"id", "slug", "name", "muscle_group", "equipment_type", "tracking_type", "notes", "instructions", "owner_id", "visibility", "created_at", "updated_at"}
+
 func TestExerciseRepository_Create(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	repo := NewExerciseRepository(db)
+	expectedTime := time.Now()
 	exercise := models.Exercise{
 		Name:          "Push-ups",
-		MuscleGroup:   "Chest",
-		EquipmentType: "Bodyweight",
+		MuscleGroup:   "chest",
+		EquipmentType: "bodyweight",
 		Notes:         "Standard push-ups",
+		Visibility:    models.VisibilityGlobal,
+		Muscles: []models.ExerciseMuscle{
+			{MuscleGroup: "chest", Role: models.MuscleRolePrimary},
+			{MuscleGroup: "triceps", Role: models.MuscleRoleSecondary},
+		},
 	}
 
-	mock.ExpectExec("INSERT INTO exercises").
-		WithArgs(exercise.Name, exercise.MuscleGroup, exercise.EquipmentType, exercise.Notes).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO exercises").
+		WithArgs(exercise.Name, exercise.MuscleGroup, exercise.EquipmentType, exercise.TrackingType, exercise.Notes, exercise.Instructions, exercise.OwnerID, exercise.Visibility).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(1, expectedTime, expectedTime))
+	mock.ExpectExec("INSERT INTO exercise_muscles").
+		WithArgs(1, pq.Array([]string{"chest", "triceps"}), pq.Array([]string{"primary", "secondary"})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	created, err := repo.Create(context.Background(), exercise)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created.ID)
+	assert.Equal(t, exercise.Muscles, created.Muscles)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExerciseRepository_Create_UnknownMuscleGroup(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewExerciseRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO exercises").
+		WillReturnError(&pq.Error{Code: "23503"})
+	mock.ExpectRollback()
+
+	_, err = repo.Create(context.Background(), models.Exercise{Name: "Push-ups", MuscleGroup: "spleen", EquipmentType: "bodyweight"})
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
-	err = repo.Create(exercise)
+func TestExerciseRepository_Create_NameTooLong(t *testing.T) {
+	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewExerciseRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO exercises").
+		WillReturnError(&pq.Error{Code: "22001"})
+	mock.ExpectRollback()
+
+	_, err = repo.Create(context.Background(), models.Exercise{Name: "Push-ups", MuscleGroup: "chest", EquipmentType: "bodyweight"})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -39,25 +93,38 @@ func TestExerciseRepository_GetById(t *testing.T) {
 
 	repo := NewExerciseRepository(db)
 	expectedTime := time.Now()
+	ownerID := 7
 	expectedExercise := models.Exercise{
 		ID:            1,
 		Name:          "Push-ups",
-		MuscleGroup:   "Chest",
-		EquipmentType: "Bodyweight",
+		MuscleGroup:   "chest",
+		EquipmentType: "bodyweight",
+		TrackingType:  models.TrackingRepsOnly,
 		Notes:         "Standard push-ups",
-		CreatedAt:     expectedTime,
-		UpdatedAt:     expectedTime,
+		Muscles: []models.ExerciseMuscle{
+			{MuscleGroup: "chest", Role: models.MuscleRolePrimary},
+			{MuscleGroup: "triceps", Role: models.MuscleRoleSecondary},
+		},
+		OwnerID:    &ownerID,
+		Visibility: models.VisibilityShared,
+		CreatedAt:  expectedTime,
+		UpdatedAt:  expectedTime,
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "name", "muscle_group", "equipment_type", "notes", "created_at", "updated_at"}).
-		AddRow(expectedExercise.ID, expectedExercise.Name, expectedExercise.MuscleGroup,
-			expectedExercise.EquipmentType, expectedExercise.Notes, expectedExercise.CreatedAt, expectedExercise.UpdatedAt)
+	rows := sqlmock.NewRows(exerciseColumnNames).
+		AddRow(expectedExercise.ID, "", expectedExercise.Name, expectedExercise.MuscleGroup, expectedExercise.EquipmentType, expectedExercise.TrackingType,
+			expectedExercise.Notes, "", ownerID, "shared", expectedExercise.CreatedAt, expectedExercise.UpdatedAt)
 
 	mock.ExpectQuery("SELECT (.+) FROM exercises WHERE id = \\$1").
 		WithArgs(1).
 		WillReturnRows(rows)
+	mock.ExpectQuery("FROM exercise_muscles").
+		WithArgs(pq.Array([]int{1})).
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_id", "slug", "role"}).
+			AddRow(1, "chest", "primary").
+			AddRow(1, "triceps", "secondary"))
 
-	exercise, err := repo.GetById(1)
+	exercise, err := repo.GetById(context.Background(), 1)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedExercise, exercise)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -74,13 +141,13 @@ func TestExerciseRepository_GetById_NotFound(t *testing.T) {
 		WithArgs(1).
 		WillReturnError(sql.ErrNoRows)
 
-	exercise, err := repo.GetById(1)
-	assert.NoError(t, err)
+	exercise, err := repo.GetById(context.Background(), 1)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
 	assert.Equal(t, models.Exercise{}, exercise)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestExerciseRepository_GetAll(t *testing.T) {
+func TestExerciseRepository_List(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
@@ -88,22 +155,34 @@ func TestExerciseRepository_GetAll(t *testing.T) {
 	repo := NewExerciseRepository(db)
 	expectedTime := time.Now()
 
-	rows := sqlmock.NewRows([]string{"id", "name", "muscle_group", "equipment_type", "notes", "created_at", "updated_at"}).
-		AddRow(1, "Push-ups", "Chest", "Bodyweight", "Standard push-ups", expectedTime, expectedTime).
-		AddRow(2, "Squats", "Legs", "Bodyweight", "Basic squats", expectedTime, expectedTime)
+	rows := sqlmock.NewRows(exerciseColumnNames).
+		AddRow(1, "", "Push-ups", "Chest", "Bodyweight", "weight_reps", "Standard push-ups", "", nil, "global", expectedTime, expectedTime).
+		AddRow(2, "", "Squats", "Legs", "Bodyweight", "weight_reps", "Basic squats", "", 5, "private", expectedTime, expectedTime)
 
-	mock.ExpectQuery("SELECT (.+) FROM exercises").
+	mock.ExpectQuery("SELECT (.+) FROM exercises ORDER BY name ASC, id ASC LIMIT 21").
 		WillReturnRows(rows)
+	mock.ExpectQuery("FROM exercise_muscles").
+		WithArgs(pq.Array([]int{1, 2})).
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_id", "slug", "role"}).
+			AddRow(1, "chest", "primary").
+			AddRow(2, "quads", "primary"))
+
+	params, err := ExerciseListSpec.Parse(pagination.Query{})
+	assert.NoError(t, err)
 
-	exercises, err := repo.GetAll()
+	// Admins see every exercise, so no visibility condition is applied
+	page, err := repo.List(context.Background(), models.Viewer{UserID: 1, Role: models.RoleAdmin}, models.ExerciseFilter{}, params)
 	assert.NoError(t, err)
-	assert.Len(t, exercises, 2)
-	assert.Equal(t, "Push-ups", exercises[0].Name)
-	assert.Equal(t, "Squats", exercises[1].Name)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, "Push-ups", page.Items[0].Name)
+	assert.Equal(t, "Squats", page.Items[1].Name)
+	assert.Equal(t, []models.ExerciseMuscle{{MuscleGroup: "quads", Role: "primary"}}, page.Items[1].Muscles)
+	assert.Empty(t, page.NextCursor)
+	assert.Empty(t, page.PrevCursor)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestExerciseRepository_GetByMuscleGroup(t *testing.T) {
+func TestExerciseRepository_List_FiltersAndCursor(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
@@ -111,19 +190,35 @@ func TestExerciseRepository_GetByMuscleGroup(t *testing.T) {
 	repo := NewExerciseRepository(db)
 	expectedTime := time.Now()
 
-	rows := sqlmock.NewRows([]string{"id", "name", "muscle_group", "equipment_type", "notes", "created_at", "updated_at"}).
-		AddRow(1, "Push-ups", "Chest", "Bodyweight", "Standard push-ups", expectedTime, expectedTime).
-		AddRow(2, "Bench Press", "Chest", "Barbell", "Heavy bench press", expectedTime, expectedTime)
+	cursor := pagination.Cursor{Sort: "name", Value: "Bench Press", ID: 3}.Encode()
+	params, err := ExerciseListSpec.Parse(pagination.Query{Limit: "2", Cursor: cursor})
+	assert.NoError(t, err)
+
+	// Three rows for a limit of two means another page follows
+	rows := sqlmock.NewRows(exerciseColumnNames).
+		AddRow(4, "", "Cable Fly", "Chest", "Cable", "weight_reps", "", "", nil, "global", expectedTime, expectedTime).
+		AddRow(1, "", "Push-ups", "Chest", "Bodyweight", "weight_reps", "", "", 9, "private", expectedTime, expectedTime).
+		AddRow(7, "", "Svend Press", "Chest", "Plate", "weight_reps", "", "", nil, "global", expectedTime, expectedTime)
 
-	mock.ExpectQuery("SELECT (.+) FROM exercises WHERE muscle_group = \\$1").
-		WithArgs("Chest").
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE (visibility <> 'private' OR owner_id = $1) AND ")).
+		WithArgs(9, "chest", "Bench Press", 3).
 		WillReturnRows(rows)
+	mock.ExpectQuery("FROM exercise_muscles").
+		WithArgs(pq.Array([]int{4, 1})).
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_id", "slug", "role"}))
+
+	page, err := repo.List(context.Background(), models.Viewer{UserID: 9, Role: models.RoleUser}, models.ExerciseFilter{MuscleGroup: "chest"}, params)
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, "Push-ups", page.Items[1].Name)
+
+	next, err := pagination.DecodeCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, pagination.Cursor{Sort: "name", Value: "Push-ups", ID: 1}, next)
 
-	exercises, err := repo.GetByMuscleGroup("Chest")
+	prev, err := pagination.DecodeCursor(page.PrevCursor)
 	assert.NoError(t, err)
-	assert.Len(t, exercises, 2)
-	assert.Equal(t, "Chest", exercises[0].MuscleGroup)
-	assert.Equal(t, "Chest", exercises[1].MuscleGroup)
+	assert.Equal(t, pagination.Cursor{Sort: "name", Value: "Cable Fly", ID: 4, Before: true}, prev)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -139,13 +234,22 @@ func TestExerciseRepository_Update(t *testing.T) {
 		MuscleGroup:   "Chest",
 		EquipmentType: "Bodyweight",
 		Notes:         "Modified push-ups for beginners",
+		Muscles:       []models.ExerciseMuscle{{MuscleGroup: "chest", Role: models.MuscleRolePrimary}},
 	}
 
+	mock.ExpectBegin()
 	mock.ExpectExec("UPDATE exercises SET").
-		WithArgs(exercise.Name, exercise.MuscleGroup, exercise.EquipmentType, exercise.Notes, exercise.ID).
+		WithArgs(exercise.Name, exercise.MuscleGroup, exercise.EquipmentType, exercise.TrackingType, exercise.Notes, exercise.Instructions, exercise.Visibility, exercise.ID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE FROM exercise_muscles WHERE exercise_id = \\$1").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO exercise_muscles").
+		WithArgs(1, pq.Array([]string{"chest"}), pq.Array([]string{"primary"})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
-	err = repo.Update(exercise)
+	err = repo.Update(context.Background(), exercise)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -161,7 +265,216 @@ func TestExerciseRepository_Delete(t *testing.T) {
 		WithArgs(1).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = repo.Delete(1)
+	err = repo.Delete(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExerciseRepository_Update_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewExerciseRepository(db)
+	exercise := models.Exercise{ID: 999, Name: "Push-ups", MuscleGroup: "Chest", EquipmentType: "Bodyweight"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE exercises SET").
+		WithArgs(exercise.Name, exercise.MuscleGroup, exercise.EquipmentType, exercise.TrackingType, exercise.Notes, exercise.Instructions, exercise.Visibility, exercise.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = repo.Update(context.Background(), exercise)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExerciseRepository_Delete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewExerciseRepository(db)
+
+	mock.ExpectExec("DELETE FROM exercises WHERE id = \\$1").
+		WithArgs(999).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Delete(context.Background(), 999)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExerciseRepository_Search(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewExerciseRepository(db)
+	expectedTime := time.Now()
+
+	rows := sqlmock.NewRows(append(exerciseColumnNames, "rank", "name_hl", "notes_hl")).
+		AddRow(4, "", "Dumbbell Bench Press", "Chest", "Dumbbell", "weight_reps", "Flat bench", "", nil, "global", expectedTime, expectedTime, 0.92, "Dumbbell \x02Bench\x03 Press", "Flat \x02bench\x03")
+
+	mock.ExpectQuery("websearch_to_tsquery(.+)WHERE \\(e.search_vector @@ q.tsq OR word_similarity\\(\\$1, e.name\\) >= \\$2\\) AND \\(e.visibility <> 'private' OR e.owner_id = \\$3\\) AND e.equipment_type = \\$4(.+)LIMIT \\$5").
+		WithArgs("bench", searchSimilarityThreshold, 2, "Dumbbell", 10, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(rows)
+	mock.ExpectQuery("FROM exercise_muscles").
+		WithArgs(pq.Array([]int{4})).
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_id", "slug", "role"}).AddRow(4, "chest", "primary"))
+
+	results, err := repo.Search(context.Background(), models.Viewer{UserID: 2, Role: models.RoleUser}, models.ExerciseSearch{Query: "bench", EquipmentType: "Dumbbell", Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "Dumbbell Bench Press", results[0].Name)
+	assert.Equal(t, 0.92, results[0].Rank)
+	assert.Equal(t, "Dumbbell <mark>Bench</mark> Press", results[0].NameHighlight)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExerciseRepository_Search_EscapesHighlights(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewExerciseRepository(db)
+	expectedTime := time.Now()
+	name := `<script>alert("x")</script> Bench`
+
+	rows := sqlmock.NewRows(append(exerciseColumnNames, "rank", "name_hl", "notes_hl")).
+		AddRow(9, "", name, "Chest", "Barbell", "weight_reps", "<b>bench</b> & co", "", 3, "shared", expectedTime, expectedTime, 0.5,
+			`<script>alert("x")</script> `+"\x02Bench\x03", "<b>\x02bench\x03</b> & co")
+
+	mock.ExpectQuery(regexp.QuoteMeta("ts_headline('english', e.name, q.tsq, $4)")+"(.+)"+regexp.QuoteMeta("q.tsq, $5)")).
+		WithArgs("bench", searchSimilarityThreshold, 10, "StartSel=\x02, StopSel=\x03, HighlightAll=true", "StartSel=\x02, StopSel=\x03, MaxFragments=2").
+		WillReturnRows(rows)
+	mock.ExpectQuery("FROM exercise_muscles").
+		WithArgs(pq.Array([]int{9})).
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_id", "slug", "role"}))
+
+	results, err := repo.Search(context.Background(), models.Viewer{UserID: 1, Role: models.RoleAdmin}, models.ExerciseSearch{Query: "bench", Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, name, results[0].Name)
+		assert.Equal(t, "&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; <mark>Bench</mark>", results[0].NameHighlight)
+		assert.Equal(t, "&lt;b&gt;<mark>bench</mark>&lt;/b&gt; &amp; co", results[0].NotesHighlight)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExerciseRepository_List_OwnedOnly(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewExerciseRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE (visibility <> 'private' OR owner_id = $1) AND owner_id = $2 AND visibility = $3 ORDER BY name ASC")).
+		WithArgs(3, 3, "shared").
+		WillReturnRows(sqlmock.NewRows(exerciseColumnNames))
+
+	params, err := ExerciseListSpec.Parse(pagination.Query{})
+	assert.NoError(t, err)
+
+	page, err := repo.List(context.Background(), models.Viewer{UserID: 3, Role: models.RoleUser},
+		models.ExerciseFilter{OwnedOnly: true, Visibility: models.VisibilityShared}, params)
+	assert.NoError(t, err)
+	assert.Empty(t, page.Items)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExerciseRepository_Promote(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewExerciseRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE exercises SET visibility = 'global', owner_id = NULL")).
+		WithArgs(5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.Promote(context.Background(), 5)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExerciseRepository_UpsertCatalog(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewExerciseRepository(db)
+	exercises := []models.Exercise{
+		{Slug: "push-up", Name: "Push-up", MuscleGroup: "chest", EquipmentType: "bodyweight", TrackingType: models.TrackingRepsOnly, Instructions: "Push.",
+			Muscles: []models.ExerciseMuscle{{MuscleGroup: "chest", Role: models.MuscleRolePrimary}}},
+		{Slug: "dip", Name: "Dip", MuscleGroup: "chest", EquipmentType: "bodyweight", TrackingType: models.TrackingWeightReps,
+			Muscles: []models.ExerciseMuscle{{MuscleGroup: "chest", Role: models.MuscleRolePrimary}}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("ON CONFLICT (slug) WHERE slug IS NOT NULL DO UPDATE")).
+		WithArgs("push-up", "Push-up", "chest", "bodyweight", models.TrackingRepsOnly, "", "Push.").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created"}).AddRow(10, true))
+	mock.ExpectExec("DELETE FROM exercise_muscles").WithArgs(10).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO exercise_muscles").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO exercises").
+		WithArgs("dip", "Dip", "chest", "bodyweight", models.TrackingWeightReps, "", "").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created"}).AddRow(4, false))
+	mock.ExpectExec("DELETE FROM exercise_muscles").WithArgs(4).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO exercise_muscles").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	results, err := repo.UpsertCatalog(context.Background(), exercises)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.CatalogUpsert{{ID: 10, Created: true}, {ID: 4, Created: false}}, results)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExerciseRepository_MatchNames(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewExerciseRepository(db)
+	names := map[string]string{"Squat (Barbell)": "back squat", "Bench Press (Barbell)": "barbell bench press"}
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM unnest($1::text[], $2::text[])")+"(.+)"+regexp.QuoteMeta("WHERE score >= $4")).
+		WithArgs(pq.Array([]string{"Bench Press (Barbell)", "Squat (Barbell)"}), pq.Array([]string{"barbell bench press", "back squat"}), 5, 0.6).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "id", "exercise_name", "tracking_type", "score"}).
+			AddRow("Squat (Barbell)", 33, "Back Squat", models.TrackingWeightReps, 1.0))
+
+	matches, err := repo.MatchNames(context.Background(), 5, names, 0.6)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]models.ExerciseMatch{
+		"Squat (Barbell)": {ExerciseID: 33, ExerciseName: "Back Squat", TrackingType: models.TrackingWeightReps, Score: 1},
+	}, matches)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExerciseRepository_ListUsedBy(t *testing.T) {
+	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewExerciseRepository(db)
+	now := time.Now()
+	owner := 5
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE owner_id = $1 OR id IN (SELECT s.exercise_id FROM workout_sets s")).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows(exerciseColumnNames).
+			AddRow(3, "bench-press", "Bench Press", "chest", "barbell", models.TrackingWeightReps, "", "", nil, models.VisibilityGlobal, now, now).
+			AddRow(40, "", "Sled Push", "full-body", "other", models.TrackingDistanceDuration, "", "", owner, models.VisibilityPrivate, now, now))
+	mock.ExpectQuery("FROM exercise_muscles em").
+		WithArgs(pq.Array([]int{3, 40})).
+		WillReturnRows(sqlmock.NewRows([]string{"exercise_id", "slug", "role"}).AddRow(3, "chest", models.MuscleRolePrimary))
+
+	exercises, err := repo.ListUsedBy(context.Background(), 5)
+	assert.NoError(t, err)
+	if assert.Len(t, exercises, 2) {
+		assert.Len(t, exercises[0].Muscles, 1)
+		assert.True(t, exercises[1].OwnedBy(5))
+	}
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -1,23 +1,112 @@
 package repository
 
-import "workout-api/internal/models"
+import (
+	"context"
+	"time"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+)
 
 // UserRepositoryInterface defines the contract for user repository operations
 type UserRepositoryInterface interface {
-	Create(user models.User) error
-	GetById(id int) (models.User, error)
-	GetByEmail(email string) (models.User, error)
-	GetAll() ([]models.User, error)
-	Update(user models.User) error
-	Delete(id int) error
+	Create(ctx context.Context, user models.User) error
+	GetById(ctx context.Context, id int) (models.User, error)
+	GetByEmail(ctx context.Context, email string) (models.User, error)
+	List(ctx context.Context, filter models.UserFilter, params pagination.Params) (pagination.Page[models.User], error)
+	Update(ctx context.Context, user models.User) error
+	SetRole(ctx context.Context, id int, role string) error
+	Delete(ctx context.Context, id int) error
 }
 
 // ExerciseRepositoryInterface defines the contract for exercise repository operations
 type ExerciseRepositoryInterface interface {
-	Create(exercise models.Exercise) error
-	GetById(id int) (models.Exercise, error)
-	GetAll() ([]models.Exercise, error)
-	GetByMuscleGroup(muscleGroup string) ([]models.Exercise, error)
-	Update(exercise models.Exercise) error
-	Delete(id int) error
+	Create(ctx context.Context, exercise models.Exercise) (models.Exercise, error)
+	GetById(ctx context.Context, id int) (models.Exercise, error)
+	List(ctx context.Context, viewer models.Viewer, filter models.ExerciseFilter, params pagination.Params) (pagination.Page[models.Exercise], error)
+	Search(ctx context.Context, viewer models.Viewer, search models.ExerciseSearch) ([]models.ExerciseSearchResult, error)
+	Update(ctx context.Context, exercise models.Exercise) error
+	Promote(ctx context.Context, id int) error
+	UpsertCatalog(ctx context.Context, exercises []models.Exercise) ([]models.CatalogUpsert, error)
+	MatchNames(ctx context.Context, userID int, names map[string]string, threshold float64) (map[string]models.ExerciseMatch, error)
+	ListUsedBy(ctx context.Context, userID int) ([]models.Exercise, error)
+	Delete(ctx context.Context, id int) error
+}
+
+// TaxonomyRepositoryInterface defines the contract for muscle group and equipment lookups
+type TaxonomyRepositoryInterface interface {
+	ListMuscleGroups(ctx context.Context) ([]models.MuscleGroup, error)
+	ResolveMuscleGroup(ctx context.Context, name string) (models.MuscleGroup, error)
+	ListEquipment(ctx context.Context) ([]models.Equipment, error)
+	ResolveEquipment(ctx context.Context, name string) (models.Equipment, error)
+}
+
+// WorkoutRepositoryInterface defines the contract for workout and workout set operations
+type WorkoutRepositoryInterface interface {
+	Create(ctx context.Context, workout models.Workout) (models.Workout, error)
+	Import(ctx context.Context, workout models.Workout) (models.Workout, bool, error)
+	GetById(ctx context.Context, id int) (models.Workout, error)
+	ListByUser(ctx context.Context, userID int, filter models.WorkoutFilter, params pagination.Params) (pagination.Page[models.Workout], error)
+	ListAll(ctx context.Context, userID int) ([]models.Workout, error)
+	Finish(ctx context.Context, id int, finishedAt time.Time) error
+	Delete(ctx context.Context, id int) error
+	AddSet(ctx context.Context, set models.WorkoutSet) (models.WorkoutSet, error)
+	GetSets(ctx context.Context, workoutID int) ([]models.WorkoutSet, error)
+	GetPlannedSets(ctx context.Context, workoutID int) ([]models.PlannedSet, error)
+	LastPerformance(ctx context.Context, userID int, exerciseIDs []int) (map[int][]models.WorkoutSet, error)
+	DeleteSet(ctx context.Context, workoutID, setID int) error
+}
+
+// RoutineRepositoryInterface defines the contract for routine template operations
+type RoutineRepositoryInterface interface {
+	Create(ctx context.Context, routine models.Routine) (models.Routine, error)
+	GetById(ctx context.Context, id int) (models.Routine, error)
+	ListByUser(ctx context.Context, userID int, params pagination.Params) (pagination.Page[models.Routine], error)
+	Update(ctx context.Context, routine models.Routine) error
+	Delete(ctx context.Context, id int) error
+}
+
+// ProgramRepositoryInterface defines the contract for program data access
+type ProgramRepositoryInterface interface {
+	Create(ctx context.Context, program models.Program) (models.Program, error)
+	GetById(ctx context.Context, id int) (models.Program, error)
+	List(ctx context.Context, viewer models.Viewer, filter models.ProgramFilter, params pagination.Params) (pagination.Page[models.Program], error)
+	Update(ctx context.Context, program models.Program) error
+	Delete(ctx context.Context, id int) error
+}
+
+// EnrollmentRepositoryInterface defines the contract for program enrollment data access
+type EnrollmentRepositoryInterface interface {
+	Create(ctx context.Context, enrollment models.Enrollment) (models.Enrollment, error)
+	GetActive(ctx context.Context, userID int) (models.Enrollment, error)
+	Update(ctx context.Context, enrollment models.Enrollment) error
+}
+
+// AnalyticsRepositoryInterface defines the contract for training analytics queries
+type AnalyticsRepositoryInterface interface {
+	E1RMSets(ctx context.Context, userID int, q models.E1RMQuery, limit int) ([]models.SetE1RM, error)
+	E1RMSeries(ctx context.Context, userID int, q models.E1RMQuery) ([]models.E1RMPoint, error)
+	MuscleVolume(ctx context.Context, userID int, q models.MuscleVolumeQuery) ([]models.MuscleVolumeRow, error)
+}
+
+// PersonalRecordRepositoryInterface defines the contract for personal record data access
+type PersonalRecordRepositoryInterface interface {
+	PreviousBests(ctx context.Context, userID int, set models.WorkoutSet) (models.RecordBests, error)
+	Create(ctx context.Context, records []models.PersonalRecord) ([]models.PersonalRecord, error)
+	ListByUser(ctx context.Context, userID int, filter models.RecordFilter, params pagination.Params) (pagination.Page[models.PersonalRecord], error)
+}
+
+// ActivityRepositoryInterface defines the contract for uploaded activity data access
+type ActivityRepositoryInterface interface {
+	Create(ctx context.Context, workout models.Workout, activity models.Activity) (models.Activity, error)
+	GetById(ctx context.Context, id int) (models.Activity, error)
+	ListByUser(ctx context.Context, userID int, sport string, params pagination.Params) (pagination.Page[models.Activity], error)
+	Points(ctx context.Context, activityID int) ([]models.ActivityPoint, error)
+}
+
+// RefreshTokenRepositoryInterface defines the contract for refresh token storage
+type RefreshTokenRepositoryInterface interface {
+	Create(ctx context.Context, token models.RefreshToken) (models.RefreshToken, error)
+	GetByHash(ctx context.Context, tokenHash string) (models.RefreshToken, error)
+	Revoke(ctx context.Context, id int, replacedBy *int) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
 }
@@ -0,0 +1,9 @@
+package repository
+
+import "time"
+
+// timeKey formats a timestamp as a cursor value. Full precision matters:
+// a truncated value would skip or repeat rows that share a second.
+func timeKey(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
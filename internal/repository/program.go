@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+
+	"github.com/lib/pq"
+)
+
+const programColumns = "id, owner_id, name, description, visibility, repeats, rounding, created_at, updated_at"
+
+type ProgramRepository struct {
+	db *sql.DB
+}
+
+func NewProgramRepository(db *sql.DB) *ProgramRepository {
+	return &ProgramRepository{db: db}
+}
+
+func scanProgram(row interface{ Scan(dest ...any) error }, p *models.Program) error {
+	return row.Scan(&p.ID, &p.OwnerID, &p.Name, &p.Description, &p.Visibility, &p.Repeats, &p.Rounding, &p.CreatedAt, &p.UpdatedAt)
+}
+
+// Create inserts the program with its days, prescriptions and progression
+// rules in one transaction
+func (r *ProgramRepository) Create(ctx context.Context, program models.Program) (models.Program, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := `INSERT INTO programs (owner_id, name, description, visibility, repeats, rounding)
+			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at, updated_at`
+		err := tx.QueryRowContext(ctx, query, program.OwnerID, program.Name, program.Description, program.Visibility, program.Repeats, program.Rounding).
+			Scan(&program.ID, &program.CreatedAt, &program.UpdatedAt)
+		if err != nil {
+			return mapError(err, "program", program.Name)
+		}
+		return insertProgramChildren(ctx, tx, &program)
+	})
+	if err != nil {
+		return models.Program{}, err
+	}
+	return program, nil
+}
+
+// GetById returns the program with its days, prescriptions and progression rules
+func (r *ProgramRepository) GetById(ctx context.Context, id int) (models.Program, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var program models.Program
+	err := scanProgram(r.db.QueryRowContext(ctx, "SELECT "+programColumns+" FROM programs WHERE id = $1", id), &program)
+	if err != nil {
+		return models.Program{}, mapError(err, "program", id)
+	}
+
+	if program.Days, err = r.loadDays(ctx, id); err != nil {
+		return models.Program{}, err
+	}
+	if program.Progressions, err = r.loadProgressions(ctx, id); err != nil {
+		return models.Program{}, err
+	}
+	return program, nil
+}
+
+// ProgramListSpec whitelists the sort orders accepted by List
+var ProgramListSpec = pagination.Spec{
+	Fields: map[string]pagination.Field{
+		"id":         {Column: "id", Type: "integer"},
+		"name":       {Column: "name", Type: "text"},
+		"created_at": {Column: "created_at", Type: "timestamp"},
+	},
+	DefaultSort: "name",
+}
+
+// List returns one page of the programs the viewer may see. Days and
+// progression rules are left out; fetch a program by ID for those.
+func (r *ProgramRepository) List(ctx context.Context, viewer models.Viewer, filter models.ProgramFilter, params pagination.Params) (pagination.Page[models.Program], error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var (
+		where []string
+		args  []any
+	)
+	if !viewer.IsAdmin() {
+		args = append(args, viewer.UserID)
+		where = append(where, visibleTo("visibility", "owner_id", len(args)))
+	}
+	if filter.OwnedOnly {
+		args = append(args, viewer.UserID)
+		where = append(where, fmt.Sprintf("owner_id = $%d", len(args)))
+	}
+	if filter.Name != "" {
+		args = append(args, filter.Name)
+		where = append(where, fmt.Sprintf("strpos(lower(name), lower($%d)) > 0", len(args)))
+	}
+
+	query, args := ProgramListSpec.Apply("SELECT "+programColumns+" FROM programs", where, args, params)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return pagination.Page[models.Program]{}, err
+	}
+	defer rows.Close()
+
+	var programs []models.Program
+	for rows.Next() {
+		var program models.Program
+		if err := scanProgram(rows, &program); err != nil {
+			return pagination.Page[models.Program]{}, err
+		}
+		programs = append(programs, program)
+	}
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.Program]{}, err
+	}
+	return pagination.NewPage(programs, params, programKey), nil
+}
+
+func programKey(p models.Program, sort string) (string, int) {
+	switch sort {
+	case "name":
+		return p.Name, p.ID
+	case "created_at":
+		return timeKey(p.CreatedAt), p.ID
+	default:
+		return strconv.Itoa(p.ID), p.ID
+	}
+}
+
+// Update rewrites the program and replaces its days and progression rules
+func (r *ProgramRepository) Update(ctx context.Context, program models.Program) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := `UPDATE programs SET name = $1, description = $2, visibility = $3, repeats = $4, rounding = $5,
+			updated_at = CURRENT_TIMESTAMP WHERE id = $6`
+		result, err := tx.ExecContext(ctx, query, program.Name, program.Description, program.Visibility, program.Repeats, program.Rounding, program.ID)
+		if err != nil {
+			return mapError(err, "program", program.ID)
+		}
+		if err := expectAffected(result, "program", program.ID); err != nil {
+			return err
+		}
+
+		// Prescriptions go with their days through the cascade
+		if _, err := tx.ExecContext(ctx, "DELETE FROM program_days WHERE program_id = $1", program.ID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM program_progressions WHERE program_id = $1", program.ID); err != nil {
+			return err
+		}
+		return insertProgramChildren(ctx, tx, &program)
+	})
+}
+
+func (r *ProgramRepository) Delete(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM programs WHERE id = $1", id)
+	if err != nil {
+		return mapError(err, "program", id)
+	}
+	return expectAffected(result, "program", id)
+}
+
+// insertProgramChildren writes the program's days with their prescriptions
+// and its progression rules
+func insertProgramChildren(ctx context.Context, tx *sql.Tx, program *models.Program) error {
+	dayQuery := "INSERT INTO program_days (program_id, week, day, name, routine_id) VALUES ($1, $2, $3, $4, $5) RETURNING id"
+	rxQuery := `INSERT INTO program_prescriptions (program_day_id, exercise_id, set_number, percent_tm, reps, amrap)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	for i := range program.Days {
+		d := &program.Days[i]
+		d.ProgramID = program.ID
+		err := tx.QueryRowContext(ctx, dayQuery, d.ProgramID, d.Week, d.Day, d.Name, d.RoutineID).Scan(&d.ID)
+		if err != nil {
+			return mapError(err, "program day", fmt.Sprintf("%d/%d", d.Week, d.Day))
+		}
+		for j := range d.Prescriptions {
+			rx := &d.Prescriptions[j]
+			rx.ProgramDayID = d.ID
+			err := tx.QueryRowContext(ctx, rxQuery, rx.ProgramDayID, rx.ExerciseID, rx.SetNumber, rx.PercentTM, rx.Reps, rx.AMRAP).Scan(&rx.ID)
+			if err != nil {
+				return mapError(err, "prescription", rx.ExerciseID)
+			}
+		}
+	}
+
+	ruleQuery := "INSERT INTO program_progressions (program_id, exercise_id, increment, trigger) VALUES ($1, $2, $3, $4) RETURNING id"
+	for i := range program.Progressions {
+		rule := &program.Progressions[i]
+		rule.ProgramID = program.ID
+		err := tx.QueryRowContext(ctx, ruleQuery, rule.ProgramID, rule.ExerciseID, rule.Increment, rule.Trigger).Scan(&rule.ID)
+		if err != nil {
+			return mapError(err, "progression rule", rule.ExerciseID)
+		}
+	}
+	return nil
+}
+
+// loadDays returns the program's days in week and day order with their
+// prescriptions
+func (r *ProgramRepository) loadDays(ctx context.Context, programID int) ([]models.ProgramDay, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT id, program_id, week, day, name, routine_id FROM program_days WHERE program_id = $1 ORDER BY week, day", programID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		days []models.ProgramDay
+		ids  []int
+	)
+	for rows.Next() {
+		var d models.ProgramDay
+		if err := rows.Scan(&d.ID, &d.ProgramID, &d.Week, &d.Day, &d.Name, &d.RoutineID); err != nil {
+			return nil, err
+		}
+		days = append(days, d)
+		ids = append(ids, d.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(days) == 0 {
+		return days, nil
+	}
+
+	rxRows, err := r.db.QueryContext(ctx, `SELECT id, program_day_id, exercise_id, set_number, percent_tm, reps, amrap
+		FROM program_prescriptions WHERE program_day_id = ANY($1) ORDER BY program_day_id, exercise_id, set_number`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rxRows.Close()
+
+	prescriptions := map[int][]models.Prescription{}
+	for rxRows.Next() {
+		var rx models.Prescription
+		if err := rxRows.Scan(&rx.ID, &rx.ProgramDayID, &rx.ExerciseID, &rx.SetNumber, &rx.PercentTM, &rx.Reps, &rx.AMRAP); err != nil {
+			return nil, err
+		}
+		prescriptions[rx.ProgramDayID] = append(prescriptions[rx.ProgramDayID], rx)
+	}
+	if err := rxRows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range days {
+		days[i].Prescriptions = prescriptions[days[i].ID]
+	}
+	return days, nil
+}
+
+func (r *ProgramRepository) loadProgressions(ctx context.Context, programID int) ([]models.ProgressionRule, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT id, program_id, exercise_id, increment, trigger FROM program_progressions WHERE program_id = $1 ORDER BY id", programID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []models.ProgressionRule
+	for rows.Next() {
+		var rule models.ProgressionRule
+		if err := rows.Scan(&rule.ID, &rule.ProgramID, &rule.ExerciseID, &rule.Increment, &rule.Trigger); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
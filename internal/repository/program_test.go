@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var programRowColumns = []string{"id", "owner_id", "name", "description", "visibility", "repeats", "rounding", "created_at", "updated_at"}
+
+func TestProgramRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProgramRepository(db)
+	now := time.Now()
+	program := models.Program{
+		OwnerID:    1,
+		Name:       "5/3/1",
+		Visibility: models.VisibilityShared,
+		Repeats:    true,
+		Rounding:   2.5,
+		Days: []models.ProgramDay{{
+			Week:      1,
+			Day:       1,
+			RoutineID: 4,
+			Prescriptions: []models.Prescription{
+				{ExerciseID: 3, SetNumber: 1, PercentTM: 65, Reps: 5},
+				{ExerciseID: 3, SetNumber: 2, PercentTM: 85, Reps: 5, AMRAP: true},
+			},
+		}},
+		Progressions: []models.ProgressionRule{{ExerciseID: 3, Increment: 5, Trigger: models.ProgressOnCycle}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO programs").
+		WithArgs(1, "5/3/1", "", models.VisibilityShared, true, 2.5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(2, now, now))
+	mock.ExpectQuery("INSERT INTO program_days").
+		WithArgs(2, 1, 1, "", 4).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(30))
+	mock.ExpectQuery("INSERT INTO program_prescriptions").
+		WithArgs(30, 3, 1, 65.0, 5, false).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(40))
+	mock.ExpectQuery("INSERT INTO program_prescriptions").
+		WithArgs(30, 3, 2, 85.0, 5, true).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(41))
+	mock.ExpectQuery("INSERT INTO program_progressions").
+		WithArgs(2, 3, 5.0, models.ProgressOnCycle).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(50))
+	mock.ExpectCommit()
+
+	created, err := repo.Create(context.Background(), program)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, created.ID)
+	assert.Equal(t, 30, created.Days[0].ID)
+	assert.Equal(t, 30, created.Days[0].Prescriptions[1].ProgramDayID)
+	assert.Equal(t, 2, created.Progressions[0].ProgramID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProgramRepository_GetById(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProgramRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM programs WHERE id = \\$1").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows(programRowColumns).AddRow(2, 1, "5/3/1", "", "shared", true, 2.5, now, now))
+	mock.ExpectQuery("FROM program_days WHERE program_id = \\$1 ORDER BY week, day").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "program_id", "week", "day", "name", "routine_id"}).
+			AddRow(30, 2, 1, 1, "Squat day", 4).
+			AddRow(31, 2, 1, 2, "Bench day", 5))
+	mock.ExpectQuery("FROM program_prescriptions WHERE program_day_id = ANY\\(\\$1\\)").
+		WithArgs(pq.Array([]int{30, 31})).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "program_day_id", "exercise_id", "set_number", "percent_tm", "reps", "amrap"}).
+			AddRow(40, 30, 3, 1, 65.0, 5, false).
+			AddRow(41, 30, 3, 2, 85.0, 5, true))
+	mock.ExpectQuery("FROM program_progressions WHERE program_id = \\$1").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "program_id", "exercise_id", "increment", "trigger"}).
+			AddRow(50, 2, 3, 5.0, "cycle"))
+
+	program, err := repo.GetById(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Len(t, program.Days, 2)
+	assert.Len(t, program.Days[0].Prescriptions, 2)
+	assert.True(t, program.Days[0].Prescriptions[1].AMRAP)
+	assert.Empty(t, program.Days[1].Prescriptions)
+	assert.Equal(t, 5.0, program.Progressions[0].Increment)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProgramRepository_GetById_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProgramRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM programs WHERE id = \\$1").
+		WithArgs(2).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetById(context.Background(), 2)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProgramRepository_List_VisibleToViewer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProgramRepository(db)
+	now := time.Now()
+	params, err := ProgramListSpec.Parse(pagination.Query{})
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM programs WHERE (visibility <> 'private' OR owner_id = $1) AND strpos(lower(name), lower($2)) > 0 ORDER BY name ASC, id ASC")).
+		WithArgs(7, "531").
+		WillReturnRows(sqlmock.NewRows(programRowColumns).AddRow(2, 1, "5/3/1 BBB", "", "shared", true, 2.5, now, now))
+
+	page, err := repo.List(context.Background(), models.Viewer{UserID: 7}, models.ProgramFilter{Name: "531"}, params)
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.Nil(t, page.Items[0].Days)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProgramRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProgramRepository(db)
+	program := models.Program{
+		ID:         2,
+		Name:       "GZCLP",
+		Visibility: models.VisibilityPrivate,
+		Rounding:   5,
+		Days:       []models.ProgramDay{{Week: 1, Day: 1, RoutineID: 4}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE programs SET").
+		WithArgs("GZCLP", "", models.VisibilityPrivate, false, 5.0, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM program_days WHERE program_id = \\$1").
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec("DELETE FROM program_progressions WHERE program_id = \\$1").
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO program_days").
+		WithArgs(2, 1, 1, "", 4).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(33))
+	mock.ExpectCommit()
+
+	err = repo.Update(context.Background(), program)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProgramRepository_Update_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewProgramRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE programs SET").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = repo.Update(context.Background(), models.Program{ID: 2, Name: "GZCLP"})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
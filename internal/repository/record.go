@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+)
+
+const recordColumns = "id, user_id, exercise_id, workout_id, set_id, category, value, previous, weight, reps, distance_meters, duration_seconds, achieved_at"
+
+type PersonalRecordRepository struct {
+	db *sql.DB
+}
+
+func NewPersonalRecordRepository(db *sql.DB) *PersonalRecordRepository {
+	return &PersonalRecordRepository{db: db}
+}
+
+// PreviousBests computes the user's bests for the set's exercise from the
+// sets logged before it: those of workouts started earlier, and those
+// logged earlier in the same workout. Backfilled sets are therefore judged
+// against what was true on their date. Estimated one-rep maxes use the
+// Epley formula.
+func (r *PersonalRecordRepository) PreviousBests(ctx context.Context, userID int, set models.WorkoutSet) (models.RecordBests, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := fmt.Sprintf(`
+		SELECT
+			COALESCE(MAX(s.weight), 0),
+			COALESCE(MAX(s.reps) FILTER (WHERE s.weight = $4), 0),
+			COALESCE(MAX(ROUND((%s)::numeric, 2)) FILTER (WHERE s.weight > 0 AND s.reps BETWEEN 1 AND %d), 0),
+			COALESCE((
+				SELECT MAX(volume) FROM (
+					SELECT SUM(vs.weight * vs.reps) AS volume
+					FROM workout_sets vs
+					JOIN workouts vw ON vw.id = vs.workout_id
+					WHERE vw.user_id = $1 AND vs.exercise_id = $2 AND vw.started_at < (SELECT started_at FROM workouts WHERE id = $5)
+					GROUP BY vs.workout_id
+				) volumes
+			), 0),
+			COALESCE((SELECT SUM(weight * reps) FROM workout_sets WHERE workout_id = $5 AND exercise_id = $2), 0),
+			COALESCE(MAX(s.distance_meters), 0),
+			COALESCE(MIN(s.duration_seconds) FILTER (WHERE s.distance_meters = $6 AND s.duration_seconds > 0), 0)
+		FROM workout_sets s
+		JOIN workouts w ON w.id = s.workout_id
+		WHERE w.user_id = $1 AND s.exercise_id = $2 AND s.id <> $3
+			AND (w.started_at < (SELECT started_at FROM workouts WHERE id = $5) OR (s.workout_id = $5 AND s.id < $3))`, e1rmFormulas[models.FormulaEpley], models.MaxE1RMReps)
+
+	var bests models.RecordBests
+	err := r.db.QueryRowContext(ctx, query, userID, set.ExerciseID, set.ID, set.Weight, set.WorkoutID, set.DistanceMeters).
+		Scan(&bests.Weight, &bests.RepsAtWeight, &bests.E1RM, &bests.SessionVolume, &bests.CurrentVolume, &bests.Distance, &bests.TimeAtDistance)
+	if err != nil {
+		return models.RecordBests{}, err
+	}
+	return bests, nil
+}
+
+// Create stores new records in one transaction. A session volume record
+// replaces the one already kept for the same workout and exercise.
+func (r *PersonalRecordRepository) Create(ctx context.Context, records []models.PersonalRecord) ([]models.PersonalRecord, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `INSERT INTO personal_records
+			(user_id, exercise_id, workout_id, set_id, category, value, previous, weight, reps, distance_meters, duration_seconds, achieved_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (workout_id, exercise_id) WHERE category = 'session_volume' DO UPDATE SET
+			set_id = EXCLUDED.set_id, value = EXCLUDED.value, weight = EXCLUDED.weight, reps = EXCLUDED.reps, achieved_at = EXCLUDED.achieved_at
+		RETURNING id`
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		for i := range records {
+			pr := &records[i]
+			err := tx.QueryRowContext(ctx, query, pr.UserID, pr.ExerciseID, pr.WorkoutID, pr.SetID, pr.Category, pr.Value, pr.Previous,
+				pr.Weight, pr.Reps, pr.DistanceMeters, pr.DurationSeconds, pr.AchievedAt).Scan(&pr.ID)
+			if err != nil {
+				return mapError(err, "personal record", pr.SetID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// RecordListSpec whitelists the sort orders accepted by ListByUser
+var RecordListSpec = pagination.Spec{
+	Fields: map[string]pagination.Field{
+		"id":          {Column: "id", Type: "integer"},
+		"achieved_at": {Column: "achieved_at", Type: "timestamp"},
+	},
+	DefaultSort: "achieved_at",
+	DefaultDesc: true,
+}
+
+// ListByUser returns one page of a user's personal records matching filter
+func (r *PersonalRecordRepository) ListByUser(ctx context.Context, userID int, filter models.RecordFilter, params pagination.Params) (pagination.Page[models.PersonalRecord], error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	where := []string{"user_id = $1"}
+	args := []any{userID}
+	if filter.ExerciseID != 0 {
+		args = append(args, filter.ExerciseID)
+		where = append(where, fmt.Sprintf("exercise_id = $%d", len(args)))
+	}
+	if filter.Category != "" {
+		args = append(args, filter.Category)
+		where = append(where, fmt.Sprintf("category = $%d", len(args)))
+	}
+
+	query, args := RecordListSpec.Apply("SELECT "+recordColumns+" FROM personal_records", where, args, params)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return pagination.Page[models.PersonalRecord]{}, err
+	}
+	defer rows.Close()
+
+	var records []models.PersonalRecord
+	for rows.Next() {
+		var pr models.PersonalRecord
+		err := rows.Scan(&pr.ID, &pr.UserID, &pr.ExerciseID, &pr.WorkoutID, &pr.SetID, &pr.Category, &pr.Value, &pr.Previous,
+			&pr.Weight, &pr.Reps, &pr.DistanceMeters, &pr.DurationSeconds, &pr.AchievedAt)
+		if err != nil {
+			return pagination.Page[models.PersonalRecord]{}, err
+		}
+		records = append(records, pr)
+	}
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.PersonalRecord]{}, err
+	}
+	return pagination.NewPage(records, params, recordKey), nil
+}
+
+func recordKey(pr models.PersonalRecord, sort string) (string, int) {
+	if sort == "achieved_at" {
+		return timeKey(pr.AchievedAt), pr.ID
+	}
+	return strconv.Itoa(pr.ID), pr.ID
+}
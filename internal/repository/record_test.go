@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersonalRecordRepository_PreviousBests(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPersonalRecordRepository(db)
+	set := models.WorkoutSet{ID: 5, WorkoutID: 2, ExerciseID: 3, Weight: 100, Reps: 5}
+
+	mock.ExpectQuery(regexp.QuoteMeta("MAX(s.reps) FILTER (WHERE s.weight = $4)")+
+		"(.+)"+regexp.QuoteMeta("vw.started_at < (SELECT started_at FROM workouts WHERE id = $5)")+
+		"(.+)"+regexp.QuoteMeta("AND (w.started_at < (SELECT started_at FROM workouts WHERE id = $5) OR (s.workout_id = $5 AND s.id < $3))")).
+		WithArgs(1, 3, 5, 100.0, 2, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"weight", "reps", "e1rm", "session_volume", "current_volume", "distance", "time_at_distance"}).
+			AddRow(110.0, 6, 125.0, 3000.0, 1500.0, 0.0, 0))
+
+	bests, err := repo.PreviousBests(context.Background(), 1, set)
+	assert.NoError(t, err)
+	assert.Equal(t, models.RecordBests{Weight: 110, RepsAtWeight: 6, E1RM: 125, SessionVolume: 3000, CurrentVolume: 1500}, bests)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPersonalRecordRepository_PreviousBests_Cardio(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPersonalRecordRepository(db)
+	distance := 5000.0
+	duration := 1500
+	set := models.WorkoutSet{ID: 9, WorkoutID: 4, ExerciseID: 8, DistanceMeters: &distance, DurationSeconds: &duration}
+
+	mock.ExpectQuery(regexp.QuoteMeta("MIN(s.duration_seconds) FILTER (WHERE s.distance_meters = $6 AND s.duration_seconds > 0)")).
+		WithArgs(1, 8, 9, 0.0, 4, &distance).
+		WillReturnRows(sqlmock.NewRows([]string{"weight", "reps", "e1rm", "session_volume", "current_volume", "distance", "time_at_distance"}).
+			AddRow(0.0, 0, 0.0, 0.0, 0.0, 10000.0, 1560))
+
+	bests, err := repo.PreviousBests(context.Background(), 1, set)
+	assert.NoError(t, err)
+	assert.Equal(t, 10000.0, bests.Distance)
+	assert.Equal(t, 1560, bests.TimeAtDistance)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPersonalRecordRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPersonalRecordRepository(db)
+	at := time.Date(2024, 7, 1, 18, 0, 0, 0, time.UTC)
+	records := []models.PersonalRecord{
+		{UserID: 1, ExerciseID: 3, WorkoutID: 2, SetID: 5, Category: models.RecordHeaviestWeight, Value: 120, Previous: 115, Weight: 120, Reps: 1, AchievedAt: at},
+		{UserID: 1, ExerciseID: 3, WorkoutID: 2, SetID: 5, Category: models.RecordSessionVolume, Value: 3100, Previous: 3000, Weight: 120, Reps: 1, AchievedAt: at},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO personal_records (.+) ON CONFLICT").
+		WithArgs(1, 3, 2, 5, models.RecordHeaviestWeight, 120.0, 115.0, 120.0, 1, nil, nil, at).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery("INSERT INTO personal_records (.+) ON CONFLICT").
+		WithArgs(1, 3, 2, 5, models.RecordSessionVolume, 3100.0, 3000.0, 120.0, 1, nil, nil, at).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+	mock.ExpectCommit()
+
+	created, err := repo.Create(context.Background(), records)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, created[0].ID)
+	assert.Equal(t, 8, created[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPersonalRecordRepository_Create_SetDeleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPersonalRecordRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO personal_records").
+		WillReturnError(&pq.Error{Code: "23503"})
+	mock.ExpectRollback()
+
+	_, err = repo.Create(context.Background(), []models.PersonalRecord{{UserID: 1, ExerciseID: 3, WorkoutID: 2, SetID: 5, Category: models.RecordMostReps}})
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPersonalRecordRepository_ListByUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPersonalRecordRepository(db)
+	at := time.Date(2024, 7, 1, 18, 0, 0, 0, time.UTC)
+	filter := models.RecordFilter{ExerciseID: 3, Category: models.RecordBestE1RM}
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE user_id = $1 AND exercise_id = $2 AND category = $3")+
+		"(.+)"+regexp.QuoteMeta("ORDER BY achieved_at DESC")).
+		WithArgs(1, 3, models.RecordBestE1RM).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "exercise_id", "workout_id", "set_id", "category", "value", "previous", "weight", "reps", "distance_meters", "duration_seconds", "achieved_at"}).
+			AddRow(7, 1, 3, 2, 5, models.RecordBestE1RM, 126.67, 120.0, 100.0, 8, nil, nil, at))
+
+	page, err := repo.ListByUser(context.Background(), 1, filter, pagination.Params{Limit: 10, Sort: "achieved_at", Desc: true})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, 126.67, page.Items[0].Value)
+	assert.Empty(t, page.NextCursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
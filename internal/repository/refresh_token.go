@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"workout-api/internal/models"
+)
+
+type RefreshTokenRepository struct {
+	db *sql.DB
+}
+
+func NewRefreshTokenRepository(db *sql.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token models.RefreshToken) (models.RefreshToken, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "INSERT INTO refresh_tokens (user_id, token_hash, family_id, expires_at) VALUES ($1, $2, $3, $4) RETURNING id, created_at"
+	err := r.db.QueryRowContext(ctx, query, token.UserID, token.TokenHash, token.FamilyID, token.ExpiresAt).Scan(&token.ID, &token.CreatedAt)
+	if err != nil {
+		return models.RefreshToken{}, err
+	}
+	return token, nil
+}
+
+func (r *RefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (models.RefreshToken, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT id, user_id, token_hash, family_id, expires_at, revoked_at, replaced_by, created_at FROM refresh_tokens WHERE token_hash = $1"
+	t := &models.RefreshToken{}
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(&t.ID, &t.UserID, &t.TokenHash, &t.FamilyID, &t.ExpiresAt, &t.RevokedAt, &t.ReplacedBy, &t.CreatedAt)
+	if err != nil {
+		return models.RefreshToken{}, mapError(err, "refresh token", nil)
+	}
+	return *t, nil
+}
+
+// Revoke marks a token as used. It reports false when the token had already
+// been revoked, which lets concurrent refreshes of the same token be caught.
+func (r *RefreshTokenRepository) Revoke(ctx context.Context, id int, replacedBy *int) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP, replaced_by = $1 WHERE id = $2 AND revoked_at IS NULL"
+	result, err := r.db.ExecContext(ctx, query, replacedBy, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE family_id = $1 AND revoked_at IS NULL"
+	_, err := r.db.ExecContext(ctx, query, familyID)
+	return err
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshTokenRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRefreshTokenRepository(db)
+	token := models.RefreshToken{
+		UserID:    1,
+		TokenHash: "abc123",
+		FamilyID:  "family",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	mock.ExpectQuery("INSERT INTO refresh_tokens").
+		WithArgs(token.UserID, token.TokenHash, token.FamilyID, token.ExpiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, time.Now()))
+
+	created, err := repo.Create(context.Background(), token)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, created.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenRepository_GetByHash_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRefreshTokenRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM refresh_tokens WHERE token_hash = \\$1").
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	token, err := repo.GetByHash(context.Background(), "missing")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.Equal(t, models.RefreshToken{}, token)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenRepository_Revoke(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRefreshTokenRepository(db)
+	replacedBy := 4
+
+	mock.ExpectExec("UPDATE refresh_tokens SET revoked_at").
+		WithArgs(&replacedBy, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	revoked, err := repo.Revoke(context.Background(), 3, &replacedBy)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenRepository_Revoke_AlreadyRevoked(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRefreshTokenRepository(db)
+
+	mock.ExpectExec("UPDATE refresh_tokens SET revoked_at").
+		WithArgs(nil, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	revoked, err := repo.Revoke(context.Background(), 3, nil)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenRepository_RevokeFamily(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRefreshTokenRepository(db)
+
+	mock.ExpectExec("UPDATE refresh_tokens SET revoked_at (.+) WHERE family_id = \\$1").
+		WithArgs("family").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = repo.RevokeFamily(context.Background(), "family")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+
+	"github.com/lib/pq"
+)
+
+type RoutineRepository struct {
+	db *sql.DB
+}
+
+func NewRoutineRepository(db *sql.DB) *RoutineRepository {
+	return &RoutineRepository{db: db}
+}
+
+// Create inserts the routine and its exercises in one transaction
+func (r *RoutineRepository) Create(ctx context.Context, routine models.Routine) (models.Routine, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := "INSERT INTO routines (user_id, name, notes) VALUES ($1, $2, $3) RETURNING id, created_at, updated_at"
+		err := tx.QueryRowContext(ctx, query, routine.UserID, routine.Name, routine.Notes).
+			Scan(&routine.ID, &routine.CreatedAt, &routine.UpdatedAt)
+		if err != nil {
+			return mapError(err, "routine", routine.Name)
+		}
+		return insertRoutineExercises(ctx, tx, routine.ID, routine.Exercises)
+	})
+	if err != nil {
+		return models.Routine{}, err
+	}
+	return routine, nil
+}
+
+func (r *RoutineRepository) GetById(ctx context.Context, id int) (models.Routine, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT id, user_id, name, notes, created_at, updated_at FROM routines WHERE id = $1"
+	var routine models.Routine
+	err := r.db.QueryRowContext(ctx, query, id).
+		Scan(&routine.ID, &routine.UserID, &routine.Name, &routine.Notes, &routine.CreatedAt, &routine.UpdatedAt)
+	if err != nil {
+		return models.Routine{}, mapError(err, "routine", id)
+	}
+
+	exercises, err := r.loadExercises(ctx, []int{id})
+	if err != nil {
+		return models.Routine{}, err
+	}
+	routine.Exercises = exercises[id]
+	return routine, nil
+}
+
+// RoutineListSpec whitelists the sort orders accepted by ListByUser
+var RoutineListSpec = pagination.Spec{
+	Fields: map[string]pagination.Field{
+		"id":         {Column: "id", Type: "integer"},
+		"name":       {Column: "name", Type: "text"},
+		"created_at": {Column: "created_at", Type: "timestamp"},
+	},
+	DefaultSort: "name",
+}
+
+// ListByUser returns one page of a user's routines with their exercises
+func (r *RoutineRepository) ListByUser(ctx context.Context, userID int, params pagination.Params) (pagination.Page[models.Routine], error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query, args := RoutineListSpec.Apply("SELECT id, user_id, name, notes, created_at, updated_at FROM routines",
+		[]string{"user_id = $1"}, []any{userID}, params)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return pagination.Page[models.Routine]{}, err
+	}
+	defer rows.Close()
+
+	var routines []models.Routine
+	for rows.Next() {
+		var routine models.Routine
+		err := rows.Scan(&routine.ID, &routine.UserID, &routine.Name, &routine.Notes, &routine.CreatedAt, &routine.UpdatedAt)
+		if err != nil {
+			return pagination.Page[models.Routine]{}, err
+		}
+		routines = append(routines, routine)
+	}
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.Routine]{}, err
+	}
+
+	page := pagination.NewPage(routines, params, routineKey)
+	ids := make([]int, 0, len(page.Items))
+	for _, routine := range page.Items {
+		ids = append(ids, routine.ID)
+	}
+	exercises, err := r.loadExercises(ctx, ids)
+	if err != nil {
+		return pagination.Page[models.Routine]{}, err
+	}
+	for i := range page.Items {
+		page.Items[i].Exercises = exercises[page.Items[i].ID]
+	}
+	return page, nil
+}
+
+func routineKey(routine models.Routine, sort string) (string, int) {
+	switch sort {
+	case "name":
+		return routine.Name, routine.ID
+	case "created_at":
+		return timeKey(routine.CreatedAt), routine.ID
+	default:
+		return strconv.Itoa(routine.ID), routine.ID
+	}
+}
+
+// Update rewrites the routine and replaces its exercises
+func (r *RoutineRepository) Update(ctx context.Context, routine models.Routine) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := "UPDATE routines SET name = $1, notes = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3"
+		result, err := tx.ExecContext(ctx, query, routine.Name, routine.Notes, routine.ID)
+		if err != nil {
+			return mapError(err, "routine", routine.ID)
+		}
+		if err := expectAffected(result, "routine", routine.ID); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM routine_exercises WHERE routine_id = $1", routine.ID); err != nil {
+			return err
+		}
+		return insertRoutineExercises(ctx, tx, routine.ID, routine.Exercises)
+	})
+}
+
+func (r *RoutineRepository) Delete(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM routines WHERE id = $1", id)
+	if err != nil {
+		return mapError(err, "routine", id)
+	}
+	return expectAffected(result, "routine", id)
+}
+
+// insertRoutineExercises writes the exercises in order, numbering their
+// positions from 1
+func insertRoutineExercises(ctx context.Context, tx *sql.Tx, routineID int, exercises []models.RoutineExercise) error {
+	query := `INSERT INTO routine_exercises
+		(routine_id, exercise_id, position, target_sets, rep_min, rep_max, target_weight, target_rpe, rest_seconds, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
+	for i := range exercises {
+		e := &exercises[i]
+		e.RoutineID = routineID
+		e.Position = i + 1
+		err := tx.QueryRowContext(ctx, query, routineID, e.ExerciseID, e.Position, e.TargetSets, e.RepMin, e.RepMax,
+			e.TargetWeight, e.TargetRPE, e.RestSeconds, e.Notes).Scan(&e.ID)
+		if err != nil {
+			return mapError(err, "routine exercise", e.ExerciseID)
+		}
+	}
+	return nil
+}
+
+func (r *RoutineRepository) loadExercises(ctx context.Context, routineIDs []int) (map[int][]models.RoutineExercise, error) {
+	exercises := make(map[int][]models.RoutineExercise, len(routineIDs))
+	if len(routineIDs) == 0 {
+		return exercises, nil
+	}
+
+	query := `SELECT id, routine_id, exercise_id, position, target_sets, rep_min, rep_max, target_weight, target_rpe, rest_seconds, notes
+		FROM routine_exercises WHERE routine_id = ANY($1) ORDER BY routine_id, position`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(routineIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e models.RoutineExercise
+		err := rows.Scan(&e.ID, &e.RoutineID, &e.ExerciseID, &e.Position, &e.TargetSets, &e.RepMin, &e.RepMax,
+			&e.TargetWeight, &e.TargetRPE, &e.RestSeconds, &e.Notes)
+		if err != nil {
+			return nil, err
+		}
+		exercises[e.RoutineID] = append(exercises[e.RoutineID], e)
+	}
+	return exercises, rows.Err()
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var routineExerciseColumns = []string{"id", "routine_id", "exercise_id", "position", "target_sets", "rep_min", "rep_max", "target_weight", "target_rpe", "rest_seconds", "notes"}
+
+func TestRoutineRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRoutineRepository(db)
+	now := time.Now()
+	weight := 60.0
+	routine := models.Routine{
+		UserID: 1,
+		Name:   "Upper A",
+		Exercises: []models.RoutineExercise{
+			{ExerciseID: 3, TargetSets: 3, RepMin: 6, RepMax: 8, TargetWeight: &weight},
+			{ExerciseID: 5, TargetSets: 2, RepMin: 10, RepMax: 12},
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO routines").
+		WithArgs(1, "Upper A", "").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(4, now, now))
+	mock.ExpectQuery("INSERT INTO routine_exercises").
+		WithArgs(4, 3, 1, 3, 6, 8, &weight, nil, nil, "").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+	mock.ExpectQuery("INSERT INTO routine_exercises").
+		WithArgs(4, 5, 2, 2, 10, 12, nil, nil, nil, "").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+	mock.ExpectCommit()
+
+	created, err := repo.Create(context.Background(), routine)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, created.ID)
+	assert.Equal(t, 2, created.Exercises[1].Position)
+	assert.Equal(t, 4, created.Exercises[1].RoutineID)
+	assert.Equal(t, 11, created.Exercises[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRoutineRepository_Create_UnknownExercise(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRoutineRepository(db)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO routines").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(4, now, now))
+	mock.ExpectQuery("INSERT INTO routine_exercises").
+		WillReturnError(&pq.Error{Code: "23503"})
+	mock.ExpectRollback()
+
+	_, err = repo.Create(context.Background(), models.Routine{UserID: 1, Name: "Upper A", Exercises: []models.RoutineExercise{{ExerciseID: 99, TargetSets: 1, RepMin: 5, RepMax: 5}}})
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRoutineRepository_GetById(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRoutineRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM routines WHERE id = \\$1").
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "notes", "created_at", "updated_at"}).
+			AddRow(4, 1, "Upper A", "", now, now))
+	mock.ExpectQuery("SELECT (.+) FROM routine_exercises WHERE routine_id = ANY\\(\\$1\\)").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(routineExerciseColumns).
+			AddRow(10, 4, 3, 1, 3, 6, 8, 60.0, nil, 120, "").
+			AddRow(11, 4, 5, 2, 2, 10, 12, nil, 8.0, nil, "slow eccentric"))
+
+	routine, err := repo.GetById(context.Background(), 4)
+	assert.NoError(t, err)
+	assert.Equal(t, "Upper A", routine.Name)
+	assert.Len(t, routine.Exercises, 2)
+	assert.Equal(t, 60.0, *routine.Exercises[0].TargetWeight)
+	assert.Nil(t, routine.Exercises[1].TargetWeight)
+	assert.Equal(t, 8.0, *routine.Exercises[1].TargetRPE)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRoutineRepository_GetById_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRoutineRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM routines WHERE id = \\$1").
+		WithArgs(4).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetById(context.Background(), 4)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRoutineRepository_ListByUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRoutineRepository(db)
+	now := time.Now()
+	params, err := RoutineListSpec.Parse(pagination.Query{Limit: "2"})
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM routines WHERE user_id = $1 ORDER BY name ASC, id ASC LIMIT 3")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "notes", "created_at", "updated_at"}).
+			AddRow(6, 1, "Lower A", "", now, now).
+			AddRow(4, 1, "Upper A", "", now, now).
+			AddRow(7, 1, "Upper B", "", now, now))
+	// Only the routines on the page have their exercises loaded
+	mock.ExpectQuery("FROM routine_exercises WHERE routine_id = ANY\\(\\$1\\)").
+		WithArgs("{6,4}").
+		WillReturnRows(sqlmock.NewRows(routineExerciseColumns).
+			AddRow(10, 4, 3, 1, 3, 6, 8, nil, nil, nil, "").
+			AddRow(12, 6, 7, 1, 5, 5, 5, nil, nil, nil, ""))
+
+	page, err := repo.ListByUser(context.Background(), 1, params)
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, 7, page.Items[0].Exercises[0].ExerciseID)
+	assert.Equal(t, 3, page.Items[1].Exercises[0].ExerciseID)
+	assert.NotEmpty(t, page.NextCursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRoutineRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRoutineRepository(db)
+	routine := models.Routine{
+		ID:        4,
+		UserID:    1,
+		Name:      "Upper A",
+		Notes:     "deload",
+		Exercises: []models.RoutineExercise{{ExerciseID: 5, TargetSets: 2, RepMin: 10, RepMax: 12}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE routines SET name = \\$1, notes = \\$2").
+		WithArgs("Upper A", "deload", 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM routine_exercises WHERE routine_id = \\$1").
+		WithArgs(4).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectQuery("INSERT INTO routine_exercises").
+		WithArgs(4, 5, 1, 2, 10, 12, nil, nil, nil, "").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(20))
+	mock.ExpectCommit()
+
+	err = repo.Update(context.Background(), routine)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRoutineRepository_Update_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRoutineRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE routines").
+		WithArgs("Upper A", "", 4).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = repo.Update(context.Background(), models.Routine{ID: 4, Name: "Upper A"})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+	"workout-api/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type TaxonomyRepository struct {
+	db *sql.DB
+}
+
+func NewTaxonomyRepository(db *sql.DB) *TaxonomyRepository {
+	return &TaxonomyRepository{db: db}
+}
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+// NormalizeAlias puts free text into the form aliases are stored in:
+// lower-cased with runs of whitespace collapsed to single spaces
+func NormalizeAlias(s string) string {
+	return whitespace.ReplaceAllString(strings.ToLower(strings.TrimSpace(s)), " ")
+}
+
+const muscleGroupSelect = `SELECT mg.id, mg.slug, mg.name, mg.region,
+	coalesce(array_agg(a.alias ORDER BY a.alias) FILTER (WHERE a.alias IS NOT NULL), '{}')
+	FROM muscle_groups mg
+	LEFT JOIN muscle_group_aliases a ON a.muscle_group_id = mg.id`
+
+func (r *TaxonomyRepository) ListMuscleGroups(ctx context.Context) ([]models.MuscleGroup, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := muscleGroupSelect + " GROUP BY mg.id ORDER BY mg.region, mg.name"
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []models.MuscleGroup
+	for rows.Next() {
+		var mg models.MuscleGroup
+		if err := rows.Scan(&mg.ID, &mg.Slug, &mg.Name, &mg.Region, pq.Array(&mg.Aliases)); err != nil {
+			return nil, err
+		}
+		groups = append(groups, mg)
+	}
+	return groups, rows.Err()
+}
+
+// ResolveMuscleGroup finds the muscle group a slug, name or alias refers to
+func (r *TaxonomyRepository) ResolveMuscleGroup(ctx context.Context, name string) (models.MuscleGroup, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := muscleGroupSelect + `
+		WHERE mg.id = (SELECT muscle_group_id FROM muscle_group_aliases WHERE alias = $1)
+		GROUP BY mg.id`
+	var mg models.MuscleGroup
+	err := r.db.QueryRowContext(ctx, query, NormalizeAlias(name)).Scan(&mg.ID, &mg.Slug, &mg.Name, &mg.Region, pq.Array(&mg.Aliases))
+	if err != nil {
+		return models.MuscleGroup{}, mapError(err, "muscle group", name)
+	}
+	return mg, nil
+}
+
+const equipmentSelect = `SELECT e.id, e.slug, e.name,
+	coalesce(array_agg(a.alias ORDER BY a.alias) FILTER (WHERE a.alias IS NOT NULL), '{}')
+	FROM equipment e
+	LEFT JOIN equipment_aliases a ON a.equipment_id = e.id`
+
+func (r *TaxonomyRepository) ListEquipment(ctx context.Context) ([]models.Equipment, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := equipmentSelect + " GROUP BY e.id ORDER BY e.name"
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var equipment []models.Equipment
+	for rows.Next() {
+		var e models.Equipment
+		if err := rows.Scan(&e.ID, &e.Slug, &e.Name, pq.Array(&e.Aliases)); err != nil {
+			return nil, err
+		}
+		equipment = append(equipment, e)
+	}
+	return equipment, rows.Err()
+}
+
+// ResolveEquipment finds the equipment a slug, name or alias refers to
+func (r *TaxonomyRepository) ResolveEquipment(ctx context.Context, name string) (models.Equipment, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := equipmentSelect + `
+		WHERE e.id = (SELECT equipment_id FROM equipment_aliases WHERE alias = $1)
+		GROUP BY e.id`
+	var e models.Equipment
+	err := r.db.QueryRowContext(ctx, query, NormalizeAlias(name)).Scan(&e.ID, &e.Slug, &e.Name, pq.Array(&e.Aliases))
+	if err != nil {
+		return models.Equipment{}, mapError(err, "equipment", name)
+	}
+	return e, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAlias(t *testing.T) {
+	assert.Equal(t, "upper back", NormalizeAlias("  Upper\t  BACK "))
+	assert.Equal(t, "pecs", NormalizeAlias("Pecs"))
+}
+
+func TestTaxonomyRepository_ResolveMuscleGroup(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTaxonomyRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "slug", "name", "region", "aliases"}).
+		AddRow(1, "chest", "Chest", "upper", "{chest,pec,pecs}")
+
+	mock.ExpectQuery("FROM muscle_groups mg(.+)WHERE mg.id = \\(SELECT muscle_group_id FROM muscle_group_aliases WHERE alias = \\$1\\)").
+		WithArgs("pecs").
+		WillReturnRows(rows)
+
+	mg, err := repo.ResolveMuscleGroup(context.Background(), " PECS ")
+	assert.NoError(t, err)
+	assert.Equal(t, models.MuscleGroup{ID: 1, Slug: "chest", Name: "Chest", Region: "upper", Aliases: []string{"chest", "pec", "pecs"}}, mg)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTaxonomyRepository_ResolveEquipment_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTaxonomyRepository(db)
+
+	mock.ExpectQuery("FROM equipment e").
+		WithArgs("anvil").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.ResolveEquipment(context.Background(), "Anvil")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTaxonomyRepository_ListEquipment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTaxonomyRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "slug", "name", "aliases"}).
+		AddRow(1, "barbell", "Barbell", "{bar,barbell}").
+		AddRow(2, "dumbbell", "Dumbbell", "{db,dumbbell}")
+
+	mock.ExpectQuery("FROM equipment e(.+)GROUP BY e.id ORDER BY e.name").
+		WillReturnRows(rows)
+
+	equipment, err := repo.ListEquipment(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, equipment, 2)
+	assert.Equal(t, []string{"db", "dumbbell"}, equipment[1].Aliases)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
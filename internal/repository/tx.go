@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// inTx runs fn in a transaction, committing when it returns nil and
+// rolling back otherwise
+func inTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -1,8 +1,12 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"strconv"
 	"workout-api/internal/models"
+	"workout-api/internal/pagination"
 )
 
 type UserRepository struct {
@@ -13,62 +17,140 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-func (r *UserRepository) Create(user models.User) error {
+func (r *UserRepository) Create(ctx context.Context, user models.User) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	// Insert into users table with auto-generated timestamps
 	query := "INSERT INTO users (name, email, password) VALUES ($1, $2, $3)"
-	_, err := r.db.Exec(query, user.Name, user.Email, user.Password)
-	return err
+	_, err := r.db.ExecContext(ctx, query, user.Name, user.Email, user.Password)
+	return mapError(err, "user", user.Email)
 }
 
-func (r *UserRepository) GetById(id int) (models.User, error) {
+func (r *UserRepository) GetById(ctx context.Context, id int) (models.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	// Select from users table including timestamps
-	query := "SELECT id, name, email, password, created_at, updated_at FROM users WHERE id = $1"
+	query := "SELECT id, name, email, password, role, created_at, updated_at FROM users WHERE id = $1"
 	u := &models.User{}
-	err := r.db.QueryRow(query, id).Scan(&u.ID, &u.Name, &u.Email, &u.Password, &u.CreatedAt, &u.UpdatedAt)
-	if err == sql.ErrNoRows {
-		return models.User{}, nil
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&u.ID, &u.Name, &u.Email, &u.Password, &u.Role, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		return models.User{}, mapError(err, "user", id)
 	}
-	return *u, err
+	return *u, nil
 }
 
-func (r *UserRepository) GetByEmail(email string) (models.User, error) {
-	query := "SELECT id, name, email, password, created_at, updated_at FROM users WHERE email = $1"
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT id, name, email, password, role, created_at, updated_at FROM users WHERE email = $1"
 	u := &models.User{}
-	err := r.db.QueryRow(query, email).Scan(&u.ID, &u.Name, &u.Email, &u.Password, &u.CreatedAt, &u.UpdatedAt)
-	if err == sql.ErrNoRows {
-		return models.User{}, nil
+	err := r.db.QueryRowContext(ctx, query, email).Scan(&u.ID, &u.Name, &u.Email, &u.Password, &u.Role, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		return models.User{}, mapError(err, "user", email)
 	}
-	return *u, err
+	return *u, nil
+}
+
+// UserListSpec whitelists the sort orders accepted by List
+var UserListSpec = pagination.Spec{
+	Fields: map[string]pagination.Field{
+		"id":         {Column: "id", Type: "integer"},
+		"name":       {Column: "name", Type: "text"},
+		"email":      {Column: "email", Type: "text"},
+		"created_at": {Column: "created_at", Type: "timestamp"},
+	},
+	DefaultSort: "id",
 }
 
-func (r *UserRepository) GetAll() ([]models.User, error) {
-	query := "SELECT id, name, email, password, created_at, updated_at FROM users"
-	rows, err := r.db.Query(query)
+// List returns one page of users matching filter
+func (r *UserRepository) List(ctx context.Context, filter models.UserFilter, params pagination.Params) (pagination.Page[models.User], error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var (
+		where []string
+		args  []any
+	)
+	if filter.Name != "" {
+		args = append(args, filter.Name)
+		where = append(where, fmt.Sprintf("strpos(lower(name), lower($%d)) > 0", len(args)))
+	}
+	if filter.Email != "" {
+		args = append(args, filter.Email)
+		where = append(where, fmt.Sprintf("email = $%d", len(args)))
+	}
+
+	query, args := UserListSpec.Apply("SELECT id, name, email, password, role, created_at, updated_at FROM users", where, args, params)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.User]{}, err
 	}
 	defer rows.Close()
 
 	var users []models.User
 	for rows.Next() {
 		var u models.User
-		err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Password, &u.CreatedAt, &u.UpdatedAt)
+		err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Password, &u.Role, &u.CreatedAt, &u.UpdatedAt)
 		if err != nil {
-			return nil, err
+			return pagination.Page[models.User]{}, err
 		}
 		users = append(users, u)
 	}
-	return users, nil
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.User]{}, err
+	}
+	return pagination.NewPage(users, params, userKey), nil
+}
+
+func userKey(u models.User, sort string) (string, int) {
+	switch sort {
+	case "name":
+		return u.Name, u.ID
+	case "email":
+		return u.Email, u.ID
+	case "created_at":
+		return timeKey(u.CreatedAt), u.ID
+	default:
+		return strconv.Itoa(u.ID), u.ID
+	}
 }
 
-func (r *UserRepository) Update(user models.User) error {
+func (r *UserRepository) Update(ctx context.Context, user models.User) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := "UPDATE users SET name = $1, email = $2, password = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $4"
-	_, err := r.db.Exec(query, user.Name, user.Email, user.Password, user.ID)
-	return err
+	result, err := r.db.ExecContext(ctx, query, user.Name, user.Email, user.Password, user.ID)
+	if err != nil {
+		return mapError(err, "user", user.ID)
+	}
+	return expectAffected(result, "user", user.ID)
 }
 
-func (r *UserRepository) Delete(id int) error {
+// SetRole changes a user's role; it takes effect on their next token refresh
+func (r *UserRepository) SetRole(ctx context.Context, id int, role string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE users SET role = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2"
+	result, err := r.db.ExecContext(ctx, query, role, id)
+	if err != nil {
+		return mapError(err, "user", id)
+	}
+	return expectAffected(result, "user", id)
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := "DELETE FROM users WHERE id = $1"
-	_, err := r.db.Exec(query, id)
-	return err
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return mapError(err, "user", id)
+	}
+	return expectAffected(result, "user", id)
 }
@@ -1,12 +1,17 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"regexp"
 	"testing"
 	"time"
+	"workout-api/internal/apperrors"
 	"workout-api/internal/models"
+	"workout-api/internal/pagination"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -26,7 +31,7 @@ func TestUserRepository_Create(t *testing.T) {
 		WithArgs(user.Name, user.Email, user.Password).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = repo.Create(user)
+	err = repo.Create(context.Background(), user)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -43,19 +48,20 @@ func TestUserRepository_GetById(t *testing.T) {
 		Name:      "John Doe",
 		Email:     "john@example.com",
 		Password:  "hashedpassword",
+		Role:      models.RoleUser,
 		CreatedAt: expectedTime,
 		UpdatedAt: expectedTime,
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "created_at", "updated_at"}).
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "created_at", "updated_at"}).
 		AddRow(expectedUser.ID, expectedUser.Name, expectedUser.Email,
-			expectedUser.Password, expectedUser.CreatedAt, expectedUser.UpdatedAt)
+			expectedUser.Password, expectedUser.Role, expectedUser.CreatedAt, expectedUser.UpdatedAt)
 
 	mock.ExpectQuery("SELECT (.+) FROM users WHERE id = \\$1").
 		WithArgs(1).
 		WillReturnRows(rows)
 
-	user, err := repo.GetById(1)
+	user, err := repo.GetById(context.Background(), 1)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser, user)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -73,19 +79,20 @@ func TestUserRepository_GetByEmail(t *testing.T) {
 		Name:      "John Doe",
 		Email:     "john@example.com",
 		Password:  "hashedpassword",
+		Role:      models.RoleUser,
 		CreatedAt: expectedTime,
 		UpdatedAt: expectedTime,
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "created_at", "updated_at"}).
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "created_at", "updated_at"}).
 		AddRow(expectedUser.ID, expectedUser.Name, expectedUser.Email,
-			expectedUser.Password, expectedUser.CreatedAt, expectedUser.UpdatedAt)
+			expectedUser.Password, expectedUser.Role, expectedUser.CreatedAt, expectedUser.UpdatedAt)
 
 	mock.ExpectQuery("SELECT (.+) FROM users WHERE email = \\$1").
 		WithArgs("john@example.com").
 		WillReturnRows(rows)
 
-	user, err := repo.GetByEmail("john@example.com")
+	user, err := repo.GetByEmail(context.Background(), "john@example.com")
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser, user)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -102,13 +109,13 @@ func TestUserRepository_GetByEmail_NotFound(t *testing.T) {
 		WithArgs("nonexistent@example.com").
 		WillReturnError(sql.ErrNoRows)
 
-	user, err := repo.GetByEmail("nonexistent@example.com")
-	assert.NoError(t, err)
+	user, err := repo.GetByEmail(context.Background(), "nonexistent@example.com")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
 	assert.Equal(t, models.User{}, user)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestUserRepository_GetAll(t *testing.T) {
+func TestUserRepository_List(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
@@ -116,18 +123,22 @@ func TestUserRepository_GetAll(t *testing.T) {
 	repo := NewUserRepository(db)
 	expectedTime := time.Now()
 
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "created_at", "updated_at"}).
-		AddRow(1, "John Doe", "john@example.com", "hashedpassword1", expectedTime, expectedTime).
-		AddRow(2, "Jane Smith", "jane@example.com", "hashedpassword2", expectedTime, expectedTime)
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "created_at", "updated_at"}).
+		AddRow(1, "John Doe", "john@example.com", "hashedpassword1", "admin", expectedTime, expectedTime).
+		AddRow(2, "Jane Smith", "jane@example.com", "hashedpassword2", "user", expectedTime, expectedTime)
 
-	mock.ExpectQuery("SELECT (.+) FROM users").
+	mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE strpos(lower(name), lower($1)) > 0 ORDER BY id ASC, id ASC LIMIT 21")).
+		WithArgs("j").
 		WillReturnRows(rows)
 
-	users, err := repo.GetAll()
+	params, err := UserListSpec.Parse(pagination.Query{})
+	assert.NoError(t, err)
+
+	page, err := repo.List(context.Background(), models.UserFilter{Name: "j"}, params)
 	assert.NoError(t, err)
-	assert.Len(t, users, 2)
-	assert.Equal(t, "John Doe", users[0].Name)
-	assert.Equal(t, "Jane Smith", users[1].Name)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, "John Doe", page.Items[0].Name)
+	assert.Equal(t, "Jane Smith", page.Items[1].Name)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -148,7 +159,7 @@ func TestUserRepository_Update(t *testing.T) {
 		WithArgs(user.Name, user.Email, user.Password, user.ID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = repo.Update(user)
+	err = repo.Update(context.Background(), user)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -164,7 +175,89 @@ func TestUserRepository_Delete(t *testing.T) {
 		WithArgs(1).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = repo.Delete(1)
+	err = repo.Delete(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_Create_DuplicateEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	user := models.User{Name: "John Doe", Email: "john@example.com", Password: "hashedpassword"}
+
+	mock.ExpectExec("INSERT INTO users").
+		WithArgs(user.Name, user.Email, user.Password).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	err = repo.Create(context.Background(), user)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_GetById_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM users WHERE id = \\$1").
+		WithArgs(999).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetById(context.Background(), 999)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_Delete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	mock.ExpectExec("DELETE FROM users WHERE id = \\$1").
+		WithArgs(999).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Delete(context.Background(), 999)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_List_ContextCancelled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	mock.ExpectQuery("SELECT (.+) FROM users").
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = repo.List(ctx, models.UserFilter{}, pagination.Params{Limit: 10, Sort: "id"})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestUserRepository_SetRole(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	mock.ExpectExec("UPDATE users SET role = \\$1").
+		WithArgs(models.RoleAdmin, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.SetRole(context.Background(), 1, models.RoleAdmin)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
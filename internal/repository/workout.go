@@ -0,0 +1,378 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+
+	"github.com/lib/pq"
+)
+
+type WorkoutRepository struct {
+	db *sql.DB
+}
+
+func NewWorkoutRepository(db *sql.DB) *WorkoutRepository {
+	return &WorkoutRepository{db: db}
+}
+
+// Create inserts the workout together with any planned sets in one transaction
+func (r *WorkoutRepository) Create(ctx context.Context, workout models.Workout) (models.Workout, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		// Return the generated columns so callers can keep logging against the new session
+		query := "INSERT INTO workouts (user_id, routine_id, name, notes, started_at) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at"
+		err := tx.QueryRowContext(ctx, query, workout.UserID, workout.RoutineID, workout.Name, workout.Notes, workout.StartedAt).
+			Scan(&workout.ID, &workout.CreatedAt, &workout.UpdatedAt)
+		if err != nil {
+			return mapError(err, "workout", workout.UserID)
+		}
+
+		planQuery := `INSERT INTO workout_planned_sets
+			(workout_id, exercise_id, position, set_number, rep_min, rep_max, reps, weight, rpe, rest_seconds, percent_tm, amrap, source)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id`
+		for i := range workout.PlannedSets {
+			p := &workout.PlannedSets[i]
+			p.WorkoutID = workout.ID
+			err := tx.QueryRowContext(ctx, planQuery, p.WorkoutID, p.ExerciseID, p.Position, p.SetNumber, p.RepMin, p.RepMax,
+				p.Reps, p.Weight, p.RPE, p.RestSeconds, p.PercentTM, p.AMRAP, p.Source).Scan(&p.ID)
+			if err != nil {
+				return mapError(err, "planned set", workout.ID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Workout{}, err
+	}
+	return workout, nil
+}
+
+// Import inserts an imported workout with its sets in one transaction. It
+// reports false without writing anything when the user already has a
+// workout with the same import source and key, or one with the same name
+// started at the same time.
+func (r *WorkoutRepository) Import(ctx context.Context, workout models.Workout) (models.Workout, bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	created := false
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		query := `INSERT INTO workouts (user_id, name, notes, started_at, finished_at, import_source, import_key)
+			SELECT $1, $2, $3, $4, $5, $6, $7
+			WHERE NOT EXISTS (SELECT 1 FROM workouts WHERE user_id = $1 AND name = $2 AND started_at = $4)
+			ON CONFLICT (user_id, import_source, import_key) WHERE import_key IS NOT NULL DO NOTHING
+			RETURNING id, created_at, updated_at`
+		err := tx.QueryRowContext(ctx, query, workout.UserID, workout.Name, workout.Notes, workout.StartedAt, workout.FinishedAt,
+			workout.ImportSource, workout.ImportKey).Scan(&workout.ID, &workout.CreatedAt, &workout.UpdatedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return mapError(err, "workout", workout.UserID)
+		}
+		created = true
+
+		setQuery := `INSERT INTO workout_sets (workout_id, exercise_id, set_number, reps, weight, rpe, rest_seconds,
+				duration_seconds, distance_meters, avg_heart_rate, max_heart_rate, elevation_gain_meters, avg_power_watts, calories)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id, created_at`
+		for i := range workout.Sets {
+			s := &workout.Sets[i]
+			s.WorkoutID = workout.ID
+			err := tx.QueryRowContext(ctx, setQuery, s.WorkoutID, s.ExerciseID, s.SetNumber, s.Reps, s.Weight, s.RPE, s.RestSeconds,
+				s.DurationSeconds, s.DistanceMeters, s.AvgHeartRate, s.MaxHeartRate, s.ElevationGain, s.AvgPowerWatts, s.Calories).
+				Scan(&s.ID, &s.CreatedAt)
+			if err != nil {
+				return mapError(err, "workout set", workout.ID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Workout{}, false, err
+	}
+	return workout, created, nil
+}
+
+func (r *WorkoutRepository) GetById(ctx context.Context, id int) (models.Workout, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT id, user_id, routine_id, name, notes, started_at, finished_at, created_at, updated_at FROM workouts WHERE id = $1"
+	w := &models.Workout{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&w.ID, &w.UserID, &w.RoutineID, &w.Name, &w.Notes, &w.StartedAt, &w.FinishedAt, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		return models.Workout{}, mapError(err, "workout", id)
+	}
+	return *w, nil
+}
+
+// WorkoutListSpec whitelists the sort orders accepted by ListByUser
+var WorkoutListSpec = pagination.Spec{
+	Fields: map[string]pagination.Field{
+		"id":         {Column: "id", Type: "integer"},
+		"started_at": {Column: "started_at", Type: "timestamp"},
+		"created_at": {Column: "created_at", Type: "timestamp"},
+	},
+	DefaultSort: "started_at",
+	DefaultDesc: true,
+}
+
+// ListByUser returns one page of a user's workouts matching filter
+func (r *WorkoutRepository) ListByUser(ctx context.Context, userID int, filter models.WorkoutFilter, params pagination.Params) (pagination.Page[models.Workout], error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	where := []string{"user_id = $1"}
+	args := []any{userID}
+	if filter.StartedFrom != nil {
+		args = append(args, *filter.StartedFrom)
+		where = append(where, fmt.Sprintf("started_at >= $%d", len(args)))
+	}
+	if filter.StartedTo != nil {
+		args = append(args, *filter.StartedTo)
+		where = append(where, fmt.Sprintf("started_at < $%d", len(args)))
+	}
+	if filter.Finished != nil {
+		if *filter.Finished {
+			where = append(where, "finished_at IS NOT NULL")
+		} else {
+			where = append(where, "finished_at IS NULL")
+		}
+	}
+
+	query, args := WorkoutListSpec.Apply("SELECT id, user_id, routine_id, name, notes, started_at, finished_at, created_at, updated_at FROM workouts", where, args, params)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return pagination.Page[models.Workout]{}, err
+	}
+	defer rows.Close()
+
+	var workouts []models.Workout
+	for rows.Next() {
+		var w models.Workout
+		err := rows.Scan(&w.ID, &w.UserID, &w.RoutineID, &w.Name, &w.Notes, &w.StartedAt, &w.FinishedAt, &w.CreatedAt, &w.UpdatedAt)
+		if err != nil {
+			return pagination.Page[models.Workout]{}, err
+		}
+		workouts = append(workouts, w)
+	}
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.Workout]{}, err
+	}
+	return pagination.NewPage(workouts, params, workoutKey), nil
+}
+
+func workoutKey(w models.Workout, sort string) (string, int) {
+	switch sort {
+	case "started_at":
+		return timeKey(w.StartedAt), w.ID
+	case "created_at":
+		return timeKey(w.CreatedAt), w.ID
+	default:
+		return strconv.Itoa(w.ID), w.ID
+	}
+}
+
+// ListAll returns every workout of a user with its logged sets, oldest
+// first, for exporting the account
+func (r *WorkoutRepository) ListAll(ctx context.Context, userID int) ([]models.Workout, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT id, user_id, routine_id, name, notes, started_at, finished_at, created_at, updated_at FROM workouts WHERE user_id = $1 ORDER BY started_at, id"
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	workouts := []models.Workout{}
+	index := map[int]int{}
+	for rows.Next() {
+		var w models.Workout
+		err := rows.Scan(&w.ID, &w.UserID, &w.RoutineID, &w.Name, &w.Notes, &w.StartedAt, &w.FinishedAt, &w.CreatedAt, &w.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		index[w.ID] = len(workouts)
+		workouts = append(workouts, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	setQuery := "SELECT " + setColumns + " FROM workout_sets s JOIN workouts w ON w.id = s.workout_id WHERE w.user_id = $1 ORDER BY s.workout_id, s.id"
+	setRows, err := r.db.QueryContext(ctx, setQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer setRows.Close()
+
+	for setRows.Next() {
+		var s models.WorkoutSet
+		if err := scanSet(setRows, &s); err != nil {
+			return nil, err
+		}
+		if i, ok := index[s.WorkoutID]; ok {
+			workouts[i].Sets = append(workouts[i].Sets, s)
+		}
+	}
+	return workouts, setRows.Err()
+}
+
+func (r *WorkoutRepository) Finish(ctx context.Context, id int, finishedAt time.Time) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE workouts SET finished_at = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2"
+	result, err := r.db.ExecContext(ctx, query, finishedAt, id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result, "workout", id)
+}
+
+func (r *WorkoutRepository) Delete(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "DELETE FROM workouts WHERE id = $1"
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result, "workout", id)
+}
+
+func (r *WorkoutRepository) AddSet(ctx context.Context, set models.WorkoutSet) (models.WorkoutSet, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	// Sets are numbered per exercise within a workout, so the next number is derived in the insert
+	query := `INSERT INTO workout_sets (workout_id, exercise_id, set_number, reps, weight, rpe, rest_seconds,
+			duration_seconds, distance_meters, avg_heart_rate, max_heart_rate, elevation_gain_meters, avg_power_watts, calories)
+		VALUES ($1, $2, COALESCE((SELECT MAX(set_number) FROM workout_sets WHERE workout_id = $1 AND exercise_id = $2), 0) + 1,
+			$3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING id, set_number, created_at`
+	err := r.db.QueryRowContext(ctx, query, set.WorkoutID, set.ExerciseID, set.Reps, set.Weight, set.RPE, set.RestSeconds,
+		set.DurationSeconds, set.DistanceMeters, set.AvgHeartRate, set.MaxHeartRate, set.ElevationGain, set.AvgPowerWatts, set.Calories).
+		Scan(&set.ID, &set.SetNumber, &set.CreatedAt)
+	if err != nil {
+		return models.WorkoutSet{}, mapError(err, "workout set", set.WorkoutID)
+	}
+	return set, nil
+}
+
+func (r *WorkoutRepository) GetSets(ctx context.Context, workoutID int) ([]models.WorkoutSet, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + setColumns + " FROM workout_sets s WHERE s.workout_id = $1 ORDER BY s.id"
+	rows, err := r.db.QueryContext(ctx, query, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sets []models.WorkoutSet
+	for rows.Next() {
+		var s models.WorkoutSet
+		if err := scanSet(rows, &s); err != nil {
+			return nil, err
+		}
+		sets = append(sets, s)
+	}
+	return sets, rows.Err()
+}
+
+// GetPlannedSets returns a workout's planned sets in exercise and set order
+func (r *WorkoutRepository) GetPlannedSets(ctx context.Context, workoutID int) ([]models.PlannedSet, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT id, workout_id, exercise_id, position, set_number, rep_min, rep_max, reps, weight, rpe, rest_seconds, percent_tm, amrap, source
+		FROM workout_planned_sets WHERE workout_id = $1 ORDER BY position, set_number`
+	rows, err := r.db.QueryContext(ctx, query, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var planned []models.PlannedSet
+	for rows.Next() {
+		var p models.PlannedSet
+		err := rows.Scan(&p.ID, &p.WorkoutID, &p.ExerciseID, &p.Position, &p.SetNumber, &p.RepMin, &p.RepMax,
+			&p.Reps, &p.Weight, &p.RPE, &p.RestSeconds, &p.PercentTM, &p.AMRAP, &p.Source)
+		if err != nil {
+			return nil, err
+		}
+		planned = append(planned, p)
+	}
+	return planned, rows.Err()
+}
+
+// LastPerformance returns, for each of the given exercises, the sets the
+// user logged in their most recent finished workout that included it
+func (r *WorkoutRepository) LastPerformance(ctx context.Context, userID int, exerciseIDs []int) (map[int][]models.WorkoutSet, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `
+		WITH latest AS (
+			SELECT DISTINCT ON (s.exercise_id) s.exercise_id, s.workout_id
+			FROM workout_sets s
+			JOIN workouts w ON w.id = s.workout_id
+			WHERE w.user_id = $1 AND w.finished_at IS NOT NULL AND s.exercise_id = ANY($2)
+			ORDER BY s.exercise_id, w.started_at DESC, w.id DESC
+		)
+		SELECT ` + setColumns + `
+		FROM workout_sets s
+		JOIN latest l ON l.workout_id = s.workout_id AND l.exercise_id = s.exercise_id
+		ORDER BY s.exercise_id, s.set_number`
+	rows, err := r.db.QueryContext(ctx, query, userID, pq.Array(exerciseIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	last := map[int][]models.WorkoutSet{}
+	for rows.Next() {
+		var s models.WorkoutSet
+		if err := scanSet(rows, &s); err != nil {
+			return nil, err
+		}
+		last[s.ExerciseID] = append(last[s.ExerciseID], s)
+	}
+	return last, rows.Err()
+}
+
+func (r *WorkoutRepository) DeleteSet(ctx context.Context, workoutID, setID int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "DELETE FROM workout_sets WHERE id = $1 AND workout_id = $2"
+	result, err := r.db.ExecContext(ctx, query, setID, workoutID)
+	if err != nil {
+		return err
+	}
+	return expectAffected(result, "workout set", setID)
+}
+
+// setColumns lists the workout_sets columns read by scanSet, for a query
+// aliasing the table as s
+const setColumns = `s.id, s.workout_id, s.exercise_id, s.set_number, s.reps, s.weight, s.rpe, s.rest_seconds,
+	s.duration_seconds, s.distance_meters, s.avg_heart_rate, s.max_heart_rate, s.elevation_gain_meters, s.avg_power_watts, s.calories, s.created_at`
+
+// scanSet reads a row selected with setColumns
+func scanSet(row interface{ Scan(dest ...any) error }, s *models.WorkoutSet) error {
+	return row.Scan(&s.ID, &s.WorkoutID, &s.ExerciseID, &s.SetNumber, &s.Reps, &s.Weight, &s.RPE, &s.RestSeconds,
+		&s.DurationSeconds, &s.DistanceMeters, &s.AvgHeartRate, &s.MaxHeartRate, &s.ElevationGain, &s.AvgPowerWatts, &s.Calories, &s.CreatedAt)
+}
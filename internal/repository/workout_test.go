@@ -0,0 +1,405 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkoutRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWorkoutRepository(db)
+	startedAt := time.Now()
+	workout := models.Workout{
+		UserID:    1,
+		Name:      "Push Day",
+		Notes:     "Felt strong",
+		StartedAt: startedAt,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO workouts").
+		WithArgs(workout.UserID, nil, workout.Name, workout.Notes, workout.StartedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(7, startedAt, startedAt))
+	mock.ExpectCommit()
+
+	created, err := repo.Create(context.Background(), workout)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, created.ID)
+	assert.Equal(t, "Push Day", created.Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkoutRepository_Import(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWorkoutRepository(db)
+	startedAt := time.Date(2026, 3, 2, 7, 30, 0, 0, time.UTC)
+	finishedAt := startedAt.Add(time.Hour)
+	rpe := 8.0
+	workout := models.Workout{
+		UserID:       1,
+		Name:         "Push Day",
+		StartedAt:    startedAt,
+		FinishedAt:   &finishedAt,
+		ImportSource: "strong",
+		ImportKey:    "abc",
+		Sets:         []models.WorkoutSet{{ExerciseID: 3, SetNumber: 1, Reps: 5, Weight: 100, RPE: &rpe}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("ON CONFLICT (user_id, import_source, import_key)")).
+		WithArgs(1, "Push Day", "", startedAt, &finishedAt, "strong", "abc").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(7, startedAt, startedAt))
+	mock.ExpectQuery("INSERT INTO workout_sets").
+		WithArgs(7, 3, 1, 5, 100.0, &rpe, nil, nil, nil, nil, nil, nil, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(40, startedAt))
+	mock.ExpectCommit()
+
+	created, ok, err := repo.Import(context.Background(), workout)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 7, created.ID)
+	assert.Equal(t, 40, created.Sets[0].ID)
+	assert.Equal(t, 7, created.Sets[0].WorkoutID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkoutRepository_Import_AlreadyImported(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWorkoutRepository(db)
+	workout := models.Workout{UserID: 1, ImportSource: "hevy", ImportKey: "abc", Sets: []models.WorkoutSet{{ExerciseID: 3, SetNumber: 1, Reps: 5}}}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO workouts").WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}))
+	mock.ExpectCommit()
+
+	_, ok, err := repo.Import(context.Background(), workout)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkoutRepository_Create_WithPlannedSets(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWorkoutRepository(db)
+	startedAt := time.Now()
+	routineID := 4
+	workout := models.Workout{
+		UserID:    1,
+		RoutineID: &routineID,
+		Name:      "Upper A",
+		StartedAt: startedAt,
+		PlannedSets: []models.PlannedSet{
+			{ExerciseID: 3, Position: 1, SetNumber: 1, RepMin: 5, RepMax: 8, Reps: 6, Weight: 80, Source: models.PlannedFromHistory},
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO workouts").
+		WithArgs(1, &routineID, "Upper A", "", startedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(7, startedAt, startedAt))
+	mock.ExpectQuery("INSERT INTO workout_planned_sets").
+		WithArgs(7, 3, 1, 1, 5, 8, 6, 80.0, nil, nil, nil, false, models.PlannedFromHistory).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(21))
+	mock.ExpectCommit()
+
+	created, err := repo.Create(context.Background(), workout)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, created.PlannedSets[0].WorkoutID)
+	assert.Equal(t, 21, created.PlannedSets[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkoutRepository_Create_PlannedSetFailureRollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWorkoutRepository(db)
+	startedAt := time.Now()
+	workout := models.Workout{
+		UserID:      1,
+		Name:        "Upper A",
+		StartedAt:   startedAt,
+		PlannedSets: []models.PlannedSet{{ExerciseID: 3, Position: 1, SetNumber: 1, RepMin: 5, RepMax: 5, Reps: 5, Source: models.PlannedFromTemplate}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO workouts").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(7, startedAt, startedAt))
+	mock.ExpectQuery("INSERT INTO workout_planned_sets").
+		WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	_, err = repo.Create(context.Background(), workout)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkoutRepository_GetById(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWorkoutRepository(db)
+	expectedTime := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "routine_id", "name", "notes", "started_at", "finished_at", "created_at", "updated_at"}).
+		AddRow(1, 2, nil, "Leg Day", "", expectedTime, nil, expectedTime, expectedTime)
+
+	mock.ExpectQuery("SELECT (.+) FROM workouts WHERE id = \\$1").
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	workout, err := repo.GetById(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, workout.ID)
+	assert.Equal(t, 2, workout.UserID)
+	assert.Nil(t, workout.FinishedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkoutRepository_GetById_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWorkoutRepository(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM workouts WHERE id = \\$1").
+		WithArgs(1).
+		WillReturnError(sql.ErrNoRows)
+
+	workout, err := repo.GetById(context.Background(), 1)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.Equal(t, models.Workout{}, workout)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkoutRepository_ListByUser_Backward(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWorkoutRepository(db)
+	newer := time.Date(2024, 5, 2, 18, 0, 0, 0, time.UTC)
+	older := newer.Add(-24 * time.Hour)
+	finished := true
+
+	cursor := pagination.Cursor{Sort: "started_at", Desc: true, Value: older.Add(-24 * time.Hour).Format(time.RFC3339Nano), ID: 3, Before: true}.Encode()
+	params, err := WorkoutListSpec.Parse(pagination.Query{Limit: "5", Cursor: cursor})
+	assert.NoError(t, err)
+
+	// Walking backwards reverses the default newest-first order in SQL
+	rows := sqlmock.NewRows([]string{"id", "user_id", "routine_id", "name", "notes", "started_at", "finished_at", "created_at", "updated_at"}).
+		AddRow(4, 1, nil, "Pull", "", older, older, older, older).
+		AddRow(5, 1, 9, "Push", "", newer, newer, newer, newer)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM workouts WHERE user_id = $1 AND finished_at IS NOT NULL AND (started_at, id) > ($2::timestamp, $3) ORDER BY started_at ASC, id ASC LIMIT 6")).
+		WithArgs(1, older.Add(-24*time.Hour).Format(time.RFC3339Nano), 3).
+		WillReturnRows(rows)
+
+	page, err := repo.ListByUser(context.Background(), 1, models.WorkoutFilter{Finished: &finished}, params)
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, "Push", page.Items[0].Name)
+	assert.Equal(t, 9, *page.Items[0].RoutineID)
+	assert.Equal(t, "Pull", page.Items[1].Name)
+	// Nothing newer remains, but the page we came from is still ahead
+	assert.Empty(t, page.PrevCursor)
+	assert.NotEmpty(t, page.NextCursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkoutRepository_Finish(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWorkoutRepository(db)
+	finishedAt := time.Now()
+
+	mock.ExpectExec("UPDATE workouts SET finished_at").
+		WithArgs(finishedAt, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.Finish(context.Background(), 1, finishedAt)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+var setColumnNames = []string{"id", "workout_id", "exercise_id", "set_number", "reps", "weight", "rpe", "rest_seconds",
+	"duration_seconds", "distance_meters", "avg_heart_rate", "max_heart_rate", "elevation_gain_meters", "avg_power_watts", "calories", "created_at"}
+
+func TestWorkoutRepository_ListAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWorkoutRepository(db)
+	startedAt := time.Date(2026, 3, 2, 7, 30, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM workouts WHERE user_id = $1 ORDER BY started_at, id")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "routine_id", "name", "notes", "started_at", "finished_at", "created_at", "updated_at"}).
+			AddRow(7, 1, nil, "Push Day", "", startedAt, startedAt.Add(time.Hour), startedAt, startedAt).
+			AddRow(9, 1, nil, "Rest Day", "", startedAt.AddDate(0, 0, 1), nil, startedAt, startedAt))
+	mock.ExpectQuery(regexp.QuoteMeta("JOIN workouts w ON w.id = s.workout_id WHERE w.user_id = $1")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(setColumnNames).
+			AddRow(1, 7, 3, 1, 5, 100.0, nil, nil, nil, nil, nil, nil, nil, nil, nil, startedAt).
+			AddRow(2, 7, 3, 2, 5, 100.0, nil, nil, nil, nil, nil, nil, nil, nil, nil, startedAt))
+
+	workouts, err := repo.ListAll(context.Background(), 1)
+	assert.NoError(t, err)
+	if assert.Len(t, workouts, 2) {
+		assert.Len(t, workouts[0].Sets, 2)
+		assert.Empty(t, workouts[1].Sets)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkoutRepository_AddSet(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWorkoutRepository(db)
+	rpe := 8.5
+	rest := 90
+	set := models.WorkoutSet{
+		WorkoutID:   1,
+		ExerciseID:  3,
+		Reps:        5,
+		Weight:      100,
+		RPE:         &rpe,
+		RestSeconds: &rest,
+	}
+
+	mock.ExpectQuery("INSERT INTO workout_sets").
+		WithArgs(set.WorkoutID, set.ExerciseID, set.Reps, set.Weight, set.RPE, set.RestSeconds, nil, nil, nil, nil, nil, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "set_number", "created_at"}).AddRow(11, 2, time.Now()))
+
+	logged, err := repo.AddSet(context.Background(), set)
+	assert.NoError(t, err)
+	assert.Equal(t, 11, logged.ID)
+	assert.Equal(t, 2, logged.SetNumber)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkoutRepository_GetSets(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWorkoutRepository(db)
+	expectedTime := time.Now()
+
+	rows := sqlmock.NewRows(setColumnNames).
+		AddRow(1, 1, 3, 1, 5, 100.0, 8.0, 120, nil, nil, nil, nil, nil, nil, nil, expectedTime).
+		AddRow(2, 1, 3, 2, 5, 100.0, nil, nil, nil, nil, nil, nil, nil, nil, nil, expectedTime).
+		AddRow(3, 1, 8, 1, 0, 0.0, nil, nil, 1500, 5000.0, 152, 171, 35.5, nil, 380, expectedTime)
+
+	mock.ExpectQuery("SELECT (.+) FROM workout_sets s WHERE s.workout_id = \\$1").
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	sets, err := repo.GetSets(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, sets, 3)
+	assert.Equal(t, 8.0, *sets[0].RPE)
+	assert.Nil(t, sets[1].RPE)
+	assert.Nil(t, sets[1].DistanceMeters)
+	assert.Equal(t, 5000.0, *sets[2].DistanceMeters)
+	assert.Equal(t, 171, *sets[2].MaxHeartRate)
+	assert.Equal(t, 300.0, *sets[2].Pace())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkoutRepository_GetPlannedSets(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWorkoutRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "workout_id", "exercise_id", "position", "set_number", "rep_min", "rep_max", "reps", "weight", "rpe", "rest_seconds", "percent_tm", "amrap", "source"}).
+		AddRow(1, 7, 3, 1, 1, 5, 8, 6, 80.0, 8.0, 120, nil, false, "history").
+		AddRow(2, 7, 3, 1, 2, 5, 5, 5, 85.0, nil, nil, 85.0, true, "program")
+
+	mock.ExpectQuery("SELECT (.+) FROM workout_planned_sets WHERE workout_id = \\$1 ORDER BY position, set_number").
+		WithArgs(7).
+		WillReturnRows(rows)
+
+	planned, err := repo.GetPlannedSets(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Len(t, planned, 2)
+	assert.Equal(t, models.PlannedFromHistory, planned[0].Source)
+	assert.Equal(t, 8.0, *planned[0].RPE)
+	assert.Nil(t, planned[1].RestSeconds)
+	assert.Equal(t, 85.0, *planned[1].PercentTM)
+	assert.True(t, planned[1].AMRAP)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkoutRepository_LastPerformance(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWorkoutRepository(db)
+	expectedTime := time.Now()
+
+	rows := sqlmock.NewRows(setColumnNames).
+		AddRow(10, 4, 3, 1, 8, 60.0, nil, nil, nil, nil, nil, nil, nil, nil, nil, expectedTime).
+		AddRow(11, 4, 3, 2, 7, 60.0, nil, nil, nil, nil, nil, nil, nil, nil, nil, expectedTime).
+		AddRow(15, 5, 6, 1, 5, 120.0, 9.0, 180, nil, nil, nil, nil, nil, nil, nil, expectedTime)
+
+	mock.ExpectQuery("DISTINCT ON \\(s.exercise_id\\)(.+) w.finished_at IS NOT NULL AND s.exercise_id = ANY\\(\\$2\\)").
+		WithArgs(1, sqlmock.AnyArg()).
+		WillReturnRows(rows)
+
+	last, err := repo.LastPerformance(context.Background(), 1, []int{3, 6, 9})
+	assert.NoError(t, err)
+	assert.Len(t, last, 2)
+	assert.Len(t, last[3], 2)
+	assert.Equal(t, 120.0, last[6][0].Weight)
+	assert.Empty(t, last[9])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkoutRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWorkoutRepository(db)
+
+	mock.ExpectExec("DELETE FROM workouts WHERE id = \\$1").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.Delete(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
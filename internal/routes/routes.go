@@ -2,20 +2,106 @@ package routes
 
 import (
 	"github.com/gin-gonic/gin"
+	"workout-api/internal/apperrors"
 	"workout-api/internal/handlers"
+	"workout-api/internal/middleware"
+	"workout-api/internal/models"
 )
 
-func SetupRouter(userHandler *handlers.UserHandler) *gin.Engine {
+func SetupRouter(authMiddleware gin.HandlerFunc, healthHandler *handlers.HealthHandler, authHandler *handlers.AuthHandler, userHandler *handlers.UserHandler, exerciseHandler *handlers.ExerciseHandler, taxonomyHandler *handlers.TaxonomyHandler, workoutHandler *handlers.WorkoutHandler, routineHandler *handlers.RoutineHandler, programHandler *handlers.ProgramHandler, analyticsHandler *handlers.AnalyticsHandler, recordHandler *handlers.RecordHandler, historyHandler *handlers.HistoryHandler, activityHandler *handlers.ActivityHandler, accountHandler *handlers.AccountHandler) *gin.Engine {
 	r := gin.Default()
 
-	// Router check
+	// Render errors attached by handlers as problem+json
+	r.Use(middleware.ErrorHandler())
+	r.NoRoute(func(c *gin.Context) {
+		_ = c.Error(apperrors.NotFound("route", c.Request.URL.Path))
+	})
+
+	// Health probes
 	r.GET("/ping", handlers.Ping)
+	r.GET("/healthz", healthHandler.Healthz)
+	r.GET("/readyz", healthHandler.Readyz)
 
-	// User routes
+	// Public routes
+	r.POST("/auth/login", authHandler.Login)
+	r.POST("/auth/refresh", authHandler.Refresh)
+	r.POST("/auth/logout", authHandler.Logout)
 	r.POST("/users", userHandler.CreateUser)
-	r.GET("/users/:id", userHandler.GetUser)
-	r.GET("/users", userHandler.GetAllUsers)
-	r.DELETE("/users/:id", userHandler.DeleteUser)
+
+	// Everything below requires a valid access token
+	api := r.Group("/", authMiddleware)
+
+	// User routes
+	api.GET("/users/:id", userHandler.GetUser)
+	api.GET("/users", middleware.RequireRole(models.RoleAdmin), userHandler.ListUsers)
+	api.PUT("/users/:id", userHandler.UpdateUser)
+	api.DELETE("/users/:id", userHandler.DeleteUser)
+
+	// Exercise routes
+	api.POST("/exercises", exerciseHandler.CreateExercise)
+	api.GET("/exercises/search", exerciseHandler.SearchExercises)
+	api.GET("/exercises/:id", exerciseHandler.GetExercise)
+	api.GET("/exercises", exerciseHandler.ListExercises)
+	api.PUT("/exercises/:id", exerciseHandler.UpdateExercise)
+	api.DELETE("/exercises/:id", exerciseHandler.DeleteExercise)
+	api.POST("/exercises/:id/promote", middleware.RequireRole(models.RoleAdmin), exerciseHandler.PromoteExercise)
+	api.POST("/admin/catalog/import", middleware.RequireRole(models.RoleAdmin), exerciseHandler.ImportCatalog)
+
+	// Taxonomy routes
+	api.GET("/muscle-groups", taxonomyHandler.ListMuscleGroups)
+	api.GET("/muscle-groups/:slug", taxonomyHandler.GetMuscleGroup)
+	api.GET("/equipment", taxonomyHandler.ListEquipment)
+	api.GET("/equipment/:slug", taxonomyHandler.GetEquipment)
+
+	// Workout routes
+	api.POST("/workouts", workoutHandler.StartWorkout)
+	api.POST("/workouts/import", historyHandler.ImportHistory)
+	api.GET("/workouts", workoutHandler.ListMyWorkouts)
+	api.GET("/workouts/:id", workoutHandler.GetWorkout)
+	api.POST("/workouts/:id/sets", workoutHandler.LogSet)
+	api.DELETE("/workouts/:id/sets/:setId", workoutHandler.DeleteSet)
+	api.POST("/workouts/:id/finish", workoutHandler.FinishWorkout)
+	api.DELETE("/workouts/:id", workoutHandler.DeleteWorkout)
+
+	// Routine routes
+	api.POST("/routines", routineHandler.CreateRoutine)
+	api.GET("/routines", routineHandler.ListMyRoutines)
+	api.GET("/routines/:id", routineHandler.GetRoutine)
+	api.PUT("/routines/:id", routineHandler.UpdateRoutine)
+	api.DELETE("/routines/:id", routineHandler.DeleteRoutine)
+	api.POST("/routines/:id/start", routineHandler.StartRoutine)
+
+	// Program routes
+	api.POST("/programs", programHandler.CreateProgram)
+	api.GET("/programs", programHandler.ListPrograms)
+	api.GET("/programs/:id", programHandler.GetProgram)
+	api.PUT("/programs/:id", programHandler.UpdateProgram)
+	api.DELETE("/programs/:id", programHandler.DeleteProgram)
+	api.POST("/programs/:id/enroll", programHandler.Enroll)
+	api.GET("/enrollment", programHandler.GetEnrollment)
+	api.DELETE("/enrollment", programHandler.LeaveProgram)
+	api.GET("/enrollment/today", programHandler.GetToday)
+	api.POST("/enrollment/today/start", programHandler.StartToday)
+	api.POST("/enrollment/advance", programHandler.Advance)
+
+	// Analytics routes
+	api.GET("/analytics/exercises/:id/e1rm", analyticsHandler.GetE1RMSeries)
+	api.GET("/analytics/exercises/:id/e1rm/sets", analyticsHandler.ListE1RMSets)
+	api.GET("/analytics/muscle-volume", analyticsHandler.GetMuscleVolume)
+
+	// Personal record routes
+	api.GET("/records", recordHandler.ListMyRecords)
+	api.GET("/exercises/:id/records", recordHandler.ListExerciseRecords)
+
+	// Activity routes
+	api.POST("/activities", activityHandler.UploadActivity)
+	api.GET("/activities", activityHandler.ListMyActivities)
+	api.GET("/activities/:id", activityHandler.GetActivity)
+	api.GET("/activities/:id/points", activityHandler.GetActivityPoints)
+
+	// Account routes
+	api.GET("/account/export", accountHandler.ExportAccount)
+	api.POST("/account/import", accountHandler.ImportAccount)
 
 	return r
 }
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/archive"
+	"workout-api/internal/models"
+	"workout-api/internal/repository"
+)
+
+// archiveImportSource marks workouts restored from an account archive
+const archiveImportSource = "archive"
+
+type AccountService struct {
+	userRepo     repository.UserRepositoryInterface
+	exerciseRepo repository.ExerciseRepositoryInterface
+	workoutRepo  repository.WorkoutRepositoryInterface
+	exercises    *ExerciseService
+}
+
+func NewAccountService(userRepo repository.UserRepositoryInterface, exerciseRepo repository.ExerciseRepositoryInterface, workoutRepo repository.WorkoutRepositoryInterface, exercises *ExerciseService) *AccountService {
+	return &AccountService{userRepo: userRepo, exerciseRepo: exerciseRepo, workoutRepo: workoutRepo, exercises: exercises}
+}
+
+// ExportAccount collects everything the user owns into an archive
+func (s *AccountService) ExportAccount(ctx context.Context, userID int) (archive.Archive, error) {
+	user, err := s.userRepo.GetById(ctx, userID)
+	if err != nil {
+		return archive.Archive{}, err
+	}
+	exercises, err := s.exerciseRepo.ListUsedBy(ctx, userID)
+	if err != nil {
+		return archive.Archive{}, err
+	}
+	workouts, err := s.workoutRepo.ListAll(ctx, userID)
+	if err != nil {
+		return archive.Archive{}, err
+	}
+
+	a := archive.Archive{
+		FormatVersion: archive.FormatVersion,
+		ExportedAt:    time.Now().UTC(),
+		Profile:       archive.Profile{Name: user.Name, Email: user.Email, CreatedAt: user.CreatedAt},
+		Exercises:     make([]archive.Exercise, 0, len(exercises)),
+		Workouts:      make([]archive.Workout, 0, len(workouts)),
+	}
+	for _, e := range exercises {
+		a.Exercises = append(a.Exercises, archive.NewExercise(e, userID))
+	}
+	for _, w := range workouts {
+		a.Workouts = append(a.Workouts, archive.NewWorkout(w))
+	}
+	return a, nil
+}
+
+// ImportAccount restores an archive into viewer's account, which may be on
+// another instance. Archived exercises are remapped onto global exercises
+// and viewer's own by exact name; the rest become private exercises.
+// Workouts viewer already has, by name and start time or from an earlier
+// restore, are skipped. Sets that do not fit their exercise are left out
+// and reported. Restored sets do not raise personal records.
+func (s *AccountService) ImportAccount(ctx context.Context, viewer models.Viewer, a archive.Archive) (models.AccountImportReport, error) {
+	report := models.AccountImportReport{FormatVersion: a.FormatVersion, Exercises: []models.ImportedExercise{}, Skipped: []models.ArchiveSetIssue{}}
+
+	// Exercises are resolved once per name, since an archive may hold a
+	// catalog exercise and a custom one with the same name
+	byID := make(map[int]archive.Exercise, len(a.Exercises))
+	names := map[string]string{}
+	for _, e := range a.Exercises {
+		byID[e.ID] = e
+		names[e.Name] = strings.ToLower(e.Name)
+	}
+	matches := map[string]models.ExerciseMatch{}
+	if len(names) > 0 {
+		var err error
+		if matches, err = s.exerciseRepo.MatchNames(ctx, viewer.UserID, names, 1); err != nil {
+			return models.AccountImportReport{}, err
+		}
+	}
+
+	// mapped indexes report.Exercises by lowercased name; failed holds why a
+	// name could not be mapped
+	mapped := map[string]int{}
+	trackingTypes := map[string]string{}
+	failed := map[string]string{}
+	for _, e := range a.Exercises {
+		key := strings.ToLower(e.Name)
+		if _, seen := mapped[key]; seen || failed[key] != "" {
+			continue
+		}
+		imported := models.ImportedExercise{Name: e.Name}
+		if m, ok := matches[e.Name]; ok {
+			imported.ExerciseID, imported.ExerciseName, imported.Score = m.ExerciseID, m.ExerciseName, m.Score
+			trackingTypes[key] = m.TrackingType
+		} else {
+			exercise, reason, err := s.createExercise(ctx, viewer, e)
+			if err != nil {
+				return models.AccountImportReport{}, err
+			}
+			if reason != "" {
+				failed[key] = reason
+				continue
+			}
+			imported.ExerciseID, imported.ExerciseName, imported.Created = exercise.ID, exercise.Name, true
+			trackingTypes[key] = exercise.TrackingType
+		}
+		mapped[key] = len(report.Exercises)
+		report.Exercises = append(report.Exercises, imported)
+	}
+
+	for i, w := range a.Workouts {
+		workout := models.Workout{
+			UserID:       viewer.UserID,
+			Name:         w.Name,
+			Notes:        w.Notes,
+			StartedAt:    w.StartedAt,
+			FinishedAt:   w.FinishedAt,
+			ImportSource: archiveImportSource,
+			ImportKey:    archiveKey(w),
+		}
+
+		numbers := map[int]int{}
+		var setExercises []int
+		for j, set := range w.Sets {
+			name := byID[set.ExerciseID].Name
+			key := strings.ToLower(name)
+			skip := func(reason string) {
+				report.Skipped = append(report.Skipped, models.ArchiveSetIssue{Workout: i + 1, Set: j + 1, Exercise: name, Reason: reason})
+			}
+			index, ok := mapped[key]
+			if !ok {
+				skip(failed[key])
+				continue
+			}
+			logged := set.ToModel(report.Exercises[index].ExerciseID)
+			if reason := importedSetProblems(trackingTypes[key], logged); reason != "" {
+				skip(reason)
+				continue
+			}
+			numbers[logged.ExerciseID]++
+			logged.SetNumber = numbers[logged.ExerciseID]
+			workout.Sets = append(workout.Sets, logged)
+			setExercises = append(setExercises, index)
+		}
+		// Workouts that were logged empty are restored as such, but not
+		// ones whose every set has been reported
+		if len(w.Sets) > 0 && len(workout.Sets) == 0 {
+			continue
+		}
+
+		_, created, err := s.workoutRepo.Import(ctx, workout)
+		if err != nil {
+			return models.AccountImportReport{}, err
+		}
+		if !created {
+			report.WorkoutsSkipped++
+			continue
+		}
+		report.WorkoutsCreated++
+		report.SetsImported += len(workout.Sets)
+		for _, index := range setExercises {
+			report.Exercises[index].Sets++
+		}
+	}
+	return report, nil
+}
+
+// createExercise adds a private copy of an archived exercise. The source
+// instance may have used muscle groups or equipment this one lacks, so an
+// exercise they make invalid falls back to the import defaults. One that
+// still cannot be created is returned as a reason rather than an error.
+func (s *AccountService) createExercise(ctx context.Context, viewer models.Viewer, e archive.Exercise) (models.Exercise, string, error) {
+	exercise, err := s.exercises.CreateExercise(ctx, viewer, e.ToModel())
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) && errors.Is(err, apperrors.ErrValidation) {
+		fallback := e.ToModel()
+		fallback.MuscleGroup, fallback.EquipmentType, fallback.Muscles = importMuscleGroup, importEquipment, nil
+		exercise, err = s.exercises.CreateExercise(ctx, viewer, fallback)
+	}
+	if errors.As(err, &appErr) && errors.Is(err, apperrors.ErrValidation) {
+		return models.Exercise{}, "could not create exercise: " + describeFieldErrors(appErr.Fields), nil
+	}
+	if err != nil {
+		return models.Exercise{}, "", err
+	}
+	return exercise, "", nil
+}
+
+// archiveKey identifies an archived workout across restores
+func archiveKey(w archive.Workout) string {
+	sum := sha256.Sum256([]byte(w.StartedAt.UTC().Format(time.RFC3339Nano) + "\n" + w.Name))
+	return hex.EncodeToString(sum[:])
+}
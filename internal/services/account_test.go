@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+	"workout-api/internal/archive"
+	"workout-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestAccountService() (*AccountService, *MockUserRepository, *MockExerciseRepository, *MockWorkoutRepository) {
+	userRepo := new(MockUserRepository)
+	exerciseRepo := new(MockExerciseRepository)
+	workoutRepo := new(MockWorkoutRepository)
+	service := NewAccountService(userRepo, exerciseRepo, workoutRepo, NewExerciseService(exerciseRepo, newTestTaxonomy()))
+	return service, userRepo, exerciseRepo, workoutRepo
+}
+
+func TestAccountService_ExportAccount(t *testing.T) {
+	ctx := context.Background()
+	service, userRepo, exerciseRepo, workoutRepo := newTestAccountService()
+	owner := 5
+	started := time.Date(2026, 3, 2, 7, 30, 0, 0, time.UTC)
+
+	userRepo.On("GetById", ctx, 5).Return(models.User{ID: 5, Name: "Alex", Email: "alex@example.com", Password: "hash"}, nil)
+	exerciseRepo.On("ListUsedBy", ctx, 5).Return([]models.Exercise{
+		{ID: 3, Name: "Bench Press", Visibility: models.VisibilityGlobal},
+		{ID: 40, Name: "Sled Push", OwnerID: &owner, Visibility: models.VisibilityPrivate},
+	}, nil)
+	workoutRepo.On("ListAll", ctx, 5).Return([]models.Workout{
+		{ID: 7, Name: "Push Day", StartedAt: started, Sets: []models.WorkoutSet{{ID: 1, ExerciseID: 3, SetNumber: 1, Reps: 5, Weight: 100}}},
+	}, nil)
+
+	a, err := service.ExportAccount(ctx, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, archive.FormatVersion, a.FormatVersion)
+	assert.Equal(t, archive.Profile{Name: "Alex", Email: "alex@example.com"}, a.Profile)
+	assert.Equal(t, []bool{false, true}, []bool{a.Exercises[0].Custom, a.Exercises[1].Custom})
+	if assert.Len(t, a.Workouts, 1) {
+		assert.Equal(t, []archive.Set{{ExerciseID: 3, SetNumber: 1, Reps: 5, Weight: 100}}, a.Workouts[0].Sets)
+	}
+}
+
+func TestAccountService_ImportAccount(t *testing.T) {
+	ctx := context.Background()
+	service, _, exerciseRepo, workoutRepo := newTestAccountService()
+	viewer := models.Viewer{UserID: 8, Role: models.RoleUser}
+	started := time.Date(2026, 3, 2, 7, 30, 0, 0, time.UTC)
+	seconds := 60
+
+	a := archive.Archive{
+		FormatVersion: archive.FormatVersion,
+		Exercises: []archive.Exercise{
+			{ID: 3, Name: "Bench Press", MuscleGroup: "chest", EquipmentType: "barbell", TrackingType: models.TrackingWeightReps},
+			{ID: 40, Custom: true, Name: "Sled Push", MuscleGroup: "glutes", EquipmentType: "sled", TrackingType: models.TrackingDuration},
+			{ID: 41, Custom: true, Name: "bench press", MuscleGroup: "chest", TrackingType: models.TrackingWeightReps},
+		},
+		Workouts: []archive.Workout{
+			{Name: "Push Day", StartedAt: started, Sets: []archive.Set{
+				{ExerciseID: 3, SetNumber: 1, Reps: 5, Weight: 100},
+				{ExerciseID: 41, SetNumber: 1, Reps: 0, Weight: 100},
+				{ExerciseID: 3, SetNumber: 2, Reps: 5, Weight: 100000},
+				{ExerciseID: 41, SetNumber: 2, Reps: 6, Weight: 90},
+				{ExerciseID: 40, SetNumber: 1, DurationSeconds: &seconds},
+			}},
+			{Name: "Push Day", StartedAt: started.AddDate(0, 0, 2), Sets: []archive.Set{{ExerciseID: 3, SetNumber: 1, Reps: 5, Weight: 102.5}}},
+		},
+	}
+
+	exerciseRepo.On("MatchNames", ctx, 8, map[string]string{"Bench Press": "bench press", "Sled Push": "sled push", "bench press": "bench press"}, 1.0).
+		Return(map[string]models.ExerciseMatch{
+			"Bench Press": {ExerciseID: 1, ExerciseName: "Bench Press", TrackingType: models.TrackingWeightReps, Score: 1},
+			"bench press": {ExerciseID: 1, ExerciseName: "Bench Press", TrackingType: models.TrackingWeightReps, Score: 1},
+		}, nil)
+	// The muscle group and equipment are unknown here, so the copy falls back to the import defaults
+	exerciseRepo.On("Create", ctx, mock.MatchedBy(func(e models.Exercise) bool {
+		return e.Name == "Sled Push" && e.MuscleGroup == importMuscleGroup && e.EquipmentType == importEquipment &&
+			e.TrackingType == models.TrackingDuration && e.Visibility == models.VisibilityPrivate && *e.OwnerID == 8
+	})).Return(models.Exercise{ID: 90, Name: "Sled Push", TrackingType: models.TrackingDuration}, nil)
+
+	workoutRepo.On("Import", ctx, mock.MatchedBy(func(w models.Workout) bool { return w.StartedAt.Equal(started) })).
+		Run(func(args mock.Arguments) {
+			w := args.Get(1).(models.Workout)
+			assert.Equal(t, archiveImportSource, w.ImportSource)
+			assert.Len(t, w.ImportKey, 64)
+			assert.Nil(t, w.FinishedAt)
+			if assert.Len(t, w.Sets, 3) {
+				assert.Equal(t, []int{1, 2, 1}, []int{w.Sets[0].SetNumber, w.Sets[1].SetNumber, w.Sets[2].SetNumber})
+				assert.Equal(t, 90, w.Sets[2].ExerciseID)
+			}
+		}).Return(models.Workout{ID: 7}, true, nil)
+	workoutRepo.On("Import", ctx, mock.Anything).Return(models.Workout{}, false, nil)
+
+	report, err := service.ImportAccount(ctx, viewer, a)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.WorkoutsCreated)
+	assert.Equal(t, 1, report.WorkoutsSkipped)
+	assert.Equal(t, 3, report.SetsImported)
+	assert.Equal(t, []models.ImportedExercise{
+		{Name: "Bench Press", ExerciseID: 1, ExerciseName: "Bench Press", Score: 1, Sets: 2},
+		{Name: "Sled Push", ExerciseID: 90, ExerciseName: "Sled Push", Created: true, Sets: 1},
+	}, report.Exercises)
+	assert.Equal(t, []models.ArchiveSetIssue{
+		{Workout: 1, Set: 2, Exercise: "bench press", Reason: "reps must be greater than zero"},
+		{Workout: 1, Set: 3, Exercise: "Bench Press", Reason: "weight must be less than 100000"},
+	}, report.Skipped)
+	exerciseRepo.AssertExpectations(t)
+	workoutRepo.AssertExpectations(t)
+}
+
+func TestArchiveKey_StableAcrossZones(t *testing.T) {
+	started := time.Date(2026, 3, 2, 7, 30, 0, 0, time.UTC)
+	local := started.In(time.FixedZone("CET", 3600))
+	assert.Equal(t, archiveKey(archive.Workout{Name: "Push Day", StartedAt: started}), archiveKey(archive.Workout{Name: "Push Day", StartedAt: local}))
+	assert.NotEqual(t, archiveKey(archive.Workout{Name: "Push Day", StartedAt: started}), archiveKey(archive.Workout{Name: "Pull Day", StartedAt: started}))
+}
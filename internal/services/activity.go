@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"workout-api/internal/activity"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+	"workout-api/internal/repository"
+)
+
+type ActivityService struct {
+	repo         repository.ActivityRepositoryInterface
+	exerciseRepo repository.ExerciseRepositoryInterface
+	recordRepo   repository.PersonalRecordRepositoryInterface
+}
+
+func NewActivityService(repo repository.ActivityRepositoryInterface, exerciseRepo repository.ExerciseRepositoryInterface, recordRepo repository.PersonalRecordRepositoryInterface) *ActivityService {
+	return &ActivityService{repo: repo, exerciseRepo: exerciseRepo, recordRepo: recordRepo}
+}
+
+// UploadActivity parses a GPX, TCX or FIT file and logs it as a finished
+// workout with one set of the given cardio exercise holding the activity's
+// summary. An empty format is detected from the content. The same file can
+// only be uploaded once. Long tracks are thinned to activity.MaxStoredPoints
+// once their summary is computed. Any personal records the set breaks are
+// saved and returned with the activity.
+func (s *ActivityService) UploadActivity(ctx context.Context, viewer models.Viewer, exerciseID int, format string, data []byte) (models.Activity, error) {
+	if exerciseID <= 0 {
+		return models.Activity{}, apperrors.Invalid("exercise_id", "exercise_id is required")
+	}
+	if format == "" {
+		detected, err := activity.Detect(data)
+		if err != nil {
+			return models.Activity{}, err
+		}
+		format = detected
+	}
+	track, err := activity.Parse(strings.ToLower(format), data)
+	if err != nil {
+		return models.Activity{}, err
+	}
+
+	// Other users' private exercises count as missing
+	exercise, err := s.exerciseRepo.GetById(ctx, exerciseID)
+	if errors.Is(err, apperrors.ErrNotFound) || (err == nil && !exercise.VisibleTo(viewer)) {
+		return models.Activity{}, apperrors.Invalid("exercise_id", "exercise not found")
+	}
+	if err != nil {
+		return models.Activity{}, err
+	}
+	if exercise.TrackingType != models.TrackingDistanceDuration && exercise.TrackingType != models.TrackingDuration {
+		return models.Activity{}, apperrors.Invalid("exercise_id", "activities can only be logged against distance or duration exercises")
+	}
+
+	a := activity.Summarize(track)
+	a.UserID, a.ExerciseID = viewer.UserID, exerciseID
+	hash := sha256.Sum256(data)
+	a.FileHash = hex.EncodeToString(hash[:])
+	a.Points = activity.Downsample(track.Points, activity.MaxStoredPoints)
+	a.PointCount = len(a.Points)
+
+	// Duration exercises leave the distance off the set, but the activity
+	// still stores it
+	if a.DistanceMeters >= maxSetDistance {
+		return models.Activity{}, apperrors.Invalid("distance_meters", fmt.Sprintf("distance must be less than %.0f meters", maxSetDistance))
+	}
+	set := activitySet(a, exercise.TrackingType)
+	if err := validateSet(set); err != nil {
+		return models.Activity{}, err
+	}
+	if fields := validateTrackedSet(exercise.TrackingType, set); len(fields) > 0 {
+		return models.Activity{}, apperrors.Validation(fields...)
+	}
+
+	name := a.Name
+	if name == "" {
+		name = strings.ToUpper(a.Sport[:1]) + a.Sport[1:]
+	}
+	finishedAt := a.StartedAt.Add(time.Duration(a.DurationSeconds) * time.Second)
+	workout := models.Workout{UserID: viewer.UserID, Name: name, StartedAt: a.StartedAt, FinishedAt: &finishedAt, Sets: []models.WorkoutSet{set}}
+
+	created, err := s.repo.Create(ctx, workout, a)
+	if err != nil {
+		return models.Activity{}, err
+	}
+
+	// The activity is already stored, so a failed check must not fail the
+	// upload. Its records date from when it was recorded, not uploaded.
+	set.ID, set.WorkoutID = created.SetID, created.WorkoutID
+	records, err := saveRecords(ctx, s.recordRepo, viewer.UserID, set, a.StartedAt)
+	if err != nil {
+		log.Printf("failed to detect personal records for activity %d: %v", created.ID, err)
+	}
+	created.Records = records
+
+	return created, nil
+}
+
+// activitySet builds the logged set summarising an activity. Duration
+// exercises do not track distance, so it is left off for them.
+func activitySet(a models.Activity, trackingType string) models.WorkoutSet {
+	set := models.WorkoutSet{
+		ExerciseID:    a.ExerciseID,
+		AvgHeartRate:  a.AvgHeartRate,
+		MaxHeartRate:  a.MaxHeartRate,
+		ElevationGain: a.ElevationGain,
+		AvgPowerWatts: a.AvgPowerWatts,
+		Calories:      a.Calories,
+	}
+	if a.DurationSeconds > 0 {
+		duration := a.DurationSeconds
+		set.DurationSeconds = &duration
+	}
+	if a.DistanceMeters > 0 && trackingType == models.TrackingDistanceDuration {
+		distance := a.DistanceMeters
+		set.DistanceMeters = &distance
+	}
+	return set
+}
+
+// GetActivity returns one of the user's activities without its track points
+func (s *ActivityService) GetActivity(ctx context.Context, userID, id int) (models.Activity, error) {
+	if id <= 0 {
+		return models.Activity{}, apperrors.Invalid("id", "invalid activity ID")
+	}
+
+	a, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return models.Activity{}, err
+	}
+	// Other users' activities are indistinguishable from missing ones
+	if a.UserID != userID {
+		return models.Activity{}, apperrors.NotFound("activity", id)
+	}
+	return a, nil
+}
+
+// ListActivities returns one page of a user's activities, newest first by
+// default, optionally of one sport
+func (s *ActivityService) ListActivities(ctx context.Context, userID int, sport string, query pagination.Query) (pagination.Page[models.Activity], error) {
+	params, err := repository.ActivityListSpec.Parse(query)
+	if err != nil {
+		return pagination.Page[models.Activity]{}, err
+	}
+	return s.repo.ListByUser(ctx, userID, sport, params)
+}
+
+// ActivityPoints returns the track points of one of the user's activities
+func (s *ActivityService) ActivityPoints(ctx context.Context, userID, id int) ([]models.ActivityPoint, error) {
+	if _, err := s.GetActivity(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.Points(ctx, id)
+}
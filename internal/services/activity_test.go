@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+	"workout-api/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock ActivityRepository that implements repository.ActivityRepositoryInterface
+type MockActivityRepository struct {
+	mock.Mock
+}
+
+func (m *MockActivityRepository) Create(ctx context.Context, workout models.Workout, activity models.Activity) (models.Activity, error) {
+	args := m.Called(ctx, workout, activity)
+	return args.Get(0).(models.Activity), args.Error(1)
+}
+
+func (m *MockActivityRepository) GetById(ctx context.Context, id int) (models.Activity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.Activity), args.Error(1)
+}
+
+func (m *MockActivityRepository) ListByUser(ctx context.Context, userID int, sport string, params pagination.Params) (pagination.Page[models.Activity], error) {
+	args := m.Called(ctx, userID, sport, params)
+	return args.Get(0).(pagination.Page[models.Activity]), args.Error(1)
+}
+
+func (m *MockActivityRepository) Points(ctx context.Context, activityID int) ([]models.ActivityPoint, error) {
+	args := m.Called(ctx, activityID)
+	return args.Get(0).([]models.ActivityPoint), args.Error(1)
+}
+
+var _ repository.ActivityRepositoryInterface = (*MockActivityRepository)(nil)
+
+func newTestActivityService() (*ActivityService, *MockActivityRepository, *MockExerciseRepository, *MockPersonalRecordRepository) {
+	repo := new(MockActivityRepository)
+	exerciseRepo := new(MockExerciseRepository)
+	recordRepo := new(MockPersonalRecordRepository)
+	return NewActivityService(repo, exerciseRepo, recordRepo), repo, exerciseRepo, recordRepo
+}
+
+const testGPX = `<?xml version="1.0"?>
+<gpx version="1.1" xmlns="http://www.topografix.com/GPX/1/1">
+	<trk><type>running</type><trkseg>
+		<trkpt lat="51.5000" lon="-0.1200"><ele>10</ele><time>2026-03-02T07:00:00Z</time></trkpt>
+		<trkpt lat="51.5045" lon="-0.1200"><ele>15</ele><time>2026-03-02T07:02:30Z</time></trkpt>
+		<trkpt lat="51.5090" lon="-0.1200"><ele>14</ele><time>2026-03-02T07:05:00Z</time></trkpt>
+	</trkseg></trk>
+</gpx>`
+
+func TestActivityService_UploadActivity(t *testing.T) {
+	ctx := context.Background()
+	service, repo, exerciseRepo, recordRepo := newTestActivityService()
+	viewer := models.Viewer{UserID: 5, Role: models.RoleUser}
+	start := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+
+	exerciseRepo.On("GetById", ctx, 4).Return(models.Exercise{ID: 4, Visibility: models.VisibilityGlobal, TrackingType: models.TrackingDistanceDuration}, nil)
+	repo.On("Create", ctx, mock.MatchedBy(func(w models.Workout) bool {
+		set := w.Sets[0]
+		return w.UserID == 5 && w.Name == "Running" && w.StartedAt.Equal(start) && w.FinishedAt.Equal(start.Add(5*time.Minute)) &&
+			len(w.Sets) == 1 && set.ExerciseID == 4 && set.Reps == 0 && *set.DurationSeconds == 300 && *set.DistanceMeters > 1000
+	}), mock.MatchedBy(func(a models.Activity) bool {
+		return a.UserID == 5 && a.ExerciseID == 4 && a.Format == models.ActivityGPX && len(a.FileHash) == 64 && len(a.Points) == 3
+	})).Return(models.Activity{ID: 30, WorkoutID: 10, SetID: 20}, nil)
+	// The summary set is checked against earlier bests once it is stored
+	recordRepo.On("PreviousBests", ctx, 5, mock.MatchedBy(func(set models.WorkoutSet) bool {
+		return set.ID == 20 && set.WorkoutID == 10 && set.ExerciseID == 4
+	})).Return(models.RecordBests{Distance: 800}, nil)
+	recordRepo.On("Create", ctx, mock.MatchedBy(func(records []models.PersonalRecord) bool {
+		return len(records) == 1 && records[0].Category == models.RecordLongestDistance && records[0].SetID == 20 && records[0].Previous == 800 &&
+			records[0].AchievedAt.Equal(start)
+	})).Return([]models.PersonalRecord{{ID: 3, Category: models.RecordLongestDistance}}, nil)
+
+	created, err := service.UploadActivity(ctx, viewer, 4, "", []byte(testGPX))
+	assert.NoError(t, err)
+	assert.Equal(t, 30, created.ID)
+	assert.Len(t, created.Records, 1)
+	repo.AssertExpectations(t)
+	recordRepo.AssertExpectations(t)
+}
+
+func TestActivityService_UploadActivity_DurationExercise(t *testing.T) {
+	ctx := context.Background()
+	service, repo, exerciseRepo, recordRepo := newTestActivityService()
+	viewer := models.Viewer{UserID: 5, Role: models.RoleUser}
+
+	exerciseRepo.On("GetById", ctx, 6).Return(models.Exercise{ID: 6, Visibility: models.VisibilityGlobal, TrackingType: models.TrackingDuration}, nil)
+	repo.On("Create", ctx, mock.MatchedBy(func(w models.Workout) bool {
+		return w.Sets[0].DistanceMeters == nil && *w.Sets[0].DurationSeconds == 300
+	}), mock.Anything).Return(models.Activity{ID: 31}, nil)
+	// A failed record check does not fail an upload that is already stored
+	recordRepo.On("PreviousBests", ctx, 5, mock.Anything).Return(models.RecordBests{}, errors.New("connection reset"))
+
+	created, err := service.UploadActivity(ctx, viewer, 6, models.ActivityGPX, []byte(testGPX))
+	assert.NoError(t, err)
+	assert.Equal(t, 31, created.ID)
+	assert.Empty(t, created.Records)
+	repo.AssertExpectations(t)
+}
+
+func TestActivityService_UploadActivity_DistanceTooLong(t *testing.T) {
+	ctx := context.Background()
+	service, repo, exerciseRepo, _ := newTestActivityService()
+
+	// Every point jumps to the other side of the Earth
+	var points strings.Builder
+	for i := 0; i < 7; i++ {
+		fmt.Fprintf(&points, `<trkpt lat="0" lon="%d"><time>2026-03-02T07:0%d:00Z</time></trkpt>`, (i%2)*180, i)
+	}
+	gpx := `<gpx><trk><type>running</type><trkseg>` + points.String() + `</trkseg></trk></gpx>`
+
+	exerciseRepo.On("GetById", ctx, 6).Return(models.Exercise{ID: 6, Visibility: models.VisibilityGlobal, TrackingType: models.TrackingDuration}, nil)
+
+	_, err := service.UploadActivity(ctx, models.Viewer{UserID: 5}, 6, models.ActivityGPX, []byte(gpx))
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Contains(t, err.Error(), "distance must be less than")
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestActivityService_UploadActivity_RejectsExercise(t *testing.T) {
+	ctx := context.Background()
+	service, repo, exerciseRepo, _ := newTestActivityService()
+	viewer := models.Viewer{UserID: 5, Role: models.RoleUser}
+	owner := 9
+
+	exerciseRepo.On("GetById", ctx, 2).Return(models.Exercise{ID: 2, Visibility: models.VisibilityGlobal, TrackingType: models.TrackingWeightReps}, nil)
+	exerciseRepo.On("GetById", ctx, 3).Return(models.Exercise{ID: 3, OwnerID: &owner, Visibility: models.VisibilityPrivate, TrackingType: models.TrackingDistanceDuration}, nil)
+
+	for _, id := range []int{0, 2, 3} {
+		_, err := service.UploadActivity(ctx, viewer, id, "", []byte(testGPX))
+		assert.ErrorIs(t, err, apperrors.ErrValidation)
+		var appErr *apperrors.Error
+		if assert.ErrorAs(t, err, &appErr) {
+			assert.Equal(t, "exercise_id", appErr.Fields[0].Field)
+		}
+	}
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestActivityService_UploadActivity_BadFile(t *testing.T) {
+	service, repo, _, _ := newTestActivityService()
+
+	_, err := service.UploadActivity(context.Background(), models.Viewer{UserID: 5}, 4, "", []byte("not an activity"))
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	_, err = service.UploadActivity(context.Background(), models.Viewer{UserID: 5}, 4, "kml", []byte(testGPX))
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestActivityService_ActivityPoints_OtherUser(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _, _ := newTestActivityService()
+
+	repo.On("GetById", ctx, 30).Return(models.Activity{ID: 30, UserID: 9}, nil)
+
+	_, err := service.ActivityPoints(ctx, 5, 30)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	repo.AssertNotCalled(t, "Points", mock.Anything, mock.Anything)
+}
+
+func TestActivityService_ListActivities(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _, _ := newTestActivityService()
+
+	repo.On("ListByUser", ctx, 5, "cycling", mock.MatchedBy(func(p pagination.Params) bool {
+		return p.Sort == "started_at" && p.Desc
+	})).Return(pagination.Page[models.Activity]{Items: []models.Activity{{ID: 30}}}, nil)
+
+	page, err := service.ListActivities(ctx, 5, "cycling", pagination.Query{})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+}
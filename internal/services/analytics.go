@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+	"workout-api/internal/repository"
+)
+
+// Analytics defaults and limits. Ranges default to the year before To,
+// or the twelve weeks before it for muscle volume.
+const (
+	defaultE1RMSetLimit    = 100
+	maxE1RMSetLimit        = 500
+	defaultRangeYears      = 1
+	defaultVolumeWeeks     = 12
+	maxVolumeWeeks         = 104
+	defaultHardSetRPE      = 7.0
+	defaultSecondaryWeight = 0.5
+)
+
+type AnalyticsService struct {
+	repo         repository.AnalyticsRepositoryInterface
+	exerciseRepo repository.ExerciseRepositoryInterface
+}
+
+func NewAnalyticsService(repo repository.AnalyticsRepositoryInterface, exerciseRepo repository.ExerciseRepositoryInterface) *AnalyticsService {
+	return &AnalyticsService{repo: repo, exerciseRepo: exerciseRepo}
+}
+
+// E1RMSets lists the viewer's sets of an exercise with their estimated
+// one-rep max, newest first. Sets above models.MaxE1RMReps reps or without
+// load are left out.
+func (s *AnalyticsService) E1RMSets(ctx context.Context, viewer models.Viewer, q models.E1RMQuery, limit int) ([]models.SetE1RM, error) {
+	q, err := s.prepare(ctx, viewer, q)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case limit == 0:
+		limit = defaultE1RMSetLimit
+	case limit < 0 || limit > maxE1RMSetLimit:
+		return nil, apperrors.Invalid("limit", fmt.Sprintf("limit must be between 1 and %d", maxE1RMSetLimit))
+	}
+	return s.repo.E1RMSets(ctx, viewer.UserID, q, limit)
+}
+
+// E1RMSeries returns the viewer's best estimated one-rep max of an
+// exercise per day, week or month
+func (s *AnalyticsService) E1RMSeries(ctx context.Context, viewer models.Viewer, q models.E1RMQuery) (models.E1RMSeries, error) {
+	q, err := s.prepare(ctx, viewer, q)
+	if err != nil {
+		return models.E1RMSeries{}, err
+	}
+	points, err := s.repo.E1RMSeries(ctx, viewer.UserID, q)
+	if err != nil {
+		return models.E1RMSeries{}, err
+	}
+	return models.E1RMSeries{
+		ExerciseID: q.ExerciseID,
+		Formula:    q.Formula,
+		Bucket:     q.Bucket,
+		From:       *q.From,
+		To:         *q.To,
+		Points:     points,
+	}, nil
+}
+
+// prepare validates q and fills in its defaults: the Epley formula, daily
+// buckets and the year up to now. The exercise must be visible to the viewer.
+func (s *AnalyticsService) prepare(ctx context.Context, viewer models.Viewer, q models.E1RMQuery) (models.E1RMQuery, error) {
+	var fields []apperrors.FieldError
+	if q.Formula == "" {
+		q.Formula = models.FormulaEpley
+	}
+	if q.Formula != models.FormulaEpley && q.Formula != models.FormulaBrzycki {
+		fields = append(fields, apperrors.FieldError{Field: "formula", Message: "formula must be epley or brzycki"})
+	}
+	if q.Bucket == "" {
+		q.Bucket = models.BucketDay
+	}
+	switch q.Bucket {
+	case models.BucketDay, models.BucketWeek, models.BucketMonth:
+	default:
+		fields = append(fields, apperrors.FieldError{Field: "bucket", Message: "bucket must be day, week or month"})
+	}
+	if q.To == nil {
+		now := time.Now()
+		q.To = &now
+	}
+	if q.From == nil {
+		from := q.To.AddDate(-defaultRangeYears, 0, 0)
+		q.From = &from
+	}
+	if !q.From.Before(*q.To) {
+		fields = append(fields, apperrors.FieldError{Field: "from", Message: "from must be before to"})
+	}
+	if len(fields) > 0 {
+		return models.E1RMQuery{}, apperrors.Validation(fields...)
+	}
+
+	exercise, err := s.exerciseRepo.GetById(ctx, q.ExerciseID)
+	if err != nil {
+		return models.E1RMQuery{}, err
+	}
+	if !exercise.VisibleTo(viewer) {
+		return models.E1RMQuery{}, apperrors.NotFound("exercise", q.ExerciseID)
+	}
+	return q, nil
+}
+
+// MuscleVolume returns the user's sets, hard sets and tonnage per muscle
+// group per ISO week, with each muscle's status against its volume
+// landmarks. From is moved back to the start of its week.
+func (s *AnalyticsService) MuscleVolume(ctx context.Context, userID int, q models.MuscleVolumeQuery) (models.MuscleVolumeReport, error) {
+	q, err := prepareMuscleVolume(q)
+	if err != nil {
+		return models.MuscleVolumeReport{}, err
+	}
+	rows, err := s.repo.MuscleVolume(ctx, userID, q)
+	if err != nil {
+		return models.MuscleVolumeReport{}, err
+	}
+
+	report := models.MuscleVolumeReport{
+		From:            *q.From,
+		To:              *q.To,
+		RPEThreshold:    *q.RPEThreshold,
+		SecondaryWeight: *q.SecondaryWeight,
+		Weeks:           []models.MuscleVolumeWeek{},
+	}
+	for _, row := range rows {
+		last := len(report.Weeks) - 1
+		if last < 0 || !report.Weeks[last].WeekStart.Equal(row.WeekStart) {
+			year, week := row.WeekStart.ISOWeek()
+			report.Weeks = append(report.Weeks, models.MuscleVolumeWeek{WeekStart: row.WeekStart, ISOYear: year, ISOWeek: week})
+			last++
+		}
+		volume := row.MuscleVolume
+		if volume.Landmarks != nil {
+			volume.Status = volume.Landmarks.Status(volume.HardSets)
+		}
+		report.Weeks[last].Muscles = append(report.Weeks[last].Muscles, volume)
+	}
+	return report, nil
+}
+
+// prepareMuscleVolume validates q and fills in its defaults: hard sets from
+// RPE 7, secondary muscles at half a set and the twelve weeks up to now
+func prepareMuscleVolume(q models.MuscleVolumeQuery) (models.MuscleVolumeQuery, error) {
+	var fields []apperrors.FieldError
+	if q.RPEThreshold == nil {
+		rpe := defaultHardSetRPE
+		q.RPEThreshold = &rpe
+	}
+	if *q.RPEThreshold < 1 || *q.RPEThreshold > 10 {
+		fields = append(fields, apperrors.FieldError{Field: "rpe", Message: "RPE must be between 1 and 10"})
+	}
+	if q.SecondaryWeight == nil {
+		weight := defaultSecondaryWeight
+		q.SecondaryWeight = &weight
+	}
+	if *q.SecondaryWeight < 0 || *q.SecondaryWeight > 1 {
+		fields = append(fields, apperrors.FieldError{Field: "secondary_weight", Message: "secondary weight must be between 0 and 1"})
+	}
+	if q.To == nil {
+		now := time.Now()
+		q.To = &now
+	}
+	if q.From == nil {
+		from := q.To.AddDate(0, 0, -7*defaultVolumeWeeks)
+		q.From = &from
+	}
+	from := startOfWeek(*q.From)
+	q.From = &from
+	switch {
+	case !q.From.Before(*q.To):
+		fields = append(fields, apperrors.FieldError{Field: "from", Message: "from must be before to"})
+	case q.To.Sub(*q.From) > maxVolumeWeeks*7*24*time.Hour:
+		fields = append(fields, apperrors.FieldError{Field: "from", Message: fmt.Sprintf("range cannot exceed %d weeks", maxVolumeWeeks)})
+	}
+	if len(fields) > 0 {
+		return models.MuscleVolumeQuery{}, apperrors.Validation(fields...)
+	}
+	return q, nil
+}
+
+// startOfWeek returns midnight on the Monday of t's week
+func startOfWeek(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, t.Location())
+}
+
+// EstimateOneRepMax applies formula to a set the way the analytics queries
+// do, rounded to two decimals. It reports false for sets the estimate
+// does not cover.
+func EstimateOneRepMax(formula string, weight float64, reps int) (float64, bool) {
+	if weight <= 0 || reps < 1 || reps > models.MaxE1RMReps {
+		return 0, false
+	}
+	var e1rm float64
+	switch formula {
+	case models.FormulaEpley:
+		e1rm = weight
+		if reps > 1 {
+			e1rm = weight * (1 + float64(reps)/30)
+		}
+	case models.FormulaBrzycki:
+		e1rm = weight * 36 / float64(37-reps)
+	default:
+		return 0, false
+	}
+	return math.Round(e1rm*100) / 100, true
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+	"workout-api/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock AnalyticsRepository that implements repository.AnalyticsRepositoryInterface
+type MockAnalyticsRepository struct {
+	mock.Mock
+}
+
+func (m *MockAnalyticsRepository) E1RMSets(ctx context.Context, userID int, q models.E1RMQuery, limit int) ([]models.SetE1RM, error) {
+	args := m.Called(ctx, userID, q, limit)
+	return args.Get(0).([]models.SetE1RM), args.Error(1)
+}
+
+func (m *MockAnalyticsRepository) E1RMSeries(ctx context.Context, userID int, q models.E1RMQuery) ([]models.E1RMPoint, error) {
+	args := m.Called(ctx, userID, q)
+	return args.Get(0).([]models.E1RMPoint), args.Error(1)
+}
+
+func (m *MockAnalyticsRepository) MuscleVolume(ctx context.Context, userID int, q models.MuscleVolumeQuery) ([]models.MuscleVolumeRow, error) {
+	args := m.Called(ctx, userID, q)
+	return args.Get(0).([]models.MuscleVolumeRow), args.Error(1)
+}
+
+var _ repository.AnalyticsRepositoryInterface = (*MockAnalyticsRepository)(nil)
+
+func newTestAnalyticsService() (*AnalyticsService, *MockAnalyticsRepository, *MockExerciseRepository) {
+	repo := new(MockAnalyticsRepository)
+	exerciseRepo := new(MockExerciseRepository)
+	return NewAnalyticsService(repo, exerciseRepo), repo, exerciseRepo
+}
+
+func TestAnalyticsService_E1RMSeries_Defaults(t *testing.T) {
+	ctx := context.Background()
+	service, repo, exerciseRepo := newTestAnalyticsService()
+
+	to := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	exerciseRepo.On("GetById", mock.Anything, 3).Return(models.Exercise{ID: 3, Visibility: models.VisibilityGlobal}, nil)
+	repo.On("E1RMSeries", mock.Anything, 1, mock.MatchedBy(func(q models.E1RMQuery) bool {
+		return q.Formula == models.FormulaEpley && q.Bucket == models.BucketDay &&
+			q.From.Equal(time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)) && q.To.Equal(to)
+	})).Return([]models.E1RMPoint{{E1RM: 116.67}}, nil)
+
+	series, err := service.E1RMSeries(ctx, models.Viewer{UserID: 1}, models.E1RMQuery{ExerciseID: 3, To: &to})
+	assert.NoError(t, err)
+	assert.Equal(t, models.FormulaEpley, series.Formula)
+	assert.Equal(t, models.BucketDay, series.Bucket)
+	assert.Len(t, series.Points, 1)
+	repo.AssertExpectations(t)
+}
+
+func TestAnalyticsService_E1RMSeries_Invalid(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _ := newTestAnalyticsService()
+
+	from := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, -1, 0)
+
+	_, err := service.E1RMSeries(ctx, models.Viewer{UserID: 1}, models.E1RMQuery{ExerciseID: 3, Formula: "lombardi", Bucket: "year", From: &from, To: &to})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	var appErr *apperrors.Error
+	assert.ErrorAs(t, err, &appErr)
+	assert.Len(t, appErr.Fields, 3)
+	repo.AssertNotCalled(t, "E1RMSeries", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAnalyticsService_E1RMSeries_HiddenExercise(t *testing.T) {
+	ctx := context.Background()
+	service, repo, exerciseRepo := newTestAnalyticsService()
+
+	ownerID := 2
+	exerciseRepo.On("GetById", mock.Anything, 3).Return(models.Exercise{ID: 3, OwnerID: &ownerID, Visibility: models.VisibilityPrivate}, nil)
+
+	_, err := service.E1RMSeries(ctx, models.Viewer{UserID: 1}, models.E1RMQuery{ExerciseID: 3})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	repo.AssertNotCalled(t, "E1RMSeries", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAnalyticsService_E1RMSets_Limit(t *testing.T) {
+	ctx := context.Background()
+	service, repo, exerciseRepo := newTestAnalyticsService()
+
+	exerciseRepo.On("GetById", mock.Anything, 3).Return(models.Exercise{ID: 3, Visibility: models.VisibilityGlobal}, nil)
+	repo.On("E1RMSets", mock.Anything, 1, mock.Anything, defaultE1RMSetLimit).Return([]models.SetE1RM{}, nil)
+
+	_, err := service.E1RMSets(ctx, models.Viewer{UserID: 1}, models.E1RMQuery{ExerciseID: 3, Formula: models.FormulaBrzycki}, 0)
+	assert.NoError(t, err)
+
+	_, err = service.E1RMSets(ctx, models.Viewer{UserID: 1}, models.E1RMQuery{ExerciseID: 3}, 1000)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	repo.AssertNumberOfCalls(t, "E1RMSets", 1)
+}
+
+func TestAnalyticsService_MuscleVolume(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _ := newTestAnalyticsService()
+
+	// A Thursday; the range starts on the Monday of its week
+	from := time.Date(2024, 6, 6, 15, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 17, 0, 0, 0, 0, time.UTC)
+	week1 := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	week2 := week1.AddDate(0, 0, 7)
+	chest := &models.VolumeLandmarks{MV: 8, MEV: 10, MAV: 20, MRV: 22}
+
+	repo.On("MuscleVolume", mock.Anything, 1, mock.MatchedBy(func(q models.MuscleVolumeQuery) bool {
+		return q.From.Equal(week1) && q.To.Equal(to) && *q.RPEThreshold == 7 && *q.SecondaryWeight == 0.5
+	})).Return([]models.MuscleVolumeRow{
+		{WeekStart: week1, MuscleVolume: models.MuscleVolume{MuscleGroup: "chest", Sets: 12, HardSets: 9, Tonnage: 7200, Landmarks: chest}},
+		{WeekStart: week1, MuscleVolume: models.MuscleVolume{MuscleGroup: "neck", Sets: 3, HardSets: 3, Tonnage: 300}},
+		{WeekStart: week2, MuscleVolume: models.MuscleVolume{MuscleGroup: "chest", Sets: 24, HardSets: 23, Tonnage: 15000, Landmarks: chest}},
+	}, nil)
+
+	report, err := service.MuscleVolume(ctx, 1, models.MuscleVolumeQuery{From: &from, To: &to})
+	assert.NoError(t, err)
+	assert.Equal(t, week1, report.From)
+	assert.Equal(t, 0.5, report.SecondaryWeight)
+	assert.Len(t, report.Weeks, 2)
+
+	assert.Equal(t, 2024, report.Weeks[0].ISOYear)
+	assert.Equal(t, 23, report.Weeks[0].ISOWeek)
+	assert.Len(t, report.Weeks[0].Muscles, 2)
+	assert.Equal(t, models.VolumeMaintenance, report.Weeks[0].Muscles[0].Status)
+	assert.Empty(t, report.Weeks[0].Muscles[1].Status)
+
+	assert.Equal(t, 24, report.Weeks[1].ISOWeek)
+	assert.Equal(t, models.VolumeAboveMRV, report.Weeks[1].Muscles[0].Status)
+	repo.AssertExpectations(t)
+}
+
+func TestAnalyticsService_MuscleVolume_Invalid(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _ := newTestAnalyticsService()
+
+	rpe := 11.0
+	weight := 1.5
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := service.MuscleVolume(ctx, 1, models.MuscleVolumeQuery{From: &from, To: &to, RPEThreshold: &rpe, SecondaryWeight: &weight})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	var appErr *apperrors.Error
+	assert.ErrorAs(t, err, &appErr)
+	assert.Len(t, appErr.Fields, 3)
+	repo.AssertNotCalled(t, "MuscleVolume", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVolumeLandmarks_Status(t *testing.T) {
+	landmarks := models.VolumeLandmarks{MV: 6, MEV: 8, MAV: 18, MRV: 20}
+	assert.Equal(t, models.VolumeBelowMaintenance, landmarks.Status(4))
+	assert.Equal(t, models.VolumeMaintenance, landmarks.Status(7.5))
+	assert.Equal(t, models.VolumeProductive, landmarks.Status(8))
+	assert.Equal(t, models.VolumeProductive, landmarks.Status(18))
+	assert.Equal(t, models.VolumeHigh, landmarks.Status(20))
+	assert.Equal(t, models.VolumeAboveMRV, landmarks.Status(20.5))
+}
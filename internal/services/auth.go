@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/auth"
+	"workout-api/internal/models"
+	"workout-api/internal/repository"
+)
+
+var (
+	ErrInvalidRefreshToken = apperrors.Unauthorized("invalid or expired refresh token")
+	// ErrRefreshTokenReused means an already rotated token was presented
+	// again; the whole token family is revoked when this happens
+	ErrRefreshTokenReused = apperrors.Unauthorized("refresh token has already been used")
+)
+
+// TokenPair is returned on login and refresh
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	TokenType        string    `json:"token_type"`
+}
+
+type AuthService struct {
+	userService *UserService
+	tokens      *auth.TokenManager
+	refreshRepo repository.RefreshTokenRepositoryInterface
+	refreshTTL  time.Duration
+}
+
+func NewAuthService(userService *UserService, tokens *auth.TokenManager, refreshRepo repository.RefreshTokenRepositoryInterface, refreshTTL time.Duration) *AuthService {
+	return &AuthService{userService: userService, tokens: tokens, refreshRepo: refreshRepo, refreshTTL: refreshTTL}
+}
+
+// Login verifies the credentials and starts a new refresh token family
+func (s *AuthService) Login(ctx context.Context, email, password string) (TokenPair, error) {
+	user, err := s.userService.VerifyCredentials(ctx, email, password)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	familyID, err := auth.NewFamilyID()
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	pair, _, err := s.issue(ctx, user, familyID)
+	return pair, err
+}
+
+// Refresh rotates a refresh token. Every refresh token can be used exactly
+// once; presenting a rotated token again revokes every token in its family.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (TokenPair, error) {
+	stored, err := s.refreshRepo.GetByHash(ctx, auth.HashRefreshToken(refreshToken))
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return TokenPair{}, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if stored.RevokedAt != nil {
+		s.revokeFamily(ctx, stored)
+		return TokenPair{}, ErrRefreshTokenReused
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	// Reload the user so role changes take effect on the next access token
+	user, err := s.userService.GetUserByID(ctx, stored.UserID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return TokenPair{}, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	pair, next, err := s.issue(ctx, user, stored.FamilyID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	// Revoke only succeeds for the first caller, so a token refreshed twice
+	// concurrently is treated as reuse as well
+	revoked, err := s.refreshRepo.Revoke(ctx, stored.ID, &next.ID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if !revoked {
+		s.revokeFamily(ctx, stored)
+		return TokenPair{}, ErrRefreshTokenReused
+	}
+
+	return pair, nil
+}
+
+// Logout revokes the refresh token family the given token belongs to.
+// Unknown tokens are ignored so logout is idempotent.
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	stored, err := s.refreshRepo.GetByHash(ctx, auth.HashRefreshToken(refreshToken))
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.refreshRepo.RevokeFamily(ctx, stored.FamilyID)
+}
+
+func (s *AuthService) issue(ctx context.Context, user models.User, familyID string) (TokenPair, models.RefreshToken, error) {
+	accessToken, accessExpiresAt, err := s.tokens.Issue(user.ID, user.Role)
+	if err != nil {
+		return TokenPair{}, models.RefreshToken{}, err
+	}
+
+	refreshToken, refreshHash, err := auth.NewRefreshToken()
+	if err != nil {
+		return TokenPair{}, models.RefreshToken{}, err
+	}
+
+	stored, err := s.refreshRepo.Create(ctx, models.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: refreshHash,
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	})
+	if err != nil {
+		return TokenPair{}, models.RefreshToken{}, err
+	}
+
+	pair := TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: stored.ExpiresAt,
+		TokenType:        "Bearer",
+	}
+	return pair, stored, nil
+}
+
+func (s *AuthService) revokeFamily(ctx context.Context, token models.RefreshToken) {
+	log.Printf("refresh token reuse detected for user %d, revoking family %s", token.UserID, token.FamilyID)
+	if err := s.refreshRepo.RevokeFamily(ctx, token.FamilyID); err != nil {
+		log.Printf("failed to revoke refresh token family %s: %v", token.FamilyID, err)
+	}
+}
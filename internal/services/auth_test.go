@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/auth"
+	"workout-api/internal/models"
+	"workout-api/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Mock RefreshTokenRepository that implements repository.RefreshTokenRepositoryInterface
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Create(ctx context.Context, token models.RefreshToken) (models.RefreshToken, error) {
+	args := m.Called(ctx, token)
+	return args.Get(0).(models.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (models.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	return args.Get(0).(models.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) Revoke(ctx context.Context, id int, replacedBy *int) (bool, error) {
+	args := m.Called(ctx, id, replacedBy)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	args := m.Called(ctx, familyID)
+	return args.Error(0)
+}
+
+// Ensure MockRefreshTokenRepository implements the interface
+var _ repository.RefreshTokenRepositoryInterface = (*MockRefreshTokenRepository)(nil)
+
+func newTestAuthService() (*AuthService, *MockUserRepository, *MockRefreshTokenRepository) {
+	userRepo := new(MockUserRepository)
+	tokenRepo := new(MockRefreshTokenRepository)
+	tokens := auth.NewTokenManager("test-secret", time.Minute)
+	return NewAuthService(newTestUserService(userRepo), tokens, tokenRepo, time.Hour), userRepo, tokenRepo
+}
+
+func TestAuthService_Login(t *testing.T) {
+	ctx := context.Background()
+	service, userRepo, tokenRepo := newTestAuthService()
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	userRepo.On("GetByEmail", mock.Anything, "john@example.com").Return(models.User{ID: 1, Password: string(hash)}, nil)
+	tokenRepo.On("Create", mock.Anything, mock.MatchedBy(func(tok models.RefreshToken) bool {
+		return tok.UserID == 1 && len(tok.TokenHash) == 64 && tok.FamilyID != ""
+	})).Return(models.RefreshToken{ID: 1, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+
+	pair, err := service.Login(ctx, "john@example.com", "secret")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, pair.AccessToken)
+	assert.NotEmpty(t, pair.RefreshToken)
+	assert.Equal(t, "Bearer", pair.TokenType)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestAuthService_Login_InvalidCredentials(t *testing.T) {
+	ctx := context.Background()
+	service, userRepo, tokenRepo := newTestAuthService()
+
+	userRepo.On("GetByEmail", mock.Anything, "john@example.com").Return(models.User{}, apperrors.NotFound("user", "john@example.com"))
+
+	_, err := service.Login(ctx, "john@example.com", "secret")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	tokenRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Refresh_Rotates(t *testing.T) {
+	ctx := context.Background()
+	service, userRepo, tokenRepo := newTestAuthService()
+
+	// The user is reloaded so the new access token carries their current role
+	userRepo.On("GetById", mock.Anything, 1).Return(models.User{ID: 1, Role: models.RoleAdmin}, nil)
+	stored := models.RefreshToken{ID: 5, UserID: 1, FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour)}
+	tokenRepo.On("GetByHash", mock.Anything, auth.HashRefreshToken("old-token")).Return(stored, nil)
+	tokenRepo.On("Create", mock.Anything, mock.MatchedBy(func(tok models.RefreshToken) bool {
+		return tok.UserID == 1 && tok.FamilyID == "family"
+	})).Return(models.RefreshToken{ID: 6, UserID: 1, FamilyID: "family"}, nil)
+	tokenRepo.On("Revoke", mock.Anything, 5, mock.MatchedBy(func(replacedBy *int) bool {
+		return replacedBy != nil && *replacedBy == 6
+	})).Return(true, nil)
+
+	pair, err := service.Refresh(ctx, "old-token")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "old-token", pair.RefreshToken)
+
+	claims, err := service.tokens.Parse(pair.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, claims.Role)
+	tokenRepo.AssertExpectations(t)
+	tokenRepo.AssertNotCalled(t, "RevokeFamily", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Refresh_ReuseRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	service, _, tokenRepo := newTestAuthService()
+
+	revokedAt := time.Now().Add(-time.Minute)
+	stored := models.RefreshToken{ID: 5, UserID: 1, FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}
+	tokenRepo.On("GetByHash", mock.Anything, auth.HashRefreshToken("old-token")).Return(stored, nil)
+	tokenRepo.On("RevokeFamily", mock.Anything, "family").Return(nil)
+
+	_, err := service.Refresh(ctx, "old-token")
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+	tokenRepo.AssertExpectations(t)
+	tokenRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Refresh_ConcurrentReuse(t *testing.T) {
+	ctx := context.Background()
+	service, userRepo, tokenRepo := newTestAuthService()
+
+	userRepo.On("GetById", mock.Anything, 1).Return(models.User{ID: 1}, nil)
+	stored := models.RefreshToken{ID: 5, UserID: 1, FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour)}
+	tokenRepo.On("GetByHash", mock.Anything, auth.HashRefreshToken("old-token")).Return(stored, nil)
+	tokenRepo.On("Create", mock.Anything, mock.Anything).Return(models.RefreshToken{ID: 6, UserID: 1, FamilyID: "family"}, nil)
+	tokenRepo.On("Revoke", mock.Anything, 5, mock.Anything).Return(false, nil)
+	tokenRepo.On("RevokeFamily", mock.Anything, "family").Return(nil)
+
+	_, err := service.Refresh(ctx, "old-token")
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestAuthService_Refresh_Expired(t *testing.T) {
+	ctx := context.Background()
+	service, _, tokenRepo := newTestAuthService()
+
+	stored := models.RefreshToken{ID: 5, UserID: 1, FamilyID: "family", ExpiresAt: time.Now().Add(-time.Minute)}
+	tokenRepo.On("GetByHash", mock.Anything, auth.HashRefreshToken("old-token")).Return(stored, nil)
+
+	_, err := service.Refresh(ctx, "old-token")
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	tokenRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Refresh_Unknown(t *testing.T) {
+	ctx := context.Background()
+	service, _, tokenRepo := newTestAuthService()
+
+	tokenRepo.On("GetByHash", mock.Anything, auth.HashRefreshToken("nope")).Return(models.RefreshToken{}, apperrors.NotFound("refresh token", nil))
+
+	_, err := service.Refresh(ctx, "nope")
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	ctx := context.Background()
+	service, _, tokenRepo := newTestAuthService()
+
+	stored := models.RefreshToken{ID: 5, UserID: 1, FamilyID: "family"}
+	tokenRepo.On("GetByHash", mock.Anything, auth.HashRefreshToken("token")).Return(stored, nil)
+	tokenRepo.On("RevokeFamily", mock.Anything, "family").Return(nil)
+
+	err := service.Logout(ctx, "token")
+	assert.NoError(t, err)
+	tokenRepo.AssertExpectations(t)
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/catalog"
+	"workout-api/internal/models"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// maxSlugLength matches the exercises.slug column
+const maxSlugLength = 100
+
+// ImportCatalog upserts a catalog's entries into the global catalog by
+// slug. Each entry is validated like a created exercise; invalid entries
+// are reported and skipped while the valid ones are written together. A dry
+// run validates without writing. Only admins may import.
+func (s *ExerciseService) ImportCatalog(ctx context.Context, viewer models.Viewer, c catalog.Catalog, dryRun bool) (models.CatalogImportReport, error) {
+	if !viewer.IsAdmin() {
+		return models.CatalogImportReport{}, apperrors.Forbidden("only admins can import the exercise catalog")
+	}
+
+	report := models.CatalogImportReport{Version: c.Version, DryRun: dryRun, Total: len(c.Exercises)}
+	exercises := make([]models.Exercise, 0, len(c.Exercises))
+	seen := map[string]int{}
+
+	for _, entry := range c.Exercises {
+		exercise, fields, err := s.validateCatalogEntry(ctx, entry, seen)
+		if err != nil {
+			return models.CatalogImportReport{}, err
+		}
+		if len(fields) > 0 {
+			report.Errors = append(report.Errors, models.CatalogRowError{Row: entry.Row, Slug: entry.Slug, Fields: fields})
+			continue
+		}
+		seen[entry.Slug] = entry.Row
+		exercises = append(exercises, exercise)
+	}
+	report.Failed = len(report.Errors)
+
+	if dryRun || len(exercises) == 0 {
+		return report, nil
+	}
+
+	results, err := s.repo.UpsertCatalog(ctx, exercises)
+	if err != nil {
+		return models.CatalogImportReport{}, err
+	}
+	for _, res := range results {
+		if res.Created {
+			report.Created++
+		} else {
+			report.Updated++
+		}
+	}
+	return report, nil
+}
+
+// validateCatalogEntry canonicalizes one entry, returning its problems as
+// field errors. The error is reserved for failures unrelated to the entry.
+func (s *ExerciseService) validateCatalogEntry(ctx context.Context, entry catalog.Entry, seen map[string]int) (models.Exercise, []apperrors.FieldError, error) {
+	var fields []apperrors.FieldError
+	switch {
+	case entry.Slug == "":
+		fields = append(fields, apperrors.FieldError{Field: "slug", Message: "slug is required"})
+	case len(entry.Slug) > maxSlugLength || !slugPattern.MatchString(entry.Slug):
+		fields = append(fields, apperrors.FieldError{Field: "slug", Message: fmt.Sprintf("slug must be lowercase letters, digits and single hyphens, at most %d characters", maxSlugLength)})
+	default:
+		if row, ok := seen[entry.Slug]; ok {
+			fields = append(fields, apperrors.FieldError{Field: "slug", Message: fmt.Sprintf("duplicate slug, first used on row %d", row)})
+		}
+	}
+
+	exercise := entry.ToModel()
+	err := validateExercise(exercise)
+	if err == nil {
+		exercise, err = s.canonicalize(ctx, exercise)
+	}
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) && errors.Is(err, apperrors.ErrValidation) {
+		fields = append(fields, appErr.Fields...)
+	} else if err != nil {
+		return models.Exercise{}, nil, err
+	}
+	return exercise, fields, nil
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/catalog"
+	"workout-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExerciseService_ImportCatalog(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockExerciseRepository)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
+
+	c := catalog.Catalog{FormatVersion: 1, Version: "2026.10", Exercises: []catalog.Entry{
+		{Row: 1, Slug: "push-up", Name: "Push-up", MuscleGroup: "pecs", SecondaryMuscles: []string{"Triceps"}},
+		{Row: 2, Slug: "Bad Slug", Name: "Mystery", MuscleGroup: "Spleen"},
+		{Row: 3, Slug: "push-up", Name: "Push-up again", MuscleGroup: "Chest"},
+		{Row: 4, Slug: "dip", Name: "Dip", MuscleGroup: "Chest", EquipmentType: "Bodyweight"},
+	}}
+
+	mockRepo.On("UpsertCatalog", mock.Anything, mock.MatchedBy(func(exercises []models.Exercise) bool {
+		return len(exercises) == 2 &&
+			exercises[0].Slug == "push-up" && exercises[0].MuscleGroup == "chest" && exercises[0].EquipmentType == "bodyweight" &&
+			exercises[0].Visibility == models.VisibilityGlobal && len(exercises[0].Muscles) == 2 &&
+			exercises[1].Slug == "dip"
+	})).Return([]models.CatalogUpsert{{ID: 1, Created: true}, {ID: 2, Created: false}}, nil)
+
+	report, err := service.ImportCatalog(ctx, testAdmin, c, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "2026.10", report.Version)
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 2, report.Failed)
+
+	assert.Equal(t, 2, report.Errors[0].Row)
+	fields := map[string]bool{}
+	for _, f := range report.Errors[0].Fields {
+		fields[f.Field] = true
+	}
+	assert.True(t, fields["slug"])
+	assert.True(t, fields["muscle_group"])
+
+	assert.Equal(t, 3, report.Errors[1].Row)
+	assert.Contains(t, report.Errors[1].Fields[0].Message, "duplicate slug, first used on row 1")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExerciseService_ImportCatalog_DryRun(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockExerciseRepository)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
+
+	c := catalog.Catalog{FormatVersion: 1, Version: "1", Exercises: []catalog.Entry{
+		{Row: 1, Slug: "push-up", Name: "Push-up", MuscleGroup: "Chest"},
+	}}
+
+	report, err := service.ImportCatalog(ctx, testAdmin, c, true)
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 0, report.Failed)
+	mockRepo.AssertNotCalled(t, "UpsertCatalog", mock.Anything, mock.Anything)
+}
+
+func TestExerciseService_ImportCatalog_NameTooLong(t *testing.T) {
+	mockRepo := new(MockExerciseRepository)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
+
+	c := catalog.Catalog{FormatVersion: 1, Version: "1", Exercises: []catalog.Entry{
+		{Row: 1, Slug: "push-up", Name: strings.Repeat("x", maxNameLength+1), MuscleGroup: "Chest"},
+	}}
+
+	report, err := service.ImportCatalog(context.Background(), testAdmin, c, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Failed)
+	if assert.Len(t, report.Errors, 1) {
+		assert.Equal(t, "name", report.Errors[0].Fields[0].Field)
+	}
+	mockRepo.AssertNotCalled(t, "UpsertCatalog", mock.Anything, mock.Anything)
+}
+
+func TestExerciseService_ImportCatalog_AdminOnly(t *testing.T) {
+	mockRepo := new(MockExerciseRepository)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
+
+	_, err := service.ImportCatalog(context.Background(), testUser, catalog.Catalog{}, false)
+	assert.ErrorIs(t, err, apperrors.ErrForbidden)
+}
@@ -1,66 +1,402 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+	"workout-api/internal/apperrors"
 	"workout-api/internal/models"
+	"workout-api/internal/pagination"
 	"workout-api/internal/repository"
 )
 
+// defaultEquipment is assumed when an exercise names no equipment
+const defaultEquipment = "bodyweight"
+
+// maxNameLength is the longest exercise or workout name the database stores
+const maxNameLength = 255
+
 type ExerciseService struct {
-	repo repository.ExerciseRepositoryInterface
+	repo     repository.ExerciseRepositoryInterface
+	taxonomy repository.TaxonomyRepositoryInterface
 }
 
-func NewExerciseService(repo repository.ExerciseRepositoryInterface) *ExerciseService {
-	return &ExerciseService{repo: repo}
+func NewExerciseService(repo repository.ExerciseRepositoryInterface, taxonomy repository.TaxonomyRepositoryInterface) *ExerciseService {
+	return &ExerciseService{repo: repo, taxonomy: taxonomy}
 }
 
-func (s *ExerciseService) CreateExercise(exercise models.Exercise) error {
+// CreateExercise adds an exercise on behalf of viewer. Users' exercises are
+// private unless they choose to share them; only admins add global entries.
+func (s *ExerciseService) CreateExercise(ctx context.Context, viewer models.Viewer, exercise models.Exercise) (models.Exercise, error) {
 	// Basic validation
-	if exercise.Name == "" {
-		return errors.New("exercise name is required")
+	if err := validateExercise(exercise); err != nil {
+		return models.Exercise{}, err
 	}
-	if exercise.MuscleGroup == "" {
-		return errors.New("muscle group is required")
+
+	if exercise.Visibility == "" {
+		exercise.Visibility = models.VisibilityPrivate
+		if viewer.IsAdmin() {
+			exercise.Visibility = models.VisibilityGlobal
+		}
+	}
+	if err := validateVisibility(exercise.Visibility); err != nil {
+		return models.Exercise{}, err
+	}
+	if exercise.Visibility == models.VisibilityGlobal {
+		if !viewer.IsAdmin() {
+			return models.Exercise{}, apperrors.Forbidden("only admins can add global exercises")
+		}
+		exercise.OwnerID = nil
+	} else {
+		ownerID := viewer.UserID
+		exercise.OwnerID = &ownerID
+	}
+
+	exercise, err := s.canonicalize(ctx, exercise)
+	if err != nil {
+		return models.Exercise{}, err
 	}
 
-	return s.repo.Create(exercise)
+	return s.repo.Create(ctx, exercise)
 }
 
-func (s *ExerciseService) GetExerciseByID(id int) (models.Exercise, error) {
+// GetExerciseByID returns an exercise viewer may see. Other users' private
+// exercises are reported as not found so their existence is not revealed.
+func (s *ExerciseService) GetExerciseByID(ctx context.Context, viewer models.Viewer, id int) (models.Exercise, error) {
 	if id <= 0 {
-		return models.Exercise{}, errors.New("invalid exercise ID")
+		return models.Exercise{}, apperrors.Invalid("id", "invalid exercise ID")
 	}
-	return s.repo.GetById(id)
+	exercise, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return models.Exercise{}, err
+	}
+	if !exercise.VisibleTo(viewer) {
+		return models.Exercise{}, apperrors.NotFound("exercise", id)
+	}
+	return exercise, nil
 }
 
-func (s *ExerciseService) GetAllExercises() ([]models.Exercise, error) {
-	return s.repo.GetAll()
+// ListExercises returns one page of the exercises viewer can see matching
+// filter. Taxonomy filters accept any alias, e.g. muscle_group=pecs.
+func (s *ExerciseService) ListExercises(ctx context.Context, viewer models.Viewer, filter models.ExerciseFilter, query pagination.Query) (pagination.Page[models.Exercise], error) {
+	params, err := repository.ExerciseListSpec.Parse(query)
+	if err != nil {
+		return pagination.Page[models.Exercise]{}, err
+	}
+	if filter.Visibility != "" {
+		if err := validateVisibility(filter.Visibility); err != nil {
+			return pagination.Page[models.Exercise]{}, err
+		}
+	}
+	if filter.MuscleGroup, filter.EquipmentType, err = s.resolveFilters(ctx, filter.MuscleGroup, filter.EquipmentType); err != nil {
+		return pagination.Page[models.Exercise]{}, err
+	}
+	return s.repo.List(ctx, viewer, filter, params)
 }
 
-func (s *ExerciseService) GetExercisesByMuscleGroup(muscleGroup string) ([]models.Exercise, error) {
-	if muscleGroup == "" {
-		return nil, errors.New("muscle group cannot be empty")
+// maxSearchQueryLength keeps pathological queries away from the database
+const maxSearchQueryLength = 100
+
+// SearchExercises returns catalog entries ranked by relevance to a
+// free-text query, limited to those viewer can see. A zero limit means
+// pagination.DefaultLimit.
+func (s *ExerciseService) SearchExercises(ctx context.Context, viewer models.Viewer, search models.ExerciseSearch) ([]models.ExerciseSearchResult, error) {
+	search.Query = strings.TrimSpace(search.Query)
+
+	var fields []apperrors.FieldError
+	switch {
+	case search.Query == "":
+		fields = append(fields, apperrors.FieldError{Field: "q", Message: "search query is required"})
+	case len(search.Query) > maxSearchQueryLength:
+		fields = append(fields, apperrors.FieldError{Field: "q", Message: fmt.Sprintf("search query cannot exceed %d characters", maxSearchQueryLength)})
+	}
+	if search.Limit < 0 {
+		fields = append(fields, apperrors.FieldError{Field: "limit", Message: "limit must be a positive integer"})
+	}
+	if len(fields) > 0 {
+		return nil, apperrors.Validation(fields...)
+	}
+
+	if search.Limit == 0 {
+		search.Limit = pagination.DefaultLimit
+	}
+	search.Limit = min(search.Limit, pagination.MaxLimit)
+
+	var err error
+	if search.MuscleGroup, search.EquipmentType, err = s.resolveFilters(ctx, search.MuscleGroup, search.EquipmentType); err != nil {
+		return nil, err
+	}
+
+	results, err := s.repo.Search(ctx, viewer, search)
+	if err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []models.ExerciseSearchResult{}
 	}
-	return s.repo.GetByMuscleGroup(muscleGroup)
+	return results, nil
 }
 
-func (s *ExerciseService) UpdateExercise(exercise models.Exercise) error {
+// UpdateExercise replaces an exercise viewer may edit. Ownership is kept;
+// owners may switch between private and shared, while moving an entry into
+// the global catalog goes through PromoteExercise.
+func (s *ExerciseService) UpdateExercise(ctx context.Context, viewer models.Viewer, exercise models.Exercise) error {
 	if exercise.ID <= 0 {
-		return errors.New("invalid exercise ID")
+		return apperrors.Invalid("id", "invalid exercise ID")
+	}
+	if err := validateExercise(exercise); err != nil {
+		return err
+	}
+	existing, err := s.editable(ctx, viewer, exercise.ID)
+	if err != nil {
+		return err
+	}
+
+	if exercise.Visibility == "" {
+		exercise.Visibility = existing.Visibility
+	}
+	if exercise.TrackingType == "" {
+		exercise.TrackingType = existing.TrackingType
+	}
+	if err := validateVisibility(exercise.Visibility); err != nil {
+		return err
+	}
+	if (exercise.Visibility == models.VisibilityGlobal) != (existing.Visibility == models.VisibilityGlobal) {
+		return apperrors.Invalid("visibility", "global exercises cannot be made private or shared, and user exercises are made global by promotion")
+	}
+	exercise.OwnerID = existing.OwnerID
+
+	exercise, err = s.canonicalize(ctx, exercise)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Update(ctx, exercise)
+}
+
+// DeleteExercise removes an exercise viewer may edit
+func (s *ExerciseService) DeleteExercise(ctx context.Context, viewer models.Viewer, id int) error {
+	if id <= 0 {
+		return apperrors.Invalid("id", "invalid exercise ID")
+	}
+	if _, err := s.editable(ctx, viewer, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// PromoteExercise moves a user's private or shared exercise into the global
+// catalog. Only admins may promote.
+func (s *ExerciseService) PromoteExercise(ctx context.Context, viewer models.Viewer, id int) (models.Exercise, error) {
+	if !viewer.IsAdmin() {
+		return models.Exercise{}, apperrors.Forbidden("only admins can promote exercises")
+	}
+	exercise, err := s.GetExerciseByID(ctx, viewer, id)
+	if err != nil {
+		return models.Exercise{}, err
+	}
+	if exercise.Visibility == models.VisibilityGlobal {
+		return models.Exercise{}, apperrors.Conflict("exercise is already global")
+	}
+	if err := s.repo.Promote(ctx, id); err != nil {
+		return models.Exercise{}, err
 	}
+	return s.repo.GetById(ctx, id)
+}
+
+// editable loads an exercise viewer may change: hidden exercises are not
+// found, visible ones they do not own are forbidden
+func (s *ExerciseService) editable(ctx context.Context, viewer models.Viewer, id int) (models.Exercise, error) {
+	exercise, err := s.GetExerciseByID(ctx, viewer, id)
+	if err != nil {
+		return models.Exercise{}, err
+	}
+	if !exercise.EditableBy(viewer) {
+		return models.Exercise{}, apperrors.Forbidden("you can only change your own exercises")
+	}
+	return exercise, nil
+}
+
+func validateVisibility(visibility string) error {
+	switch visibility {
+	case models.VisibilityGlobal, models.VisibilityPrivate, models.VisibilityShared:
+		return nil
+	}
+	return apperrors.Invalid("visibility", fmt.Sprintf("visibility must be one of %s, %s or %s",
+		models.VisibilityGlobal, models.VisibilityPrivate, models.VisibilityShared))
+}
+
+// validateExercise collects every missing required field into one error
+func validateExercise(exercise models.Exercise) error {
+	var fields []apperrors.FieldError
 	if exercise.Name == "" {
-		return errors.New("exercise name is required")
+		fields = append(fields, apperrors.FieldError{Field: "name", Message: "exercise name is required"})
+	} else if utf8.RuneCountInString(exercise.Name) > maxNameLength {
+		fields = append(fields, apperrors.FieldError{Field: "name", Message: fmt.Sprintf("exercise name cannot be longer than %d characters", maxNameLength)})
 	}
 	if exercise.MuscleGroup == "" {
-		return errors.New("muscle group is required")
+		fields = append(fields, apperrors.FieldError{Field: "muscle_group", Message: "muscle group is required"})
 	}
+	if len(fields) > 0 {
+		return apperrors.Validation(fields...)
+	}
+	return nil
+}
 
-	return s.repo.Update(exercise)
+// validateTrackedSet checks that a set carries the measurements its
+// exercise's tracking type calls for, and none that make no sense for it
+func validateTrackedSet(trackingType string, set models.WorkoutSet) []apperrors.FieldError {
+	var fields []apperrors.FieldError
+	require := func(ok bool, field, message string) {
+		if !ok {
+			fields = append(fields, apperrors.FieldError{Field: field, Message: message})
+		}
+	}
+
+	switch trackingType {
+	case models.TrackingWeightReps:
+		require(set.Reps > 0, "reps", "reps must be greater than zero")
+		require(set.DistanceMeters == nil, "distance_meters", "distance is not tracked for this exercise")
+	case models.TrackingRepsOnly:
+		require(set.Reps > 0, "reps", "reps must be greater than zero")
+		require(set.Weight == 0, "weight", "weight is not tracked for this exercise")
+		require(set.DistanceMeters == nil, "distance_meters", "distance is not tracked for this exercise")
+	case models.TrackingDuration:
+		require(set.DurationSeconds != nil, "duration_seconds", "duration is required for this exercise")
+		require(set.Reps == 0, "reps", "reps are not tracked for this exercise")
+		require(set.DistanceMeters == nil, "distance_meters", "distance is not tracked for this exercise")
+	case models.TrackingDistanceDuration:
+		require(set.DistanceMeters != nil, "distance_meters", "distance is required for this exercise")
+		require(set.DurationSeconds != nil, "duration_seconds", "duration is required for this exercise")
+		require(set.Reps == 0, "reps", "reps are not tracked for this exercise")
+	}
+	return fields
 }
 
-func (s *ExerciseService) DeleteExercise(id int) error {
-	if id <= 0 {
-		return errors.New("invalid exercise ID")
+// canonicalize resolves the exercise's muscle groups and equipment to
+// taxonomy slugs and builds its full muscle mapping, with the main muscle
+// group first. Unknown names and contradictory roles are validation errors.
+// Exercises are tracked by weight and reps unless they say otherwise.
+func (s *ExerciseService) canonicalize(ctx context.Context, exercise models.Exercise) (models.Exercise, error) {
+	var fields []apperrors.FieldError
+
+	if exercise.TrackingType == "" {
+		exercise.TrackingType = models.TrackingWeightReps
+	}
+	switch exercise.TrackingType {
+	case models.TrackingWeightReps, models.TrackingRepsOnly, models.TrackingDuration, models.TrackingDistanceDuration:
+	default:
+		fields = append(fields, apperrors.FieldError{Field: "tracking_type", Message: fmt.Sprintf("tracking type must be one of %s, %s, %s or %s",
+			models.TrackingWeightReps, models.TrackingRepsOnly, models.TrackingDuration, models.TrackingDistanceDuration)})
+	}
+
+	main, ok, err := s.resolveMuscleGroup(ctx, exercise.MuscleGroup)
+	if err != nil {
+		return models.Exercise{}, err
+	}
+	if !ok {
+		fields = append(fields, apperrors.FieldError{Field: "muscle_group", Message: fmt.Sprintf("unknown muscle group %q", exercise.MuscleGroup)})
+	}
+
+	if exercise.EquipmentType == "" {
+		exercise.EquipmentType = defaultEquipment
+	}
+	equipment, ok, err := s.resolveEquipment(ctx, exercise.EquipmentType)
+	if err != nil {
+		return models.Exercise{}, err
+	}
+	if !ok {
+		fields = append(fields, apperrors.FieldError{Field: "equipment_type", Message: fmt.Sprintf("unknown equipment %q", exercise.EquipmentType)})
+	}
+
+	muscles := []models.ExerciseMuscle{{MuscleGroup: main, Role: models.MuscleRolePrimary}}
+	roles := map[string]string{main: models.MuscleRolePrimary}
+	for _, m := range exercise.Muscles {
+		field := m.Role + "_muscles"
+		slug, ok, err := s.resolveMuscleGroup(ctx, m.MuscleGroup)
+		if err != nil {
+			return models.Exercise{}, err
+		}
+		if !ok {
+			fields = append(fields, apperrors.FieldError{Field: field, Message: fmt.Sprintf("unknown muscle group %q", m.MuscleGroup)})
+			continue
+		}
+
+		if existing, seen := roles[slug]; seen {
+			if existing != m.Role {
+				fields = append(fields, apperrors.FieldError{Field: field, Message: fmt.Sprintf("%s cannot be both a primary and a secondary muscle", slug)})
+			}
+			continue
+		}
+		roles[slug] = m.Role
+		muscles = append(muscles, models.ExerciseMuscle{MuscleGroup: slug, Role: m.Role})
+	}
+
+	if len(fields) > 0 {
+		return models.Exercise{}, apperrors.Validation(fields...)
+	}
+	exercise.MuscleGroup = main
+	exercise.EquipmentType = equipment
+	exercise.Muscles = muscles
+	return exercise, nil
+}
+
+// resolveMuscleGroup returns the slug a name refers to; ok is false when
+// the name is not in the taxonomy
+func (s *ExerciseService) resolveMuscleGroup(ctx context.Context, name string) (string, bool, error) {
+	mg, err := s.taxonomy.ResolveMuscleGroup(ctx, name)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return mg.Slug, true, nil
+}
+
+// resolveEquipment returns the slug a name refers to; ok is false when the
+// name is not in the taxonomy
+func (s *ExerciseService) resolveEquipment(ctx context.Context, name string) (string, bool, error) {
+	e, err := s.taxonomy.ResolveEquipment(ctx, name)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return e.Slug, true, nil
+}
+
+// resolveFilters canonicalizes optional muscle group and equipment filters
+func (s *ExerciseService) resolveFilters(ctx context.Context, muscleGroup, equipmentType string) (string, string, error) {
+	var fields []apperrors.FieldError
+
+	if muscleGroup != "" {
+		slug, ok, err := s.resolveMuscleGroup(ctx, muscleGroup)
+		if err != nil {
+			return "", "", err
+		}
+		if !ok {
+			fields = append(fields, apperrors.FieldError{Field: "muscle_group", Message: fmt.Sprintf("unknown muscle group %q", muscleGroup)})
+		}
+		muscleGroup = slug
+	}
+
+	if equipmentType != "" {
+		slug, ok, err := s.resolveEquipment(ctx, equipmentType)
+		if err != nil {
+			return "", "", err
+		}
+		if !ok {
+			fields = append(fields, apperrors.FieldError{Field: "equipment_type", Message: fmt.Sprintf("unknown equipment %q", equipmentType)})
+		}
+		equipmentType = slug
+	}
+
+	if len(fields) > 0 {
+		return "", "", apperrors.Validation(fields...)
 	}
-	return s.repo.Delete(id)
+	return muscleGroup, equipmentType, nil
 }
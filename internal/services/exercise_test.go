@@ -1,9 +1,12 @@
 package services
 
 import (
+	"context"
 	"testing"
 	"time"
+	"workout-api/internal/apperrors"
 	"workout-api/internal/models"
+	"workout-api/internal/pagination"
 	"workout-api/internal/repository"
 
 	"github.com/stretchr/testify/assert"
@@ -15,68 +18,183 @@ type MockExerciseRepository struct {
 	mock.Mock
 }
 
-func (m *MockExerciseRepository) Create(exercise models.Exercise) error {
-	args := m.Called(exercise)
-	return args.Error(0)
+func (m *MockExerciseRepository) Create(ctx context.Context, exercise models.Exercise) (models.Exercise, error) {
+	args := m.Called(ctx, exercise)
+	return args.Get(0).(models.Exercise), args.Error(1)
 }
 
-func (m *MockExerciseRepository) GetById(id int) (models.Exercise, error) {
-	args := m.Called(id)
+func (m *MockExerciseRepository) GetById(ctx context.Context, id int) (models.Exercise, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(models.Exercise), args.Error(1)
 }
 
-func (m *MockExerciseRepository) GetAll() ([]models.Exercise, error) {
-	args := m.Called()
-	return args.Get(0).([]models.Exercise), args.Error(1)
+func (m *MockExerciseRepository) List(ctx context.Context, viewer models.Viewer, filter models.ExerciseFilter, params pagination.Params) (pagination.Page[models.Exercise], error) {
+	args := m.Called(ctx, viewer, filter, params)
+	return args.Get(0).(pagination.Page[models.Exercise]), args.Error(1)
 }
 
-func (m *MockExerciseRepository) GetByMuscleGroup(muscleGroup string) ([]models.Exercise, error) {
-	args := m.Called(muscleGroup)
-	return args.Get(0).([]models.Exercise), args.Error(1)
+func (m *MockExerciseRepository) Search(ctx context.Context, viewer models.Viewer, search models.ExerciseSearch) ([]models.ExerciseSearchResult, error) {
+	args := m.Called(ctx, viewer, search)
+	return args.Get(0).([]models.ExerciseSearchResult), args.Error(1)
+}
+
+func (m *MockExerciseRepository) Update(ctx context.Context, exercise models.Exercise) error {
+	args := m.Called(ctx, exercise)
+	return args.Error(0)
 }
 
-func (m *MockExerciseRepository) Update(exercise models.Exercise) error {
-	args := m.Called(exercise)
+func (m *MockExerciseRepository) Promote(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockExerciseRepository) Delete(id int) error {
-	args := m.Called(id)
+func (m *MockExerciseRepository) UpsertCatalog(ctx context.Context, exercises []models.Exercise) ([]models.CatalogUpsert, error) {
+	args := m.Called(ctx, exercises)
+	return args.Get(0).([]models.CatalogUpsert), args.Error(1)
+}
+
+func (m *MockExerciseRepository) MatchNames(ctx context.Context, userID int, names map[string]string, threshold float64) (map[string]models.ExerciseMatch, error) {
+	args := m.Called(ctx, userID, names, threshold)
+	return args.Get(0).(map[string]models.ExerciseMatch), args.Error(1)
+}
+
+func (m *MockExerciseRepository) ListUsedBy(ctx context.Context, userID int) ([]models.Exercise, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.Exercise), args.Error(1)
+}
+
+func (m *MockExerciseRepository) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
 // Ensure MockExerciseRepository implements the interface
 var _ repository.ExerciseRepositoryInterface = (*MockExerciseRepository)(nil)
 
+var (
+	testUser  = models.Viewer{UserID: 1, Role: models.RoleUser}
+	testOther = models.Viewer{UserID: 2, Role: models.RoleUser}
+	testAdmin = models.Viewer{UserID: 99, Role: models.RoleAdmin}
+)
+
+func ownerID(id int) *int {
+	return &id
+}
+
 func TestExerciseService_CreateExercise(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockExerciseRepository)
-	service := NewExerciseService(mockRepo)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
 
 	exercise := models.Exercise{
 		Name:          "Push-ups",
 		MuscleGroup:   "Chest",
 		EquipmentType: "Bodyweight",
 		Notes:         "Standard push-ups",
+		Muscles: []models.ExerciseMuscle{
+			{MuscleGroup: "Triceps", Role: models.MuscleRoleSecondary},
+			{MuscleGroup: "Shoulders", Role: models.MuscleRoleSecondary},
+		},
 	}
 
-	mockRepo.On("Create", exercise).Return(nil)
+	// Names are stored as canonical slugs with the main muscle mapped first,
+	// and users' exercises default to private
+	canonical := models.Exercise{
+		Name:          "Push-ups",
+		MuscleGroup:   "chest",
+		EquipmentType: "bodyweight",
+		TrackingType:  models.TrackingWeightReps,
+		Notes:         "Standard push-ups",
+		Muscles: []models.ExerciseMuscle{
+			{MuscleGroup: "chest", Role: models.MuscleRolePrimary},
+			{MuscleGroup: "triceps", Role: models.MuscleRoleSecondary},
+			{MuscleGroup: "shoulders", Role: models.MuscleRoleSecondary},
+		},
+		OwnerID:    ownerID(testUser.UserID),
+		Visibility: models.VisibilityPrivate,
+	}
+	created := canonical
+	created.ID = 5
+
+	mockRepo.On("Create", mock.Anything, canonical).Return(created, nil)
 
-	err := service.CreateExercise(exercise)
+	result, err := service.CreateExercise(ctx, testUser, exercise)
 	assert.NoError(t, err)
+	assert.Equal(t, created, result)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestExerciseService_CreateExercise_DefaultsEquipment(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockExerciseRepository)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(e models.Exercise) bool {
+		return e.EquipmentType == "bodyweight"
+	})).Return(models.Exercise{ID: 1}, nil)
+
+	_, err := service.CreateExercise(ctx, testUser, models.Exercise{Name: "Dips", MuscleGroup: "pecs"})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExerciseService_CreateExercise_UnknownTaxonomy(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockExerciseRepository)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
+
+	exercise := models.Exercise{
+		Name:          "Mystery Lift",
+		MuscleGroup:   "Spleen",
+		EquipmentType: "Anvil",
+		TrackingType:  "laps",
+		Muscles:       []models.ExerciseMuscle{{MuscleGroup: "Chest", Role: models.MuscleRoleSecondary}},
+	}
+
+	_, err := service.CreateExercise(ctx, testUser, exercise)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	var appErr *apperrors.Error
+	assert.ErrorAs(t, err, &appErr)
+	fields := map[string]bool{}
+	for _, f := range appErr.Fields {
+		fields[f.Field] = true
+	}
+	assert.True(t, fields["muscle_group"])
+	assert.True(t, fields["equipment_type"])
+	assert.True(t, fields["tracking_type"])
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestExerciseService_CreateExercise_ConflictingRoles(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockExerciseRepository)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
+
+	// "pecs" resolves to the main muscle group, so it cannot also be secondary
+	exercise := models.Exercise{
+		Name:        "Bench Press",
+		MuscleGroup: "Chest",
+		Muscles:     []models.ExerciseMuscle{{MuscleGroup: "pecs", Role: models.MuscleRoleSecondary}},
+	}
+
+	_, err := service.CreateExercise(ctx, testUser, exercise)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Contains(t, err.Error(), "chest cannot be both a primary and a secondary muscle")
+}
+
 func TestExerciseService_CreateExercise_ValidationErrors(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockExerciseRepository)
-	service := NewExerciseService(mockRepo)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
 
 	// Test empty name
 	exercise := models.Exercise{
 		Name:        "",
 		MuscleGroup: "Chest",
 	}
-	err := service.CreateExercise(exercise)
-	assert.Error(t, err)
+	_, err := service.CreateExercise(ctx, testUser, exercise)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
 	assert.Contains(t, err.Error(), "exercise name is required")
 
 	// Test empty muscle group
@@ -84,14 +202,15 @@ func TestExerciseService_CreateExercise_ValidationErrors(t *testing.T) {
 		Name:        "Push-ups",
 		MuscleGroup: "",
 	}
-	err = service.CreateExercise(exercise)
+	_, err = service.CreateExercise(ctx, testUser, exercise)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "muscle group is required")
 }
 
 func TestExerciseService_GetExerciseByID(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockExerciseRepository)
-	service := NewExerciseService(mockRepo)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
 
 	expectedExercise := models.Exercise{
 		ID:            1,
@@ -103,73 +222,106 @@ func TestExerciseService_GetExerciseByID(t *testing.T) {
 		UpdatedAt:     time.Now(),
 	}
 
-	mockRepo.On("GetById", 1).Return(expectedExercise, nil)
+	mockRepo.On("GetById", mock.Anything, 1).Return(expectedExercise, nil)
 
-	exercise, err := service.GetExerciseByID(1)
+	exercise, err := service.GetExerciseByID(ctx, testUser, 1)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedExercise, exercise)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestExerciseService_GetExerciseByID_InvalidID(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockExerciseRepository)
-	service := NewExerciseService(mockRepo)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
 
-	exercise, err := service.GetExerciseByID(0)
+	exercise, err := service.GetExerciseByID(ctx, testUser, 0)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid exercise ID")
 	assert.Equal(t, models.Exercise{}, exercise)
 }
 
-func TestExerciseService_GetAllExercises(t *testing.T) {
+func TestExerciseService_ListExercises(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockExerciseRepository)
-	service := NewExerciseService(mockRepo)
-
-	expectedExercises := []models.Exercise{
-		{ID: 1, Name: "Push-ups", MuscleGroup: "Chest"},
-		{ID: 2, Name: "Squats", MuscleGroup: "Legs"},
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
+
+	expectedPage := pagination.Page[models.Exercise]{
+		Items: []models.Exercise{
+			{ID: 1, Name: "Push-ups", MuscleGroup: "Chest"},
+			{ID: 3, Name: "Bench Press", MuscleGroup: "Chest"},
+		},
+		Limit: pagination.DefaultLimit,
 	}
+	mockRepo.On("List", mock.Anything, testUser, models.ExerciseFilter{MuscleGroup: "chest", EquipmentType: "dumbbell"}, pagination.Params{Limit: pagination.DefaultLimit, Sort: "name"}).
+		Return(expectedPage, nil)
 
-	mockRepo.On("GetAll").Return(expectedExercises, nil)
+	page, err := service.ListExercises(ctx, testUser, models.ExerciseFilter{MuscleGroup: "pecs", EquipmentType: "db"}, pagination.Query{})
+	assert.NoError(t, err)
+	assert.Equal(t, expectedPage, page)
+	mockRepo.AssertExpectations(t)
+}
 
-	exercises, err := service.GetAllExercises()
+func TestExerciseService_ListExercises_CapsLimit(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockExerciseRepository)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
+
+	mockRepo.On("List", mock.Anything, testUser, models.ExerciseFilter{}, pagination.Params{Limit: pagination.MaxLimit, Sort: "name"}).
+		Return(pagination.Page[models.Exercise]{Items: []models.Exercise{}, Limit: pagination.MaxLimit}, nil)
+
+	_, err := service.ListExercises(ctx, testUser, models.ExerciseFilter{}, pagination.Query{Limit: "5000"})
 	assert.NoError(t, err)
-	assert.Len(t, exercises, 2)
-	assert.Equal(t, expectedExercises, exercises)
 	mockRepo.AssertExpectations(t)
 }
 
-func TestExerciseService_GetExercisesByMuscleGroup(t *testing.T) {
+func TestExerciseService_ListExercises_InvalidCursor(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockExerciseRepository)
-	service := NewExerciseService(mockRepo)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
 
-	expectedExercises := []models.Exercise{
-		{ID: 1, Name: "Push-ups", MuscleGroup: "Chest"},
-		{ID: 3, Name: "Bench Press", MuscleGroup: "Chest"},
-	}
+	_, err := service.ListExercises(ctx, testUser, models.ExerciseFilter{}, pagination.Query{Cursor: "not-a-cursor"})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	mockRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
 
-	mockRepo.On("GetByMuscleGroup", "Chest").Return(expectedExercises, nil)
+func TestExerciseService_SearchExercises(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockExerciseRepository)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
 
-	exercises, err := service.GetExercisesByMuscleGroup("Chest")
+	expected := []models.ExerciseSearchResult{
+		{Exercise: models.Exercise{ID: 2, Name: "Dumbbell Curl"}, Rank: 0.8, NameHighlight: "Dumbbell Curl"},
+	}
+	// The query is trimmed, filters canonicalized and the default limit
+	// applied before hitting the repository
+	mockRepo.On("Search", mock.Anything, testUser, models.ExerciseSearch{Query: "dumbell", MuscleGroup: "biceps", Limit: pagination.DefaultLimit}).
+		Return(expected, nil)
+
+	results, err := service.SearchExercises(ctx, testUser, models.ExerciseSearch{Query: "  dumbell ", MuscleGroup: "Biceps"})
 	assert.NoError(t, err)
-	assert.Len(t, exercises, 2)
-	assert.Equal(t, expectedExercises, exercises)
+	assert.Equal(t, expected, results)
 	mockRepo.AssertExpectations(t)
 }
 
-func TestExerciseService_GetExercisesByMuscleGroup_EmptyMuscleGroup(t *testing.T) {
+func TestExerciseService_SearchExercises_Invalid(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockExerciseRepository)
-	service := NewExerciseService(mockRepo)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
 
-	exercises, err := service.GetExercisesByMuscleGroup("")
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "muscle group cannot be empty")
-	assert.Nil(t, exercises)
+	_, err := service.SearchExercises(ctx, testUser, models.ExerciseSearch{Query: "   ", Limit: -1})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	var appErr *apperrors.Error
+	assert.ErrorAs(t, err, &appErr)
+	assert.Len(t, appErr.Fields, 2)
+	mockRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestExerciseService_UpdateExercise(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockExerciseRepository)
-	service := NewExerciseService(mockRepo)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
 
 	exercise := models.Exercise{
 		ID:            1,
@@ -179,16 +331,30 @@ func TestExerciseService_UpdateExercise(t *testing.T) {
 		Notes:         "Modified for beginners",
 	}
 
-	mockRepo.On("Update", exercise).Return(nil)
+	// Ownership, visibility and tracking are kept when the request leaves them out
+	mockRepo.On("GetById", mock.Anything, 1).
+		Return(models.Exercise{ID: 1, OwnerID: ownerID(testUser.UserID), Visibility: models.VisibilityShared, TrackingType: models.TrackingRepsOnly}, nil)
+	mockRepo.On("Update", mock.Anything, models.Exercise{
+		ID:            1,
+		Name:          "Modified Push-ups",
+		MuscleGroup:   "chest",
+		EquipmentType: "bodyweight",
+		TrackingType:  models.TrackingRepsOnly,
+		Notes:         "Modified for beginners",
+		Muscles:       []models.ExerciseMuscle{{MuscleGroup: "chest", Role: models.MuscleRolePrimary}},
+		OwnerID:       ownerID(testUser.UserID),
+		Visibility:    models.VisibilityShared,
+	}).Return(nil)
 
-	err := service.UpdateExercise(exercise)
+	err := service.UpdateExercise(ctx, testUser, exercise)
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestExerciseService_UpdateExercise_ValidationErrors(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockExerciseRepository)
-	service := NewExerciseService(mockRepo)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
 
 	// Test invalid ID
 	exercise := models.Exercise{
@@ -196,7 +362,7 @@ func TestExerciseService_UpdateExercise_ValidationErrors(t *testing.T) {
 		Name:        "Push-ups",
 		MuscleGroup: "Chest",
 	}
-	err := service.UpdateExercise(exercise)
+	err := service.UpdateExercise(ctx, testUser, exercise)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid exercise ID")
 
@@ -206,7 +372,7 @@ func TestExerciseService_UpdateExercise_ValidationErrors(t *testing.T) {
 		Name:        "",
 		MuscleGroup: "Chest",
 	}
-	err = service.UpdateExercise(exercise)
+	err = service.UpdateExercise(ctx, testUser, exercise)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "exercise name is required")
 
@@ -216,27 +382,125 @@ func TestExerciseService_UpdateExercise_ValidationErrors(t *testing.T) {
 		Name:        "Push-ups",
 		MuscleGroup: "",
 	}
-	err = service.UpdateExercise(exercise)
+	err = service.UpdateExercise(ctx, testUser, exercise)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "muscle group is required")
 }
 
 func TestExerciseService_DeleteExercise(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockExerciseRepository)
-	service := NewExerciseService(mockRepo)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
 
-	mockRepo.On("Delete", 1).Return(nil)
+	mockRepo.On("GetById", mock.Anything, 1).Return(models.Exercise{ID: 1, OwnerID: ownerID(testUser.UserID), Visibility: models.VisibilityPrivate}, nil)
+	mockRepo.On("Delete", mock.Anything, 1).Return(nil)
 
-	err := service.DeleteExercise(1)
+	err := service.DeleteExercise(ctx, testUser, 1)
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestExerciseService_DeleteExercise_InvalidID(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockExerciseRepository)
-	service := NewExerciseService(mockRepo)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
 
-	err := service.DeleteExercise(0)
+	err := service.DeleteExercise(ctx, testUser, 0)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid exercise ID")
 }
+
+func TestExerciseService_CreateExercise_Visibility(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockExerciseRepository)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
+
+	// Only admins may add to the global catalog
+	_, err := service.CreateExercise(ctx, testUser, models.Exercise{Name: "Dips", MuscleGroup: "Chest", Visibility: models.VisibilityGlobal})
+	assert.ErrorIs(t, err, apperrors.ErrForbidden)
+
+	_, err = service.CreateExercise(ctx, testUser, models.Exercise{Name: "Dips", MuscleGroup: "Chest", Visibility: "public"})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	// Admins' exercises default to global and have no owner
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(e models.Exercise) bool {
+		return e.Visibility == models.VisibilityGlobal && e.OwnerID == nil
+	})).Return(models.Exercise{ID: 1}, nil)
+
+	_, err = service.CreateExercise(ctx, testAdmin, models.Exercise{Name: "Dips", MuscleGroup: "Chest"})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExerciseService_GetExerciseByID_HidesPrivate(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockExerciseRepository)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
+
+	private := models.Exercise{ID: 1, OwnerID: ownerID(testUser.UserID), Visibility: models.VisibilityPrivate}
+	mockRepo.On("GetById", mock.Anything, 1).Return(private, nil)
+
+	_, err := service.GetExerciseByID(ctx, testOther, 1)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	exercise, err := service.GetExerciseByID(ctx, testAdmin, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, private, exercise)
+}
+
+func TestExerciseService_UpdateExercise_NotOwner(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockExerciseRepository)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
+
+	mockRepo.On("GetById", mock.Anything, 1).Return(models.Exercise{ID: 1, OwnerID: ownerID(testUser.UserID), Visibility: models.VisibilityShared}, nil)
+	mockRepo.On("GetById", mock.Anything, 2).Return(models.Exercise{ID: 2, Visibility: models.VisibilityGlobal}, nil)
+
+	// Shared exercises are visible but only their owner may change them
+	err := service.UpdateExercise(ctx, testOther, models.Exercise{ID: 1, Name: "Dips", MuscleGroup: "Chest"})
+	assert.ErrorIs(t, err, apperrors.ErrForbidden)
+
+	err = service.UpdateExercise(ctx, testUser, models.Exercise{ID: 2, Name: "Dips", MuscleGroup: "Chest"})
+	assert.ErrorIs(t, err, apperrors.ErrForbidden)
+
+	err = service.DeleteExercise(ctx, testOther, 1)
+	assert.ErrorIs(t, err, apperrors.ErrForbidden)
+
+	// Becoming global only happens through promotion
+	err = service.UpdateExercise(ctx, testUser, models.Exercise{ID: 1, Name: "Dips", MuscleGroup: "Chest", Visibility: models.VisibilityGlobal})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestExerciseService_PromoteExercise(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockExerciseRepository)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
+
+	promoted := models.Exercise{ID: 1, Visibility: models.VisibilityGlobal}
+	mockRepo.On("GetById", mock.Anything, 1).Return(models.Exercise{ID: 1, OwnerID: ownerID(testUser.UserID), Visibility: models.VisibilityShared}, nil).Once()
+	mockRepo.On("Promote", mock.Anything, 1).Return(nil)
+	mockRepo.On("GetById", mock.Anything, 1).Return(promoted, nil).Once()
+
+	exercise, err := service.PromoteExercise(ctx, testAdmin, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, promoted, exercise)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExerciseService_PromoteExercise_Rejected(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockExerciseRepository)
+	service := NewExerciseService(mockRepo, newTestTaxonomy())
+
+	_, err := service.PromoteExercise(ctx, testUser, 1)
+	assert.ErrorIs(t, err, apperrors.ErrForbidden)
+
+	mockRepo.On("GetById", mock.Anything, 2).Return(models.Exercise{ID: 2, Visibility: models.VisibilityGlobal}, nil)
+	_, err = service.PromoteExercise(ctx, testAdmin, 2)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	mockRepo.AssertNotCalled(t, "Promote", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/history"
+	"workout-api/internal/models"
+	"workout-api/internal/repository"
+)
+
+// importMatchThreshold is the name similarity an exported exercise needs to
+// be mapped onto an existing exercise rather than created
+const importMatchThreshold = 0.6
+
+// Exports say nothing about the muscles an exercise trains, so exercises
+// created by an import start out as full body until their owner edits them
+const (
+	importMuscleGroup = "full-body"
+	importEquipment   = "other"
+)
+
+type HistoryService struct {
+	workoutRepo  repository.WorkoutRepositoryInterface
+	exerciseRepo repository.ExerciseRepositoryInterface
+	exercises    *ExerciseService
+}
+
+func NewHistoryService(workoutRepo repository.WorkoutRepositoryInterface, exerciseRepo repository.ExerciseRepositoryInterface, exercises *ExerciseService) *HistoryService {
+	return &HistoryService{workoutRepo: workoutRepo, exerciseRepo: exerciseRepo, exercises: exercises}
+}
+
+// ImportHistory adds the workouts of an exported history to viewer's log.
+// Exercise names are mapped onto the global catalog and viewer's own
+// exercises; names that match nothing become private exercises. Workouts
+// already imported are skipped, so a failed import can simply be retried.
+// Sets that do not fit their exercise, or belong to a workout or exercise
+// whose name is too long to store, are left out and reported. Imported
+// sets do not raise personal records.
+func (s *HistoryService) ImportHistory(ctx context.Context, viewer models.Viewer, export history.Export) (models.HistoryImportReport, error) {
+	report := models.HistoryImportReport{Source: export.Source, Rows: export.Rows, Exercises: []models.ImportedExercise{}, Unmapped: []models.ImportRowIssue{}}
+	for _, issue := range export.Skipped {
+		report.Unmapped = append(report.Unmapped, models.ImportRowIssue{Row: issue.Row, Exercise: issue.Exercise, Reason: issue.Reason})
+	}
+
+	// Collect each exercise name's sets in the order names first appear
+	var order []string
+	sets := map[string][]history.Set{}
+	for _, w := range export.Workouts {
+		for _, set := range w.Sets {
+			if _, seen := sets[set.Exercise]; !seen {
+				order = append(order, set.Exercise)
+			}
+			sets[set.Exercise] = append(sets[set.Exercise], set)
+		}
+	}
+	if len(order) == 0 {
+		return report, nil
+	}
+
+	names := make(map[string]string, len(order))
+	for _, name := range order {
+		names[name] = history.NormalizeName(name)
+	}
+	matches, err := s.exerciseRepo.MatchNames(ctx, viewer.UserID, names, importMatchThreshold)
+	if err != nil {
+		return models.HistoryImportReport{}, err
+	}
+
+	// mapped indexes report.Exercises by exported name; failed holds why a
+	// name could not be mapped
+	mapped := map[string]int{}
+	trackingTypes := map[string]string{}
+	failed := map[string]string{}
+	for _, name := range order {
+		imported := models.ImportedExercise{Name: name}
+		if m, ok := matches[name]; ok {
+			imported.ExerciseID, imported.ExerciseName, imported.Score = m.ExerciseID, m.ExerciseName, m.Score
+			trackingTypes[name] = m.TrackingType
+		} else {
+			exercise, reason, err := s.createExercise(ctx, viewer, name, sets[name])
+			if err != nil {
+				return models.HistoryImportReport{}, err
+			}
+			if reason != "" {
+				failed[name] = reason
+				continue
+			}
+			imported.ExerciseID, imported.ExerciseName, imported.Created = exercise.ID, exercise.Name, true
+			trackingTypes[name] = exercise.TrackingType
+		}
+		mapped[name] = len(report.Exercises)
+		report.Exercises = append(report.Exercises, imported)
+	}
+
+	for _, w := range export.Workouts {
+		if utf8.RuneCountInString(w.Name) > maxNameLength {
+			for _, set := range w.Sets {
+				report.Unmapped = append(report.Unmapped, models.ImportRowIssue{Row: set.Row, Exercise: set.Exercise,
+					Reason: fmt.Sprintf("workout name cannot be longer than %d characters", maxNameLength)})
+			}
+			continue
+		}
+		workout := models.Workout{
+			UserID:       viewer.UserID,
+			Name:         w.Name,
+			Notes:        w.Notes,
+			StartedAt:    w.StartedAt,
+			FinishedAt:   w.FinishedAt,
+			ImportSource: export.Source,
+			ImportKey:    w.Key,
+		}
+		// Imported sessions are over; without a recorded end they end when they start
+		if workout.FinishedAt == nil {
+			finished := w.StartedAt
+			workout.FinishedAt = &finished
+		}
+
+		numbers := map[int]int{}
+		var exerciseNames []string
+		for _, set := range w.Sets {
+			unmapped := func(reason string) {
+				report.Unmapped = append(report.Unmapped, models.ImportRowIssue{Row: set.Row, Exercise: set.Exercise, Reason: reason})
+			}
+			i, ok := mapped[set.Exercise]
+			if !ok {
+				unmapped(failed[set.Exercise])
+				continue
+			}
+			logged := models.WorkoutSet{
+				ExerciseID:      report.Exercises[i].ExerciseID,
+				Reps:            set.Reps,
+				Weight:          set.Weight,
+				RPE:             set.RPE,
+				RestSeconds:     set.RestSeconds,
+				DurationSeconds: set.DurationSeconds,
+				DistanceMeters:  set.DistanceMeters,
+			}
+			if reason := importedSetProblems(trackingTypes[set.Exercise], logged); reason != "" {
+				unmapped(reason)
+				continue
+			}
+			numbers[logged.ExerciseID]++
+			logged.SetNumber = numbers[logged.ExerciseID]
+			workout.Sets = append(workout.Sets, logged)
+			exerciseNames = append(exerciseNames, set.Exercise)
+		}
+		// Rows of a workout with no usable sets have all been reported
+		if len(workout.Sets) == 0 {
+			continue
+		}
+
+		_, created, err := s.workoutRepo.Import(ctx, workout)
+		if err != nil {
+			return models.HistoryImportReport{}, err
+		}
+		if !created {
+			report.WorkoutsSkipped++
+			continue
+		}
+		report.WorkoutsCreated++
+		report.SetsImported += len(workout.Sets)
+		for _, name := range exerciseNames {
+			report.Exercises[mapped[name]].Sets++
+		}
+	}
+	return report, nil
+}
+
+// createExercise adds a private exercise for an exported name that matched
+// nothing, tracked the way its sets were logged. A name that cannot become
+// an exercise is returned as a reason rather than an error.
+func (s *HistoryService) createExercise(ctx context.Context, viewer models.Viewer, name string, sets []history.Set) (models.Exercise, string, error) {
+	// Names without an equipment qualifier get the usual default
+	equipment := history.Equipment(name)
+	if equipment != "" {
+		slug, ok, err := s.exercises.resolveEquipment(ctx, equipment)
+		if err != nil {
+			return models.Exercise{}, "", err
+		}
+		equipment = slug
+		if !ok {
+			equipment = importEquipment
+		}
+	}
+
+	exercise, err := s.exercises.CreateExercise(ctx, viewer, models.Exercise{
+		Name:          name,
+		MuscleGroup:   importMuscleGroup,
+		EquipmentType: equipment,
+		TrackingType:  inferTrackingType(sets),
+		Visibility:    models.VisibilityPrivate,
+	})
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) && errors.Is(err, apperrors.ErrValidation) {
+		return models.Exercise{}, "could not create exercise: " + describeFieldErrors(appErr.Fields), nil
+	}
+	if err != nil {
+		return models.Exercise{}, "", err
+	}
+	return exercise, "", nil
+}
+
+// inferTrackingType picks the tracking type that fits every logged set of
+// an exercise created by an import
+func inferTrackingType(sets []history.Set) string {
+	hasReps, hasWeight, hasDistance, hasDuration := false, false, false, false
+	for _, set := range sets {
+		hasReps = hasReps || set.Reps > 0
+		hasWeight = hasWeight || set.Weight > 0
+		hasDistance = hasDistance || set.DistanceMeters != nil
+		hasDuration = hasDuration || set.DurationSeconds != nil
+	}
+	switch {
+	case hasDistance:
+		return models.TrackingDistanceDuration
+	case hasDuration && !hasReps:
+		return models.TrackingDuration
+	case !hasWeight:
+		return models.TrackingRepsOnly
+	}
+	return models.TrackingWeightReps
+}
+
+// importedSetProblems validates an imported set like a logged one and
+// describes what is wrong with it, or returns ""
+func importedSetProblems(trackingType string, set models.WorkoutSet) string {
+	var fields []apperrors.FieldError
+	var appErr *apperrors.Error
+	if err := validateSet(set); errors.As(err, &appErr) {
+		fields = append(fields, appErr.Fields...)
+	}
+	fields = append(fields, validateTrackedSet(trackingType, set)...)
+	return describeFieldErrors(fields)
+}
+
+func describeFieldErrors(fields []apperrors.FieldError) string {
+	messages := make([]string, 0, len(fields))
+	for _, f := range fields {
+		messages = append(messages, f.Message)
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"workout-api/internal/history"
+	"workout-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestHistoryService() (*HistoryService, *MockWorkoutRepository, *MockExerciseRepository) {
+	workoutRepo := new(MockWorkoutRepository)
+	exerciseRepo := new(MockExerciseRepository)
+	return NewHistoryService(workoutRepo, exerciseRepo, NewExerciseService(exerciseRepo, newTestTaxonomy())), workoutRepo, exerciseRepo
+}
+
+func TestHistoryService_ImportHistory(t *testing.T) {
+	ctx := context.Background()
+	service, workoutRepo, exerciseRepo := newTestHistoryService()
+	viewer := models.Viewer{UserID: 5, Role: models.RoleUser}
+	started := time.Date(2026, 3, 2, 7, 30, 0, 0, time.UTC)
+	rest := 120
+
+	export := history.Export{
+		Source: history.SourceStrong,
+		Rows:   6,
+		Workouts: []history.Workout{
+			{Key: "first", Name: "Push Day", StartedAt: started, Sets: []history.Set{
+				{Row: 2, Exercise: "Bench Press (Barbell)", Reps: 5, Weight: 100, RestSeconds: &rest},
+				{Row: 3, Exercise: "Bench Press (Barbell)", Reps: 5, Weight: 100},
+				{Row: 4, Exercise: "Zercher Squat (Barbell)", Reps: 8, Weight: 60},
+				{Row: 5, Exercise: "Burpee", Reps: 10, Weight: 10},
+			}},
+			{Key: "second", Name: "Push Day", StartedAt: started.AddDate(0, 0, 2), Sets: []history.Set{
+				{Row: 6, Exercise: "Bench Press (Barbell)", Reps: 5, Weight: 102.5},
+			}},
+		},
+		Skipped: []history.Issue{{Row: 1, Exercise: "Bench Press (Barbell)", Reason: "warm-up sets are not imported"}},
+	}
+
+	exerciseRepo.On("MatchNames", ctx, 5, map[string]string{
+		"Bench Press (Barbell)":   "barbell bench press",
+		"Zercher Squat (Barbell)": "barbell zercher squat",
+		"Burpee":                  "burpee",
+	}, importMatchThreshold).Return(map[string]models.ExerciseMatch{
+		"Bench Press (Barbell)": {ExerciseID: 1, ExerciseName: "Barbell Bench Press", TrackingType: models.TrackingWeightReps, Score: 1},
+		"Burpee":                {ExerciseID: 55, ExerciseName: "Burpee", TrackingType: models.TrackingRepsOnly, Score: 1},
+	}, nil)
+	exerciseRepo.On("Create", ctx, mock.MatchedBy(func(e models.Exercise) bool {
+		return e.Name == "Zercher Squat (Barbell)" && e.MuscleGroup == importMuscleGroup && e.EquipmentType == "barbell" &&
+			e.TrackingType == models.TrackingWeightReps && e.Visibility == models.VisibilityPrivate && *e.OwnerID == 5
+	})).Return(models.Exercise{ID: 90, Name: "Zercher Squat (Barbell)", TrackingType: models.TrackingWeightReps}, nil)
+
+	workoutRepo.On("Import", ctx, mock.MatchedBy(func(w models.Workout) bool { return w.ImportKey == "first" })).
+		Run(func(args mock.Arguments) {
+			w := args.Get(1).(models.Workout)
+			assert.Equal(t, history.SourceStrong, w.ImportSource)
+			assert.Equal(t, started, *w.FinishedAt)
+			assert.Len(t, w.Sets, 3)
+			assert.Equal(t, []int{1, 2, 1}, []int{w.Sets[0].SetNumber, w.Sets[1].SetNumber, w.Sets[2].SetNumber})
+			assert.Equal(t, &rest, w.Sets[0].RestSeconds)
+			assert.Equal(t, 90, w.Sets[2].ExerciseID)
+		}).Return(models.Workout{ID: 7}, true, nil)
+	workoutRepo.On("Import", ctx, mock.MatchedBy(func(w models.Workout) bool { return w.ImportKey == "second" })).
+		Return(models.Workout{}, false, nil)
+
+	report, err := service.ImportHistory(ctx, viewer, export)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.WorkoutsCreated)
+	assert.Equal(t, 1, report.WorkoutsSkipped)
+	assert.Equal(t, 3, report.SetsImported)
+	assert.Equal(t, []models.ImportedExercise{
+		{Name: "Bench Press (Barbell)", ExerciseID: 1, ExerciseName: "Barbell Bench Press", Score: 1, Sets: 2},
+		{Name: "Zercher Squat (Barbell)", ExerciseID: 90, ExerciseName: "Zercher Squat (Barbell)", Created: true, Sets: 1},
+		{Name: "Burpee", ExerciseID: 55, ExerciseName: "Burpee", Score: 1},
+	}, report.Exercises)
+	assert.Equal(t, []models.ImportRowIssue{
+		{Row: 1, Exercise: "Bench Press (Barbell)", Reason: "warm-up sets are not imported"},
+		{Row: 5, Exercise: "Burpee", Reason: "weight is not tracked for this exercise"},
+	}, report.Unmapped)
+	workoutRepo.AssertExpectations(t)
+	exerciseRepo.AssertExpectations(t)
+}
+
+func TestHistoryService_ImportHistory_ReportsOversizedRows(t *testing.T) {
+	ctx := context.Background()
+	service, workoutRepo, exerciseRepo := newTestHistoryService()
+	viewer := models.Viewer{UserID: 5, Role: models.RoleUser}
+	started := time.Date(2026, 3, 2, 7, 30, 0, 0, time.UTC)
+	longName := strings.Repeat("x", maxNameLength+1)
+
+	export := history.Export{
+		Source: history.SourceStrong,
+		Rows:   4,
+		Workouts: []history.Workout{
+			{Key: "first", Name: "Push Day", StartedAt: started, Sets: []history.Set{
+				{Row: 1, Exercise: "Bench Press (Barbell)", Reps: 5, Weight: 100},
+				{Row: 2, Exercise: "Bench Press (Barbell)", Reps: 5, Weight: 100000},
+				{Row: 3, Exercise: longName, Reps: 5, Weight: 20},
+			}},
+			{Key: "second", Name: longName, StartedAt: started.AddDate(0, 0, 2), Sets: []history.Set{
+				{Row: 4, Exercise: "Bench Press (Barbell)", Reps: 5, Weight: 102.5},
+			}},
+		},
+	}
+
+	exerciseRepo.On("MatchNames", ctx, 5, mock.Anything, importMatchThreshold).Return(map[string]models.ExerciseMatch{
+		"Bench Press (Barbell)": {ExerciseID: 1, ExerciseName: "Barbell Bench Press", TrackingType: models.TrackingWeightReps, Score: 1},
+	}, nil)
+	workoutRepo.On("Import", ctx, mock.MatchedBy(func(w models.Workout) bool { return w.ImportKey == "first" && len(w.Sets) == 1 })).
+		Return(models.Workout{ID: 7}, true, nil)
+
+	report, err := service.ImportHistory(ctx, viewer, export)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.WorkoutsCreated)
+	assert.Equal(t, 1, report.SetsImported)
+	rows := make([]int, 0, len(report.Unmapped))
+	for _, issue := range report.Unmapped {
+		rows = append(rows, issue.Row)
+	}
+	assert.Equal(t, []int{2, 3, 4}, rows)
+	assert.Contains(t, report.Unmapped[1].Reason, "exercise name cannot be longer than 255 characters")
+	assert.Equal(t, "workout name cannot be longer than 255 characters", report.Unmapped[2].Reason)
+	exerciseRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	workoutRepo.AssertExpectations(t)
+}
+
+func TestHistoryService_ImportHistory_Empty(t *testing.T) {
+	service, _, exerciseRepo := newTestHistoryService()
+
+	report, err := service.ImportHistory(context.Background(), models.Viewer{UserID: 5}, history.Export{Source: history.SourceHevy})
+	assert.NoError(t, err)
+	assert.Equal(t, history.SourceHevy, report.Source)
+	assert.Empty(t, report.Exercises)
+	exerciseRepo.AssertNotCalled(t, "MatchNames", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestInferTrackingType(t *testing.T) {
+	seconds, meters := 60, 400.0
+	assert.Equal(t, models.TrackingWeightReps, inferTrackingType([]history.Set{{Reps: 5, Weight: 20}, {Reps: 8}}))
+	assert.Equal(t, models.TrackingRepsOnly, inferTrackingType([]history.Set{{Reps: 12}}))
+	assert.Equal(t, models.TrackingDuration, inferTrackingType([]history.Set{{DurationSeconds: &seconds}}))
+	assert.Equal(t, models.TrackingDistanceDuration, inferTrackingType([]history.Set{{DistanceMeters: &meters, DurationSeconds: &seconds}}))
+}
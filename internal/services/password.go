@@ -0,0 +1,73 @@
+package services
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"workout-api/internal/apperrors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DefaultPasswordCost is the bcrypt work factor used when none is configured
+const DefaultPasswordCost = bcrypt.DefaultCost
+
+// PasswordHasher hashes and verifies user passwords with bcrypt.
+// Hashes produced with a different cost than the configured one are
+// reported by NeedsRehash so they can be upgraded on the next login.
+type PasswordHasher struct {
+	cost int
+}
+
+func NewPasswordHasher(cost int) *PasswordHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = DefaultPasswordCost
+	}
+	return &PasswordHasher{cost: cost}
+}
+
+// Hash rejects passwords longer than the 72 bytes bcrypt can take
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		return "", apperrors.Invalid("password", "password must be at most 72 bytes")
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Verify reports whether password matches the stored hash. Rows written
+// before hashing was introduced still hold plaintext and are compared in
+// constant time so they keep working until they are rehashed.
+func (h *PasswordHasher) Verify(hash, password string) (bool, error) {
+	if !isBcryptHash(hash) {
+		return subtle.ConstantTimeCompare([]byte(hash), []byte(password)) == 1, nil
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// NeedsRehash reports whether the stored value should be replaced with a
+// fresh hash at the current cost
+func (h *PasswordHasher) NeedsRehash(hash string) bool {
+	if !isBcryptHash(hash) {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.cost
+}
+
+func isBcryptHash(value string) bool {
+	return len(value) == 60 && (strings.HasPrefix(value, "$2a$") ||
+		strings.HasPrefix(value, "$2b$") ||
+		strings.HasPrefix(value, "$2y$"))
+}
@@ -0,0 +1,590 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+	"workout-api/internal/repository"
+)
+
+// Limits on program size and prescriptions
+const (
+	maxProgramWeeks    = 52
+	maxDaysPerWeek     = 7
+	maxPercentTM       = 150
+	maxWeightRounding  = 50
+	defaultWeightRound = 2.5
+)
+
+type ProgramService struct {
+	repo           repository.ProgramRepositoryInterface
+	enrollmentRepo repository.EnrollmentRepositoryInterface
+	routineRepo    repository.RoutineRepositoryInterface
+	exerciseRepo   repository.ExerciseRepositoryInterface
+	workoutRepo    repository.WorkoutRepositoryInterface
+}
+
+func NewProgramService(repo repository.ProgramRepositoryInterface, enrollmentRepo repository.EnrollmentRepositoryInterface, routineRepo repository.RoutineRepositoryInterface, exerciseRepo repository.ExerciseRepositoryInterface, workoutRepo repository.WorkoutRepositoryInterface) *ProgramService {
+	return &ProgramService{repo: repo, enrollmentRepo: enrollmentRepo, routineRepo: routineRepo, exerciseRepo: exerciseRepo, workoutRepo: workoutRepo}
+}
+
+// CreateProgram saves a new program owned by the viewer. Its days must use
+// the viewer's own routines.
+func (s *ProgramService) CreateProgram(ctx context.Context, viewer models.Viewer, program models.Program) (models.Program, error) {
+	program.OwnerID = viewer.UserID
+	program, err := s.validate(ctx, program)
+	if err != nil {
+		return models.Program{}, err
+	}
+	return s.repo.Create(ctx, program)
+}
+
+// GetProgram returns a program the viewer may see
+func (s *ProgramService) GetProgram(ctx context.Context, viewer models.Viewer, id int) (models.Program, error) {
+	if id <= 0 {
+		return models.Program{}, apperrors.Invalid("id", "invalid program ID")
+	}
+
+	program, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return models.Program{}, err
+	}
+	// Hidden programs are indistinguishable from missing ones
+	if !program.VisibleTo(viewer) {
+		return models.Program{}, apperrors.NotFound("program", id)
+	}
+	return program, nil
+}
+
+// ListPrograms returns one page of the programs the viewer may see
+func (s *ProgramService) ListPrograms(ctx context.Context, viewer models.Viewer, filter models.ProgramFilter, query pagination.Query) (pagination.Page[models.Program], error) {
+	params, err := repository.ProgramListSpec.Parse(query)
+	if err != nil {
+		return pagination.Page[models.Program]{}, err
+	}
+	return s.repo.List(ctx, viewer, filter, params)
+}
+
+// UpdateProgram replaces a program the viewer may edit, days and
+// progression rules included. Enrolled users keep their position.
+func (s *ProgramService) UpdateProgram(ctx context.Context, viewer models.Viewer, program models.Program) error {
+	existing, err := s.editable(ctx, viewer, program.ID)
+	if err != nil {
+		return err
+	}
+	program.OwnerID = existing.OwnerID
+	program, err = s.validate(ctx, program)
+	if err != nil {
+		return err
+	}
+	return s.repo.Update(ctx, program)
+}
+
+// DeleteProgram removes a program the viewer may edit, ending its enrollments
+func (s *ProgramService) DeleteProgram(ctx context.Context, viewer models.Viewer, id int) error {
+	if _, err := s.editable(ctx, viewer, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *ProgramService) editable(ctx context.Context, viewer models.Viewer, id int) (models.Program, error) {
+	program, err := s.GetProgram(ctx, viewer, id)
+	if err != nil {
+		return models.Program{}, err
+	}
+	if !program.EditableBy(viewer) {
+		return models.Program{}, apperrors.Forbidden("only the program's owner can change it")
+	}
+	return program, nil
+}
+
+// Enroll starts the viewer on the first day of a program. A training max is
+// required for every exercise the program prescribes by percentage.
+func (s *ProgramService) Enroll(ctx context.Context, viewer models.Viewer, programID int, maxes []models.TrainingMax) (models.Enrollment, error) {
+	program, err := s.GetProgram(ctx, viewer, programID)
+	if err != nil {
+		return models.Enrollment{}, err
+	}
+	if len(program.Days) == 0 {
+		return models.Enrollment{}, apperrors.Conflict("program has no training days")
+	}
+	if err := validateTrainingMaxes(program, maxes); err != nil {
+		return models.Enrollment{}, err
+	}
+
+	first := program.Days[0]
+	return s.enrollmentRepo.Create(ctx, models.Enrollment{
+		UserID:        viewer.UserID,
+		ProgramID:     program.ID,
+		Week:          first.Week,
+		Day:           first.Day,
+		Cycle:         1,
+		Status:        models.EnrollmentActive,
+		TrainingMaxes: maxes,
+	})
+}
+
+// CurrentEnrollment returns the user's active enrollment
+func (s *ProgramService) CurrentEnrollment(ctx context.Context, userID int) (models.Enrollment, error) {
+	return s.enrollmentRepo.GetActive(ctx, userID)
+}
+
+// LeaveProgram cancels the user's active enrollment
+func (s *ProgramService) LeaveProgram(ctx context.Context, userID int) error {
+	enrollment, err := s.enrollmentRepo.GetActive(ctx, userID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	enrollment.Status = models.EnrollmentCancelled
+	enrollment.EndedAt = &now
+	return s.enrollmentRepo.Update(ctx, enrollment)
+}
+
+// Today returns the session the user is due to do next in their program
+func (s *ProgramService) Today(ctx context.Context, userID int) (models.ProgramSession, error) {
+	session, _, err := s.today(ctx, userID)
+	return session, err
+}
+
+// StartToday opens a workout for the user's next program session with its
+// planned sets, and links it to the enrollment until the user advances
+func (s *ProgramService) StartToday(ctx context.Context, userID int, startedAt time.Time) (models.Workout, error) {
+	session, enrollment, err := s.today(ctx, userID)
+	if err != nil {
+		return models.Workout{}, err
+	}
+	if enrollment.WorkoutID != nil {
+		return models.Workout{}, apperrors.Conflict("today's workout has already been started")
+	}
+
+	if startedAt.IsZero() {
+		startedAt = time.Now()
+	}
+	workout, err := s.workoutRepo.Create(ctx, models.Workout{
+		UserID:      userID,
+		Name:        session.Name,
+		StartedAt:   startedAt,
+		PlannedSets: session.PlannedSets,
+	})
+	if err != nil {
+		return models.Workout{}, err
+	}
+
+	enrollment.WorkoutID = &workout.ID
+	if err := s.enrollmentRepo.Update(ctx, enrollment); err != nil {
+		return models.Workout{}, err
+	}
+	return workout, nil
+}
+
+// Advance moves the user's enrollment past the current session and applies
+// the program's progression rules. A started workout must be finished
+// first; advancing without one skips the session. A program that does not
+// repeat is completed after its last day.
+func (s *ProgramService) Advance(ctx context.Context, userID int) (models.Enrollment, error) {
+	enrollment, err := s.enrollmentRepo.GetActive(ctx, userID)
+	if err != nil {
+		return models.Enrollment{}, err
+	}
+	program, err := s.repo.GetById(ctx, enrollment.ProgramID)
+	if err != nil {
+		return models.Enrollment{}, err
+	}
+
+	completed, err := s.completedExercises(ctx, enrollment.WorkoutID)
+	if err != nil {
+		return models.Enrollment{}, err
+	}
+
+	idx := currentDay(program, enrollment)
+	var newWeek, newCycle bool
+	switch {
+	case idx+1 < len(program.Days):
+		next := program.Days[idx+1]
+		newWeek = next.Week != program.Days[idx].Week
+		enrollment.Week, enrollment.Day = next.Week, next.Day
+	case program.Repeats && idx >= 0:
+		first := program.Days[0]
+		newWeek, newCycle = true, true
+		enrollment.Week, enrollment.Day = first.Week, first.Day
+		enrollment.Cycle++
+	default:
+		now := time.Now()
+		enrollment.Status = models.EnrollmentCompleted
+		enrollment.EndedAt = &now
+	}
+
+	for _, rule := range program.Progressions {
+		fire := rule.Trigger == models.ProgressOnSession && completed[rule.ExerciseID] ||
+			rule.Trigger == models.ProgressOnWeek && newWeek ||
+			rule.Trigger == models.ProgressOnCycle && newCycle
+		if !fire {
+			continue
+		}
+		for i := range enrollment.TrainingMaxes {
+			if enrollment.TrainingMaxes[i].ExerciseID == rule.ExerciseID {
+				enrollment.TrainingMaxes[i].Weight += rule.Increment
+			}
+		}
+	}
+
+	enrollment.WorkoutID = nil
+	if err := s.enrollmentRepo.Update(ctx, enrollment); err != nil {
+		return models.Enrollment{}, err
+	}
+	return enrollment, nil
+}
+
+// today resolves the user's next session and plans its sets
+func (s *ProgramService) today(ctx context.Context, userID int) (models.ProgramSession, models.Enrollment, error) {
+	enrollment, err := s.enrollmentRepo.GetActive(ctx, userID)
+	if err != nil {
+		return models.ProgramSession{}, models.Enrollment{}, err
+	}
+	program, err := s.repo.GetById(ctx, enrollment.ProgramID)
+	if err != nil {
+		return models.ProgramSession{}, models.Enrollment{}, err
+	}
+	idx := currentDay(program, enrollment)
+	if idx < 0 {
+		return models.ProgramSession{}, models.Enrollment{}, apperrors.Conflict("program has no training days")
+	}
+	day := program.Days[idx]
+
+	routine, err := s.routineRepo.GetById(ctx, day.RoutineID)
+	if err != nil {
+		return models.ProgramSession{}, models.Enrollment{}, err
+	}
+
+	viewer := models.Viewer{UserID: userID}
+	var (
+		exercises []models.RoutineExercise
+		ids       []int
+	)
+	for _, e := range routine.Exercises {
+		exercise, err := s.exerciseRepo.GetById(ctx, e.ExerciseID)
+		if errors.Is(err, apperrors.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return models.ProgramSession{}, models.Enrollment{}, err
+		}
+		if !exercise.VisibleTo(viewer) {
+			continue
+		}
+		exercises = append(exercises, e)
+		ids = append(ids, e.ExerciseID)
+	}
+
+	last, err := s.workoutRepo.LastPerformance(ctx, userID, ids)
+	if err != nil {
+		return models.ProgramSession{}, models.Enrollment{}, err
+	}
+
+	name := day.Name
+	if name == "" {
+		name = fmt.Sprintf("%s: week %d, day %d", program.Name, day.Week, day.Day)
+	}
+	session := models.ProgramSession{
+		EnrollmentID: enrollment.ID,
+		ProgramID:    program.ID,
+		ProgramName:  program.Name,
+		Week:         day.Week,
+		Day:          day.Day,
+		Cycle:        enrollment.Cycle,
+		Name:         name,
+		RoutineID:    day.RoutineID,
+		WorkoutID:    enrollment.WorkoutID,
+		PlannedSets:  planProgramSets(day, exercises, enrollment.TrainingMaxes, last, program.Rounding),
+	}
+	return session, enrollment, nil
+}
+
+// completedExercises reports, for a finished workout, which exercises had
+// every planned set done at the planned reps and weight or better
+func (s *ProgramService) completedExercises(ctx context.Context, workoutID *int) (map[int]bool, error) {
+	completed := map[int]bool{}
+	if workoutID == nil {
+		return completed, nil
+	}
+
+	workout, err := s.workoutRepo.GetById(ctx, *workoutID)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return completed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if workout.FinishedAt == nil {
+		return nil, apperrors.Conflict("finish today's workout before advancing")
+	}
+
+	planned, err := s.workoutRepo.GetPlannedSets(ctx, workout.ID)
+	if err != nil {
+		return nil, err
+	}
+	logged, err := s.workoutRepo.GetSets(ctx, workout.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	done := map[int][]models.WorkoutSet{}
+	for _, set := range logged {
+		done[set.ExerciseID] = append(done[set.ExerciseID], set)
+	}
+	for _, set := range done {
+		sort.Slice(set, func(i, j int) bool { return set[i].SetNumber < set[j].SetNumber })
+	}
+
+	// planned sets arrive in exercise position and set order
+	count := map[int]int{}
+	for _, p := range planned {
+		n := count[p.ExerciseID]
+		count[p.ExerciseID]++
+		if n == 0 {
+			completed[p.ExerciseID] = true
+		}
+		sets := done[p.ExerciseID]
+		if n >= len(sets) || sets[n].Reps < p.Reps || sets[n].Weight < p.Weight {
+			completed[p.ExerciseID] = false
+		}
+	}
+	return completed, nil
+}
+
+// currentDay returns the index of the enrollment's day in the program, or
+// of the next day after it when the program was edited to remove it. An
+// enrollment beyond the last day stays on the last day; -1 means the
+// program has no days.
+func currentDay(program models.Program, enrollment models.Enrollment) int {
+	for i, d := range program.Days {
+		if d.Week > enrollment.Week || d.Week == enrollment.Week && d.Day >= enrollment.Day {
+			return i
+		}
+	}
+	return len(program.Days) - 1
+}
+
+// planProgramSets plans a program day. Exercises with prescriptions and a
+// training max get one set per prescription, its weight a percentage of
+// the training max rounded to the program's increment; the rest are
+// planned like a routine.
+func planProgramSets(day models.ProgramDay, exercises []models.RoutineExercise, maxes []models.TrainingMax, last map[int][]models.WorkoutSet, rounding float64) []models.PlannedSet {
+	tms := map[int]float64{}
+	for _, tm := range maxes {
+		tms[tm.ExerciseID] = tm.Weight
+	}
+	prescribed := map[int][]models.Prescription{}
+	for _, rx := range day.Prescriptions {
+		prescribed[rx.ExerciseID] = append(prescribed[rx.ExerciseID], rx)
+	}
+
+	var planned []models.PlannedSet
+	for _, e := range exercises {
+		tm, ok := tms[e.ExerciseID]
+		rxs := prescribed[e.ExerciseID]
+		if !ok || len(rxs) == 0 {
+			planned = append(planned, planSets([]models.RoutineExercise{e}, last)...)
+			continue
+		}
+		for _, rx := range rxs {
+			percent := rx.PercentTM
+			planned = append(planned, models.PlannedSet{
+				ExerciseID:  e.ExerciseID,
+				Position:    e.Position,
+				SetNumber:   rx.SetNumber,
+				RepMin:      rx.Reps,
+				RepMax:      rx.Reps,
+				Reps:        rx.Reps,
+				Weight:      roundWeight(tm*percent/100, rounding),
+				RPE:         e.TargetRPE,
+				RestSeconds: e.RestSeconds,
+				PercentTM:   &percent,
+				AMRAP:       rx.AMRAP,
+				Source:      models.PlannedFromProgram,
+			})
+		}
+	}
+	return planned
+}
+
+// roundWeight rounds to the nearest multiple of step, trimming float noise
+// to two decimals
+func roundWeight(weight, step float64) float64 {
+	if step <= 0 {
+		step = defaultWeightRound
+	}
+	return math.Round(math.Round(weight/step)*step*100) / 100
+}
+
+// validate checks the program, defaulting its visibility and rounding and
+// numbering each exercise's prescriptions from 1. Days are sorted into
+// week and day order and must use routines owned by the program's owner.
+func (s *ProgramService) validate(ctx context.Context, program models.Program) (models.Program, error) {
+	var fields []apperrors.FieldError
+	program.Name = strings.TrimSpace(program.Name)
+	if program.Name == "" {
+		fields = append(fields, apperrors.FieldError{Field: "name", Message: "program name is required"})
+	}
+	if program.Visibility == "" {
+		program.Visibility = models.VisibilityPrivate
+	}
+	if program.Visibility != models.VisibilityPrivate && program.Visibility != models.VisibilityShared {
+		fields = append(fields, apperrors.FieldError{Field: "visibility", Message: "visibility must be private or shared"})
+	}
+	if program.Rounding == 0 {
+		program.Rounding = defaultWeightRound
+	}
+	if program.Rounding < 0 || program.Rounding > maxWeightRounding {
+		fields = append(fields, apperrors.FieldError{Field: "rounding", Message: fmt.Sprintf("rounding must be between 0 and %d", maxWeightRounding)})
+	}
+	if len(program.Days) == 0 {
+		fields = append(fields, apperrors.FieldError{Field: "days", Message: "a program needs at least one day"})
+	}
+
+	routines := map[int]*models.Routine{}
+	positions := map[[2]int]bool{}
+	for i := range program.Days {
+		d := &program.Days[i]
+		field := func(name string) string { return fmt.Sprintf("days[%d].%s", i, name) }
+
+		d.Name = strings.TrimSpace(d.Name)
+		if d.Week <= 0 || d.Week > maxProgramWeeks {
+			fields = append(fields, apperrors.FieldError{Field: field("week"), Message: fmt.Sprintf("week must be between 1 and %d", maxProgramWeeks)})
+		}
+		if d.Day <= 0 || d.Day > maxDaysPerWeek {
+			fields = append(fields, apperrors.FieldError{Field: field("day"), Message: fmt.Sprintf("day must be between 1 and %d", maxDaysPerWeek)})
+		}
+		if positions[[2]int{d.Week, d.Day}] {
+			fields = append(fields, apperrors.FieldError{Field: field("day"), Message: "day is already scheduled in this week"})
+		}
+		positions[[2]int{d.Week, d.Day}] = true
+
+		routine, seen := routines[d.RoutineID]
+		if !seen && d.RoutineID > 0 {
+			r, err := s.routineRepo.GetById(ctx, d.RoutineID)
+			if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+				return models.Program{}, err
+			}
+			if err == nil && r.UserID == program.OwnerID {
+				routine = &r
+			}
+			routines[d.RoutineID] = routine
+		}
+		if routine == nil {
+			fields = append(fields, apperrors.FieldError{Field: field("routine_id"), Message: "routine not found"})
+		}
+
+		setNumbers := map[int]int{}
+		for j := range d.Prescriptions {
+			rx := &d.Prescriptions[j]
+			rxField := func(name string) string { return fmt.Sprintf("days[%d].prescriptions[%d].%s", i, j, name) }
+
+			setNumbers[rx.ExerciseID]++
+			rx.SetNumber = setNumbers[rx.ExerciseID]
+			if rx.SetNumber == maxTargetSets+1 {
+				fields = append(fields, apperrors.FieldError{Field: rxField("exercise_id"), Message: fmt.Sprintf("an exercise can have at most %d prescribed sets", maxTargetSets)})
+			}
+			if routine != nil && !routineHasExercise(*routine, rx.ExerciseID) {
+				fields = append(fields, apperrors.FieldError{Field: rxField("exercise_id"), Message: "exercise is not part of the day's routine"})
+			}
+			if rx.PercentTM <= 0 || rx.PercentTM > maxPercentTM {
+				fields = append(fields, apperrors.FieldError{Field: rxField("percent_tm"), Message: fmt.Sprintf("percentage of training max must be between 0 and %d", maxPercentTM)})
+			}
+			if rx.Reps <= 0 {
+				fields = append(fields, apperrors.FieldError{Field: rxField("reps"), Message: "reps must be greater than zero"})
+			}
+		}
+	}
+
+	viewer := models.Viewer{UserID: program.OwnerID}
+	ruled := map[int]bool{}
+	for i, rule := range program.Progressions {
+		field := func(name string) string { return fmt.Sprintf("progressions[%d].%s", i, name) }
+
+		if rule.Increment <= 0 {
+			fields = append(fields, apperrors.FieldError{Field: field("increment"), Message: "increment must be greater than zero"})
+		}
+		switch rule.Trigger {
+		case models.ProgressOnSession, models.ProgressOnWeek, models.ProgressOnCycle:
+		default:
+			fields = append(fields, apperrors.FieldError{Field: field("trigger"), Message: "trigger must be session, week or cycle"})
+		}
+		if ruled[rule.ExerciseID] {
+			fields = append(fields, apperrors.FieldError{Field: field("exercise_id"), Message: "exercise already has a progression rule"})
+			continue
+		}
+		ruled[rule.ExerciseID] = true
+
+		exercise, err := s.exerciseRepo.GetById(ctx, rule.ExerciseID)
+		if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+			return models.Program{}, err
+		}
+		if err != nil || !exercise.VisibleTo(viewer) {
+			fields = append(fields, apperrors.FieldError{Field: field("exercise_id"), Message: "exercise not found"})
+		}
+	}
+
+	if len(fields) > 0 {
+		return models.Program{}, apperrors.Validation(fields...)
+	}
+
+	sort.SliceStable(program.Days, func(i, j int) bool {
+		a, b := program.Days[i], program.Days[j]
+		return a.Week < b.Week || a.Week == b.Week && a.Day < b.Day
+	})
+	return program, nil
+}
+
+func routineHasExercise(routine models.Routine, exerciseID int) bool {
+	for _, e := range routine.Exercises {
+		if e.ExerciseID == exerciseID {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTrainingMaxes requires exactly one positive training max for each
+// exercise the program prescribes by percentage
+func validateTrainingMaxes(program models.Program, maxes []models.TrainingMax) error {
+	needed := map[int]bool{}
+	for _, id := range program.PercentExercises() {
+		needed[id] = true
+	}
+
+	var fields []apperrors.FieldError
+	given := map[int]bool{}
+	for i, tm := range maxes {
+		field := func(name string) string { return fmt.Sprintf("training_maxes[%d].%s", i, name) }
+		switch {
+		case !needed[tm.ExerciseID]:
+			fields = append(fields, apperrors.FieldError{Field: field("exercise_id"), Message: "the program does not prescribe this exercise by percentage"})
+		case given[tm.ExerciseID]:
+			fields = append(fields, apperrors.FieldError{Field: field("exercise_id"), Message: "duplicate training max"})
+		}
+		given[tm.ExerciseID] = true
+		if tm.Weight <= 0 {
+			fields = append(fields, apperrors.FieldError{Field: field("weight"), Message: "training max must be greater than zero"})
+		}
+	}
+	for _, id := range program.PercentExercises() {
+		if !given[id] {
+			fields = append(fields, apperrors.FieldError{Field: "training_maxes", Message: fmt.Sprintf("training max required for exercise %d", id)})
+		}
+	}
+
+	if len(fields) > 0 {
+		return apperrors.Validation(fields...)
+	}
+	return nil
+}
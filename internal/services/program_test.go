@@ -0,0 +1,398 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+	"workout-api/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock ProgramRepository that implements repository.ProgramRepositoryInterface
+type MockProgramRepository struct {
+	mock.Mock
+}
+
+func (m *MockProgramRepository) Create(ctx context.Context, program models.Program) (models.Program, error) {
+	args := m.Called(ctx, program)
+	return args.Get(0).(models.Program), args.Error(1)
+}
+
+func (m *MockProgramRepository) GetById(ctx context.Context, id int) (models.Program, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.Program), args.Error(1)
+}
+
+func (m *MockProgramRepository) List(ctx context.Context, viewer models.Viewer, filter models.ProgramFilter, params pagination.Params) (pagination.Page[models.Program], error) {
+	args := m.Called(ctx, viewer, filter, params)
+	return args.Get(0).(pagination.Page[models.Program]), args.Error(1)
+}
+
+func (m *MockProgramRepository) Update(ctx context.Context, program models.Program) error {
+	args := m.Called(ctx, program)
+	return args.Error(0)
+}
+
+func (m *MockProgramRepository) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// Mock EnrollmentRepository that implements repository.EnrollmentRepositoryInterface
+type MockEnrollmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockEnrollmentRepository) Create(ctx context.Context, enrollment models.Enrollment) (models.Enrollment, error) {
+	args := m.Called(ctx, enrollment)
+	return args.Get(0).(models.Enrollment), args.Error(1)
+}
+
+func (m *MockEnrollmentRepository) GetActive(ctx context.Context, userID int) (models.Enrollment, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(models.Enrollment), args.Error(1)
+}
+
+func (m *MockEnrollmentRepository) Update(ctx context.Context, enrollment models.Enrollment) error {
+	args := m.Called(ctx, enrollment)
+	return args.Error(0)
+}
+
+var (
+	_ repository.ProgramRepositoryInterface    = (*MockProgramRepository)(nil)
+	_ repository.EnrollmentRepositoryInterface = (*MockEnrollmentRepository)(nil)
+)
+
+type programMocks struct {
+	programs    *MockProgramRepository
+	enrollments *MockEnrollmentRepository
+	routines    *MockRoutineRepository
+	exercises   *MockExerciseRepository
+	workouts    *MockWorkoutRepository
+}
+
+func newTestProgramService() (*ProgramService, programMocks) {
+	m := programMocks{
+		programs:    new(MockProgramRepository),
+		enrollments: new(MockEnrollmentRepository),
+		routines:    new(MockRoutineRepository),
+		exercises:   new(MockExerciseRepository),
+		workouts:    new(MockWorkoutRepository),
+	}
+	return NewProgramService(m.programs, m.enrollments, m.routines, m.exercises, m.workouts), m
+}
+
+// testProgram is a repeating two-week block: squat by percentage on day 1
+// of each week, bench from its routine on day 2 of week 1
+func testProgram() models.Program {
+	return models.Program{
+		ID:         2,
+		OwnerID:    1,
+		Name:       "Block",
+		Visibility: models.VisibilityShared,
+		Repeats:    true,
+		Rounding:   2.5,
+		Days: []models.ProgramDay{
+			{Week: 1, Day: 1, Name: "Squat", RoutineID: 4, Prescriptions: []models.Prescription{
+				{ExerciseID: 3, SetNumber: 1, PercentTM: 65, Reps: 5},
+				{ExerciseID: 3, SetNumber: 2, PercentTM: 75, Reps: 5},
+				{ExerciseID: 3, SetNumber: 3, PercentTM: 85, Reps: 5, AMRAP: true},
+			}},
+			{Week: 1, Day: 2, RoutineID: 5},
+			{Week: 2, Day: 1, Name: "Squat", RoutineID: 4, Prescriptions: []models.Prescription{
+				{ExerciseID: 3, SetNumber: 1, PercentTM: 90, Reps: 3},
+			}},
+		},
+		Progressions: []models.ProgressionRule{
+			{ExerciseID: 3, Increment: 5, Trigger: models.ProgressOnCycle},
+			{ExerciseID: 6, Increment: 2.5, Trigger: models.ProgressOnSession},
+		},
+	}
+}
+
+func TestProgramService_CreateProgram(t *testing.T) {
+	ctx := context.Background()
+	service, m := newTestProgramService()
+
+	m.routines.On("GetById", mock.Anything, 4).Return(models.Routine{ID: 4, UserID: 1, Exercises: []models.RoutineExercise{{ExerciseID: 3}}}, nil)
+	m.exercises.On("GetById", mock.Anything, 3).Return(models.Exercise{ID: 3, Visibility: models.VisibilityGlobal}, nil)
+	m.programs.On("Create", mock.Anything, mock.MatchedBy(func(p models.Program) bool {
+		// Days are sorted, prescriptions numbered per exercise and defaults applied
+		return p.OwnerID == 1 && p.Visibility == models.VisibilityPrivate && p.Rounding == 2.5 &&
+			p.Days[0].Week == 1 && p.Days[1].Week == 2 && p.Days[1].Prescriptions[1].SetNumber == 2
+	})).Return(models.Program{ID: 2}, nil)
+
+	program, err := service.CreateProgram(ctx, models.Viewer{UserID: 1}, models.Program{
+		Name: "Block",
+		Days: []models.ProgramDay{
+			{Week: 2, Day: 1, RoutineID: 4, Prescriptions: []models.Prescription{
+				{ExerciseID: 3, PercentTM: 70, Reps: 5},
+				{ExerciseID: 3, PercentTM: 80, Reps: 3},
+			}},
+			{Week: 1, Day: 1, RoutineID: 4},
+		},
+		Progressions: []models.ProgressionRule{{ExerciseID: 3, Increment: 5, Trigger: models.ProgressOnWeek}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, program.ID)
+	m.programs.AssertExpectations(t)
+}
+
+func TestProgramService_CreateProgram_Invalid(t *testing.T) {
+	ctx := context.Background()
+	service, m := newTestProgramService()
+
+	m.routines.On("GetById", mock.Anything, 4).Return(models.Routine{ID: 4, UserID: 1, Exercises: []models.RoutineExercise{{ExerciseID: 3}}}, nil)
+	m.routines.On("GetById", mock.Anything, 8).Return(models.Routine{ID: 8, UserID: 2}, nil)
+	m.exercises.On("GetById", mock.Anything, 3).Return(models.Exercise{ID: 3, Visibility: models.VisibilityGlobal}, nil)
+
+	_, err := service.CreateProgram(ctx, models.Viewer{UserID: 1}, models.Program{
+		Name:       "Block",
+		Visibility: models.VisibilityGlobal,
+		Days: []models.ProgramDay{
+			{Week: 1, Day: 1, RoutineID: 4, Prescriptions: []models.Prescription{{ExerciseID: 6, PercentTM: 200, Reps: 5}}},
+			{Week: 1, Day: 1, RoutineID: 8},
+		},
+		Progressions: []models.ProgressionRule{
+			{ExerciseID: 3, Increment: 5, Trigger: "monthly"},
+			{ExerciseID: 3, Increment: 5, Trigger: models.ProgressOnWeek},
+		},
+	})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	var appErr *apperrors.Error
+	assert.ErrorAs(t, err, &appErr)
+	var fields []string
+	for _, f := range appErr.Fields {
+		fields = append(fields, f.Field)
+	}
+	assert.Equal(t, []string{
+		"visibility",
+		"days[0].prescriptions[0].exercise_id",
+		"days[0].prescriptions[0].percent_tm",
+		"days[1].day",
+		"days[1].routine_id",
+		"progressions[0].trigger",
+		"progressions[1].exercise_id",
+	}, fields)
+	m.programs.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestProgramService_UpdateProgram_NotOwner(t *testing.T) {
+	ctx := context.Background()
+	service, m := newTestProgramService()
+
+	m.programs.On("GetById", mock.Anything, 2).Return(testProgram(), nil)
+
+	err := service.UpdateProgram(ctx, models.Viewer{UserID: 7}, models.Program{ID: 2, Name: "Mine now"})
+	assert.ErrorIs(t, err, apperrors.ErrForbidden)
+	m.programs.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestProgramService_GetProgram_Private(t *testing.T) {
+	ctx := context.Background()
+	service, m := newTestProgramService()
+
+	program := testProgram()
+	program.Visibility = models.VisibilityPrivate
+	m.programs.On("GetById", mock.Anything, 2).Return(program, nil)
+
+	_, err := service.GetProgram(ctx, models.Viewer{UserID: 7}, 2)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestProgramService_Enroll(t *testing.T) {
+	ctx := context.Background()
+	service, m := newTestProgramService()
+
+	maxes := []models.TrainingMax{{ExerciseID: 3, Weight: 140}}
+	m.programs.On("GetById", mock.Anything, 2).Return(testProgram(), nil)
+	m.enrollments.On("Create", mock.Anything, models.Enrollment{
+		UserID: 7, ProgramID: 2, Week: 1, Day: 1, Cycle: 1, Status: models.EnrollmentActive, TrainingMaxes: maxes,
+	}).Return(models.Enrollment{ID: 9}, nil)
+
+	enrollment, err := service.Enroll(ctx, models.Viewer{UserID: 7}, 2, maxes)
+	assert.NoError(t, err)
+	assert.Equal(t, 9, enrollment.ID)
+	m.enrollments.AssertExpectations(t)
+}
+
+func TestProgramService_Enroll_TrainingMaxes(t *testing.T) {
+	ctx := context.Background()
+	service, m := newTestProgramService()
+
+	m.programs.On("GetById", mock.Anything, 2).Return(testProgram(), nil)
+
+	_, err := service.Enroll(ctx, models.Viewer{UserID: 7}, 2, []models.TrainingMax{{ExerciseID: 5, Weight: 0}})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	var appErr *apperrors.Error
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, []apperrors.FieldError{
+		{Field: "training_maxes[0].exercise_id", Message: "the program does not prescribe this exercise by percentage"},
+		{Field: "training_maxes[0].weight", Message: "training max must be greater than zero"},
+		{Field: "training_maxes", Message: "training max required for exercise 3"},
+	}, appErr.Fields)
+	m.enrollments.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestProgramService_Today(t *testing.T) {
+	ctx := context.Background()
+	service, m := newTestProgramService()
+
+	rest := 180
+	m.enrollments.On("GetActive", mock.Anything, 7).Return(models.Enrollment{
+		ID: 9, UserID: 7, ProgramID: 2, Week: 1, Day: 1, Cycle: 2, Status: models.EnrollmentActive,
+		TrainingMaxes: []models.TrainingMax{{ExerciseID: 3, Weight: 142.5}},
+	}, nil)
+	m.programs.On("GetById", mock.Anything, 2).Return(testProgram(), nil)
+	m.routines.On("GetById", mock.Anything, 4).Return(models.Routine{ID: 4, UserID: 1, Exercises: []models.RoutineExercise{
+		{ExerciseID: 3, Position: 1, TargetSets: 3, RepMin: 5, RepMax: 5, RestSeconds: &rest},
+		{ExerciseID: 6, Position: 2, TargetSets: 2, RepMin: 10, RepMax: 12},
+	}}, nil)
+	m.exercises.On("GetById", mock.Anything, 3).Return(models.Exercise{ID: 3, Visibility: models.VisibilityGlobal}, nil)
+	m.exercises.On("GetById", mock.Anything, 6).Return(models.Exercise{ID: 6, Visibility: models.VisibilityGlobal}, nil)
+	m.workouts.On("LastPerformance", mock.Anything, 7, []int{3, 6}).Return(map[int][]models.WorkoutSet{
+		6: {{ExerciseID: 6, SetNumber: 1, Reps: 12, Weight: 20}},
+	}, nil)
+
+	session, err := service.Today(ctx, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, "Squat", session.Name)
+	assert.Equal(t, 2, session.Cycle)
+	assert.Len(t, session.PlannedSets, 5)
+
+	// 142.5 × 65% = 92.625, rounded to the nearest 2.5
+	first := session.PlannedSets[0]
+	assert.Equal(t, 92.5, first.Weight)
+	assert.Equal(t, 65.0, *first.PercentTM)
+	assert.Equal(t, models.PlannedFromProgram, first.Source)
+	assert.Equal(t, 180, *first.RestSeconds)
+	assert.Equal(t, 120.0, session.PlannedSets[2].Weight)
+	assert.True(t, session.PlannedSets[2].AMRAP)
+
+	// Exercises without prescriptions are planned like a routine
+	accessory := session.PlannedSets[3]
+	assert.Equal(t, 6, accessory.ExerciseID)
+	assert.Equal(t, 20.0, accessory.Weight)
+	assert.Equal(t, models.PlannedFromHistory, accessory.Source)
+}
+
+func TestProgramService_StartToday_AlreadyStarted(t *testing.T) {
+	ctx := context.Background()
+	service, m := newTestProgramService()
+
+	workoutID := 12
+	m.enrollments.On("GetActive", mock.Anything, 7).Return(models.Enrollment{
+		ID: 9, UserID: 7, ProgramID: 2, Week: 1, Day: 2, Cycle: 1, Status: models.EnrollmentActive, WorkoutID: &workoutID,
+	}, nil)
+	m.programs.On("GetById", mock.Anything, 2).Return(testProgram(), nil)
+	m.routines.On("GetById", mock.Anything, 5).Return(models.Routine{ID: 5, UserID: 1}, nil)
+	m.workouts.On("LastPerformance", mock.Anything, 7, []int(nil)).Return(map[int][]models.WorkoutSet{}, nil)
+
+	_, err := service.StartToday(ctx, 7, time.Time{})
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	m.workouts.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestProgramService_Advance_UnfinishedWorkout(t *testing.T) {
+	ctx := context.Background()
+	service, m := newTestProgramService()
+
+	workoutID := 12
+	m.enrollments.On("GetActive", mock.Anything, 7).Return(models.Enrollment{
+		ID: 9, ProgramID: 2, Week: 1, Day: 1, Cycle: 1, Status: models.EnrollmentActive, WorkoutID: &workoutID,
+	}, nil)
+	m.programs.On("GetById", mock.Anything, 2).Return(testProgram(), nil)
+	m.workouts.On("GetById", mock.Anything, 12).Return(models.Workout{ID: 12, UserID: 7}, nil)
+
+	_, err := service.Advance(ctx, 7)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	m.enrollments.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestProgramService_Advance_SessionProgression(t *testing.T) {
+	ctx := context.Background()
+	service, m := newTestProgramService()
+
+	workoutID := 12
+	finished := time.Now()
+	m.enrollments.On("GetActive", mock.Anything, 7).Return(models.Enrollment{
+		ID: 9, ProgramID: 2, Week: 1, Day: 1, Cycle: 1, Status: models.EnrollmentActive, WorkoutID: &workoutID,
+		TrainingMaxes: []models.TrainingMax{{ExerciseID: 3, Weight: 140}, {ExerciseID: 6, Weight: 40}},
+	}, nil)
+	m.programs.On("GetById", mock.Anything, 2).Return(testProgram(), nil)
+	m.workouts.On("GetById", mock.Anything, 12).Return(models.Workout{ID: 12, UserID: 7, FinishedAt: &finished}, nil)
+	m.workouts.On("GetPlannedSets", mock.Anything, 12).Return([]models.PlannedSet{
+		{ExerciseID: 3, SetNumber: 1, Reps: 5, Weight: 90},
+		{ExerciseID: 6, SetNumber: 1, Reps: 10, Weight: 20},
+		{ExerciseID: 6, SetNumber: 2, Reps: 10, Weight: 20},
+	}, nil)
+	m.workouts.On("GetSets", mock.Anything, 12).Return([]models.WorkoutSet{
+		{ExerciseID: 6, SetNumber: 2, Reps: 10, Weight: 20},
+		{ExerciseID: 6, SetNumber: 1, Reps: 12, Weight: 20},
+	}, nil)
+	m.enrollments.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	enrollment, err := service.Advance(ctx, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, enrollment.Week)
+	assert.Equal(t, 2, enrollment.Day)
+	assert.Nil(t, enrollment.WorkoutID)
+	// Squat was skipped so its session rule does not apply; the cycle rule waits for a new cycle
+	assert.Equal(t, []models.TrainingMax{{ExerciseID: 3, Weight: 140}, {ExerciseID: 6, Weight: 42.5}}, enrollment.TrainingMaxes)
+	m.enrollments.AssertExpectations(t)
+}
+
+func TestProgramService_Advance_NewCycle(t *testing.T) {
+	ctx := context.Background()
+	service, m := newTestProgramService()
+
+	m.enrollments.On("GetActive", mock.Anything, 7).Return(models.Enrollment{
+		ID: 9, ProgramID: 2, Week: 2, Day: 1, Cycle: 1, Status: models.EnrollmentActive,
+		TrainingMaxes: []models.TrainingMax{{ExerciseID: 3, Weight: 140}},
+	}, nil)
+	m.programs.On("GetById", mock.Anything, 2).Return(testProgram(), nil)
+	m.enrollments.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	enrollment, err := service.Advance(ctx, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, enrollment.Week)
+	assert.Equal(t, 1, enrollment.Day)
+	assert.Equal(t, 2, enrollment.Cycle)
+	assert.Equal(t, 145.0, enrollment.TrainingMaxes[0].Weight)
+	assert.Equal(t, models.EnrollmentActive, enrollment.Status)
+}
+
+func TestProgramService_Advance_CompletesProgram(t *testing.T) {
+	ctx := context.Background()
+	service, m := newTestProgramService()
+
+	program := testProgram()
+	program.Repeats = false
+	m.enrollments.On("GetActive", mock.Anything, 7).Return(models.Enrollment{
+		ID: 9, ProgramID: 2, Week: 2, Day: 1, Cycle: 1, Status: models.EnrollmentActive,
+	}, nil)
+	m.programs.On("GetById", mock.Anything, 2).Return(program, nil)
+	m.enrollments.On("Update", mock.Anything, mock.MatchedBy(func(e models.Enrollment) bool {
+		return e.Status == models.EnrollmentCompleted && e.EndedAt != nil
+	})).Return(nil)
+
+	enrollment, err := service.Advance(ctx, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, models.EnrollmentCompleted, enrollment.Status)
+	m.enrollments.AssertExpectations(t)
+}
+
+func TestCurrentDay_RemovedDay(t *testing.T) {
+	program := testProgram()
+	// Week 1 day 3 does not exist, so the next scheduled day is due
+	assert.Equal(t, 2, currentDay(program, models.Enrollment{Week: 1, Day: 3}))
+	assert.Equal(t, 2, currentDay(program, models.Enrollment{Week: 9, Day: 1}))
+	assert.Equal(t, -1, currentDay(models.Program{}, models.Enrollment{Week: 1, Day: 1}))
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+	"workout-api/internal/repository"
+)
+
+type RecordService struct {
+	repo         repository.PersonalRecordRepositoryInterface
+	exerciseRepo repository.ExerciseRepositoryInterface
+}
+
+func NewRecordService(repo repository.PersonalRecordRepositoryInterface, exerciseRepo repository.ExerciseRepositoryInterface) *RecordService {
+	return &RecordService{repo: repo, exerciseRepo: exerciseRepo}
+}
+
+// ListRecords returns one page of the viewer's personal records matching
+// filter, newest first by default. A filtered exercise must be visible to
+// the viewer.
+func (s *RecordService) ListRecords(ctx context.Context, viewer models.Viewer, filter models.RecordFilter, query pagination.Query) (pagination.Page[models.PersonalRecord], error) {
+	switch filter.Category {
+	case "", models.RecordHeaviestWeight, models.RecordMostReps, models.RecordBestE1RM, models.RecordSessionVolume,
+		models.RecordLongestDistance, models.RecordFastestTime:
+	default:
+		return pagination.Page[models.PersonalRecord]{}, apperrors.Invalid("category", "unknown record category")
+	}
+	if filter.ExerciseID < 0 {
+		return pagination.Page[models.PersonalRecord]{}, apperrors.Invalid("exercise_id", "invalid exercise ID")
+	}
+
+	params, err := repository.RecordListSpec.Parse(query)
+	if err != nil {
+		return pagination.Page[models.PersonalRecord]{}, err
+	}
+
+	if filter.ExerciseID != 0 {
+		exercise, err := s.exerciseRepo.GetById(ctx, filter.ExerciseID)
+		if err != nil {
+			return pagination.Page[models.PersonalRecord]{}, err
+		}
+		if !exercise.VisibleTo(viewer) {
+			return pagination.Page[models.PersonalRecord]{}, apperrors.NotFound("exercise", filter.ExerciseID)
+		}
+	}
+
+	return s.repo.ListByUser(ctx, viewer.UserID, filter, params)
+}
+
+// saveRecords saves the personal records a newly stored set breaks as
+// achieved at the given time, or now when it is zero
+func saveRecords(ctx context.Context, repo repository.PersonalRecordRepositoryInterface, userID int, set models.WorkoutSet, achievedAt time.Time) ([]models.PersonalRecord, error) {
+	bests, err := repo.PreviousBests(ctx, userID, set)
+	if err != nil {
+		return nil, err
+	}
+	if achievedAt.IsZero() {
+		achievedAt = time.Now()
+	}
+	records := detectRecords(userID, set, bests, achievedAt)
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return repo.Create(ctx, records)
+}
+
+// detectRecords compares a logged set against the user's previous bests.
+// A category needs an earlier best to beat, so the first time an exercise
+// is logged only sets the baseline.
+func detectRecords(userID int, set models.WorkoutSet, bests models.RecordBests, achievedAt time.Time) []models.PersonalRecord {
+	var records []models.PersonalRecord
+	record := func(category string, value, previous float64) {
+		records = append(records, models.PersonalRecord{
+			UserID:          userID,
+			ExerciseID:      set.ExerciseID,
+			WorkoutID:       set.WorkoutID,
+			SetID:           set.ID,
+			Category:        category,
+			Value:           value,
+			Previous:        previous,
+			Weight:          set.Weight,
+			Reps:            set.Reps,
+			DistanceMeters:  set.DistanceMeters,
+			DurationSeconds: set.DurationSeconds,
+			AchievedAt:      achievedAt,
+		})
+	}
+
+	if bests.Weight > 0 && set.Weight > bests.Weight {
+		record(models.RecordHeaviestWeight, set.Weight, bests.Weight)
+	}
+	if bests.RepsAtWeight > 0 && set.Reps > bests.RepsAtWeight {
+		record(models.RecordMostReps, float64(set.Reps), float64(bests.RepsAtWeight))
+	}
+	if e1rm, ok := EstimateOneRepMax(models.FormulaEpley, set.Weight, set.Reps); ok && bests.E1RM > 0 && e1rm > bests.E1RM {
+		record(models.RecordBestE1RM, e1rm, bests.E1RM)
+	}
+	if bests.SessionVolume > 0 && bests.CurrentVolume > bests.SessionVolume {
+		record(models.RecordSessionVolume, bests.CurrentVolume, bests.SessionVolume)
+	}
+	if set.DistanceMeters != nil && bests.Distance > 0 && *set.DistanceMeters > bests.Distance {
+		record(models.RecordLongestDistance, *set.DistanceMeters, bests.Distance)
+	}
+	if set.DistanceMeters != nil && set.DurationSeconds != nil && bests.TimeAtDistance > 0 && *set.DurationSeconds < bests.TimeAtDistance {
+		record(models.RecordFastestTime, float64(*set.DurationSeconds), float64(bests.TimeAtDistance))
+	}
+	return records
+}
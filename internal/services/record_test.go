@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+	"workout-api/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock PersonalRecordRepository that implements repository.PersonalRecordRepositoryInterface
+type MockPersonalRecordRepository struct {
+	mock.Mock
+}
+
+func (m *MockPersonalRecordRepository) PreviousBests(ctx context.Context, userID int, set models.WorkoutSet) (models.RecordBests, error) {
+	args := m.Called(ctx, userID, set)
+	return args.Get(0).(models.RecordBests), args.Error(1)
+}
+
+func (m *MockPersonalRecordRepository) Create(ctx context.Context, records []models.PersonalRecord) ([]models.PersonalRecord, error) {
+	args := m.Called(ctx, records)
+	return args.Get(0).([]models.PersonalRecord), args.Error(1)
+}
+
+func (m *MockPersonalRecordRepository) ListByUser(ctx context.Context, userID int, filter models.RecordFilter, params pagination.Params) (pagination.Page[models.PersonalRecord], error) {
+	args := m.Called(ctx, userID, filter, params)
+	return args.Get(0).(pagination.Page[models.PersonalRecord]), args.Error(1)
+}
+
+var _ repository.PersonalRecordRepositoryInterface = (*MockPersonalRecordRepository)(nil)
+
+func newTestRecordService() (*RecordService, *MockPersonalRecordRepository, *MockExerciseRepository) {
+	repo := new(MockPersonalRecordRepository)
+	exerciseRepo := new(MockExerciseRepository)
+	return NewRecordService(repo, exerciseRepo), repo, exerciseRepo
+}
+
+func TestDetectRecords(t *testing.T) {
+	at := time.Date(2024, 7, 1, 18, 0, 0, 0, time.UTC)
+	set := models.WorkoutSet{ID: 5, WorkoutID: 2, ExerciseID: 3, Weight: 100, Reps: 8}
+	bests := models.RecordBests{Weight: 100, RepsAtWeight: 6, E1RM: 120, SessionVolume: 2000, CurrentVolume: 2400}
+
+	records := detectRecords(1, set, bests, at)
+	assert.Len(t, records, 3)
+
+	assert.Equal(t, models.RecordMostReps, records[0].Category)
+	assert.Equal(t, 8.0, records[0].Value)
+	assert.Equal(t, 6.0, records[0].Previous)
+
+	assert.Equal(t, models.RecordBestE1RM, records[1].Category)
+	assert.Equal(t, 126.67, records[1].Value)
+
+	assert.Equal(t, models.RecordSessionVolume, records[2].Category)
+	assert.Equal(t, 2400.0, records[2].Value)
+	assert.Equal(t, 2000.0, records[2].Previous)
+
+	for _, pr := range records {
+		assert.Equal(t, 5, pr.SetID)
+		assert.Equal(t, 2, pr.WorkoutID)
+		assert.Equal(t, at, pr.AchievedAt)
+	}
+}
+
+func TestDetectRecords_FirstPerformanceIsBaseline(t *testing.T) {
+	set := models.WorkoutSet{ID: 5, WorkoutID: 2, ExerciseID: 3, Weight: 100, Reps: 8}
+
+	records := detectRecords(1, set, models.RecordBests{CurrentVolume: 800}, time.Now())
+	assert.Empty(t, records)
+}
+
+func TestDetectRecords_HeaviestWeightOnly(t *testing.T) {
+	set := models.WorkoutSet{ID: 5, WorkoutID: 2, ExerciseID: 3, Weight: 140, Reps: 1}
+	bests := models.RecordBests{Weight: 135, E1RM: 150, SessionVolume: 3000, CurrentVolume: 140}
+
+	records := detectRecords(1, set, bests, time.Now())
+	assert.Len(t, records, 1)
+	assert.Equal(t, models.RecordHeaviestWeight, records[0].Category)
+	assert.Equal(t, 140.0, records[0].Value)
+	assert.Equal(t, 135.0, records[0].Previous)
+}
+
+func TestEstimateOneRepMax(t *testing.T) {
+	e1rm, ok := EstimateOneRepMax(models.FormulaEpley, 100, 5)
+	assert.True(t, ok)
+	assert.Equal(t, 116.67, e1rm)
+
+	e1rm, ok = EstimateOneRepMax(models.FormulaEpley, 100, 1)
+	assert.True(t, ok)
+	assert.Equal(t, 100.0, e1rm)
+
+	e1rm, ok = EstimateOneRepMax(models.FormulaBrzycki, 100, 5)
+	assert.True(t, ok)
+	assert.Equal(t, 112.5, e1rm)
+
+	_, ok = EstimateOneRepMax(models.FormulaEpley, 100, models.MaxE1RMReps+1)
+	assert.False(t, ok)
+	_, ok = EstimateOneRepMax(models.FormulaEpley, 0, 5)
+	assert.False(t, ok)
+}
+
+func TestRecordService_ListRecords(t *testing.T) {
+	ctx := context.Background()
+	service, repo, exerciseRepo := newTestRecordService()
+
+	filter := models.RecordFilter{ExerciseID: 3, Category: models.RecordBestE1RM}
+	exerciseRepo.On("GetById", mock.Anything, 3).Return(models.Exercise{ID: 3, Visibility: models.VisibilityGlobal}, nil)
+	repo.On("ListByUser", mock.Anything, 1, filter, mock.MatchedBy(func(p pagination.Params) bool {
+		return p.Sort == "achieved_at" && p.Desc
+	})).Return(pagination.Page[models.PersonalRecord]{Items: []models.PersonalRecord{{ID: 4}}}, nil)
+
+	page, err := service.ListRecords(ctx, models.Viewer{UserID: 1}, filter, pagination.Query{})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	repo.AssertExpectations(t)
+}
+
+func TestRecordService_ListRecords_UnknownCategory(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _ := newTestRecordService()
+
+	_, err := service.ListRecords(ctx, models.Viewer{UserID: 1}, models.RecordFilter{Category: "fastest_mile"}, pagination.Query{})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	repo.AssertNotCalled(t, "ListByUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecordService_ListRecords_HiddenExercise(t *testing.T) {
+	ctx := context.Background()
+	service, repo, exerciseRepo := newTestRecordService()
+
+	ownerID := 2
+	exerciseRepo.On("GetById", mock.Anything, 3).Return(models.Exercise{ID: 3, OwnerID: &ownerID, Visibility: models.VisibilityPrivate}, nil)
+
+	_, err := service.ListRecords(ctx, models.Viewer{UserID: 1}, models.RecordFilter{ExerciseID: 3}, pagination.Query{})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	repo.AssertNotCalled(t, "ListByUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDetectRecords_Cardio(t *testing.T) {
+	distance := 5000.0
+	duration := 1440
+	set := models.WorkoutSet{ID: 5, WorkoutID: 2, ExerciseID: 8, DistanceMeters: &distance, DurationSeconds: &duration}
+	bests := models.RecordBests{Distance: 4000, TimeAtDistance: 1500}
+
+	records := detectRecords(1, set, bests, time.Now())
+	assert.Len(t, records, 2)
+
+	assert.Equal(t, models.RecordLongestDistance, records[0].Category)
+	assert.Equal(t, 5000.0, records[0].Value)
+	assert.Equal(t, 4000.0, records[0].Previous)
+
+	assert.Equal(t, models.RecordFastestTime, records[1].Category)
+	assert.Equal(t, 1440.0, records[1].Value)
+	assert.Equal(t, 1500.0, records[1].Previous)
+	assert.Equal(t, &distance, records[1].DistanceMeters)
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+	"workout-api/internal/repository"
+)
+
+// Limits on routine size keep templates and the workouts started from them
+// reasonable
+const (
+	maxRoutineExercises = 50
+	maxTargetSets       = 20
+)
+
+type RoutineService struct {
+	repo         repository.RoutineRepositoryInterface
+	exerciseRepo repository.ExerciseRepositoryInterface
+	workoutRepo  repository.WorkoutRepositoryInterface
+}
+
+func NewRoutineService(repo repository.RoutineRepositoryInterface, exerciseRepo repository.ExerciseRepositoryInterface, workoutRepo repository.WorkoutRepositoryInterface) *RoutineService {
+	return &RoutineService{repo: repo, exerciseRepo: exerciseRepo, workoutRepo: workoutRepo}
+}
+
+// CreateRoutine saves a new routine for the user. Exercises keep the order
+// they are given in.
+func (s *RoutineService) CreateRoutine(ctx context.Context, userID int, routine models.Routine) (models.Routine, error) {
+	routine.UserID = userID
+	routine, err := s.validate(ctx, routine)
+	if err != nil {
+		return models.Routine{}, err
+	}
+	return s.repo.Create(ctx, routine)
+}
+
+// GetRoutine returns one of the user's routines
+func (s *RoutineService) GetRoutine(ctx context.Context, userID, id int) (models.Routine, error) {
+	if id <= 0 {
+		return models.Routine{}, apperrors.Invalid("id", "invalid routine ID")
+	}
+
+	routine, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return models.Routine{}, err
+	}
+	// Other users' routines are indistinguishable from missing ones
+	if routine.UserID != userID {
+		return models.Routine{}, apperrors.NotFound("routine", id)
+	}
+	return routine, nil
+}
+
+// ListRoutines returns one page of the user's routines
+func (s *RoutineService) ListRoutines(ctx context.Context, userID int, query pagination.Query) (pagination.Page[models.Routine], error) {
+	params, err := repository.RoutineListSpec.Parse(query)
+	if err != nil {
+		return pagination.Page[models.Routine]{}, err
+	}
+	return s.repo.ListByUser(ctx, userID, params)
+}
+
+// UpdateRoutine replaces one of the user's routines, exercises included
+func (s *RoutineService) UpdateRoutine(ctx context.Context, userID int, routine models.Routine) error {
+	if _, err := s.GetRoutine(ctx, userID, routine.ID); err != nil {
+		return err
+	}
+	routine.UserID = userID
+	routine, err := s.validate(ctx, routine)
+	if err != nil {
+		return err
+	}
+	return s.repo.Update(ctx, routine)
+}
+
+func (s *RoutineService) DeleteRoutine(ctx context.Context, userID, id int) error {
+	if _, err := s.GetRoutine(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// StartRoutine opens a workout from one of the user's routines. Every set
+// the routine prescribes is planned, prefilled with the reps and weight of
+// the matching set from the last finished workout that included the
+// exercise. Exercises the user can no longer see are left out.
+func (s *RoutineService) StartRoutine(ctx context.Context, userID, id int, startedAt time.Time) (models.Workout, error) {
+	routine, err := s.GetRoutine(ctx, userID, id)
+	if err != nil {
+		return models.Workout{}, err
+	}
+
+	viewer := models.Viewer{UserID: userID}
+	var (
+		exercises []models.RoutineExercise
+		ids       []int
+	)
+	for _, e := range routine.Exercises {
+		exercise, err := s.exerciseRepo.GetById(ctx, e.ExerciseID)
+		if errors.Is(err, apperrors.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return models.Workout{}, err
+		}
+		if !exercise.VisibleTo(viewer) {
+			continue
+		}
+		exercises = append(exercises, e)
+		ids = append(ids, e.ExerciseID)
+	}
+
+	last, err := s.workoutRepo.LastPerformance(ctx, userID, ids)
+	if err != nil {
+		return models.Workout{}, err
+	}
+
+	if startedAt.IsZero() {
+		startedAt = time.Now()
+	}
+	routineID := routine.ID
+	workout := models.Workout{
+		UserID:      userID,
+		RoutineID:   &routineID,
+		Name:        routine.Name,
+		Notes:       routine.Notes,
+		StartedAt:   startedAt,
+		PlannedSets: planSets(exercises, last),
+	}
+	return s.workoutRepo.Create(ctx, workout)
+}
+
+// planSets expands routine exercises into planned sets. Set n copies set n
+// of the last performance, or its final set when fewer were logged; without
+// history the routine's minimum reps and target weight are used.
+func planSets(exercises []models.RoutineExercise, last map[int][]models.WorkoutSet) []models.PlannedSet {
+	var planned []models.PlannedSet
+	for _, e := range exercises {
+		previous := last[e.ExerciseID]
+		for n := 1; n <= e.TargetSets; n++ {
+			p := models.PlannedSet{
+				ExerciseID:  e.ExerciseID,
+				Position:    e.Position,
+				SetNumber:   n,
+				RepMin:      e.RepMin,
+				RepMax:      e.RepMax,
+				Reps:        e.RepMin,
+				RPE:         e.TargetRPE,
+				RestSeconds: e.RestSeconds,
+				Source:      models.PlannedFromTemplate,
+			}
+			if e.TargetWeight != nil {
+				p.Weight = *e.TargetWeight
+			}
+			if len(previous) > 0 {
+				prev := previous[min(n, len(previous))-1]
+				p.Reps = prev.Reps
+				p.Weight = prev.Weight
+				if p.RestSeconds == nil {
+					p.RestSeconds = prev.RestSeconds
+				}
+				p.Source = models.PlannedFromHistory
+			}
+			planned = append(planned, p)
+		}
+	}
+	return planned
+}
+
+// validate checks the routine and its exercises, defaulting an unset
+// maximum rep count to the minimum. Every exercise must be visible to the
+// routine's owner.
+func (s *RoutineService) validate(ctx context.Context, routine models.Routine) (models.Routine, error) {
+	var fields []apperrors.FieldError
+	routine.Name = strings.TrimSpace(routine.Name)
+	if routine.Name == "" {
+		fields = append(fields, apperrors.FieldError{Field: "name", Message: "routine name is required"})
+	}
+	switch {
+	case len(routine.Exercises) == 0:
+		fields = append(fields, apperrors.FieldError{Field: "exercises", Message: "a routine needs at least one exercise"})
+	case len(routine.Exercises) > maxRoutineExercises:
+		fields = append(fields, apperrors.FieldError{Field: "exercises", Message: fmt.Sprintf("a routine can have at most %d exercises", maxRoutineExercises)})
+	}
+
+	viewer := models.Viewer{UserID: routine.UserID}
+	visible := map[int]bool{}
+	for i := range routine.Exercises {
+		e := &routine.Exercises[i]
+		field := func(name string) string { return fmt.Sprintf("exercises[%d].%s", i, name) }
+
+		if e.RepMax == 0 {
+			e.RepMax = e.RepMin
+		}
+		if e.TargetSets <= 0 || e.TargetSets > maxTargetSets {
+			fields = append(fields, apperrors.FieldError{Field: field("target_sets"), Message: fmt.Sprintf("target sets must be between 1 and %d", maxTargetSets)})
+		}
+		if e.RepMin <= 0 {
+			fields = append(fields, apperrors.FieldError{Field: field("rep_min"), Message: "minimum reps must be greater than zero"})
+		} else if e.RepMax < e.RepMin {
+			fields = append(fields, apperrors.FieldError{Field: field("rep_max"), Message: "maximum reps cannot be below minimum reps"})
+		}
+		if e.TargetWeight != nil && *e.TargetWeight < 0 {
+			fields = append(fields, apperrors.FieldError{Field: field("target_weight"), Message: "target weight cannot be negative"})
+		}
+		if e.TargetRPE != nil && (*e.TargetRPE < 1 || *e.TargetRPE > 10) {
+			fields = append(fields, apperrors.FieldError{Field: field("target_rpe"), Message: "RPE must be between 1 and 10"})
+		}
+		if e.RestSeconds != nil && *e.RestSeconds < 0 {
+			fields = append(fields, apperrors.FieldError{Field: field("rest_seconds"), Message: "rest seconds cannot be negative"})
+		}
+
+		ok, seen := visible[e.ExerciseID]
+		if !seen && e.ExerciseID > 0 {
+			exercise, err := s.exerciseRepo.GetById(ctx, e.ExerciseID)
+			if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+				return models.Routine{}, err
+			}
+			ok = err == nil && exercise.VisibleTo(viewer)
+			visible[e.ExerciseID] = ok
+		}
+		if !ok {
+			fields = append(fields, apperrors.FieldError{Field: field("exercise_id"), Message: "exercise not found"})
+		}
+	}
+
+	if len(fields) > 0 {
+		return models.Routine{}, apperrors.Validation(fields...)
+	}
+	return routine, nil
+}
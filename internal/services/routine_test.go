@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+	"workout-api/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock RoutineRepository that implements repository.RoutineRepositoryInterface
+type MockRoutineRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoutineRepository) Create(ctx context.Context, routine models.Routine) (models.Routine, error) {
+	args := m.Called(ctx, routine)
+	return args.Get(0).(models.Routine), args.Error(1)
+}
+
+func (m *MockRoutineRepository) GetById(ctx context.Context, id int) (models.Routine, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.Routine), args.Error(1)
+}
+
+func (m *MockRoutineRepository) ListByUser(ctx context.Context, userID int, params pagination.Params) (pagination.Page[models.Routine], error) {
+	args := m.Called(ctx, userID, params)
+	return args.Get(0).(pagination.Page[models.Routine]), args.Error(1)
+}
+
+func (m *MockRoutineRepository) Update(ctx context.Context, routine models.Routine) error {
+	args := m.Called(ctx, routine)
+	return args.Error(0)
+}
+
+func (m *MockRoutineRepository) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// Ensure MockRoutineRepository implements the interface
+var _ repository.RoutineRepositoryInterface = (*MockRoutineRepository)(nil)
+
+func newTestRoutineService() (*RoutineService, *MockRoutineRepository, *MockExerciseRepository, *MockWorkoutRepository) {
+	repo := new(MockRoutineRepository)
+	exerciseRepo := new(MockExerciseRepository)
+	workoutRepo := new(MockWorkoutRepository)
+	return NewRoutineService(repo, exerciseRepo, workoutRepo), repo, exerciseRepo, workoutRepo
+}
+
+func TestRoutineService_CreateRoutine(t *testing.T) {
+	ctx := context.Background()
+	service, repo, exerciseRepo, _ := newTestRoutineService()
+
+	exerciseRepo.On("GetById", mock.Anything, 3).Return(models.Exercise{ID: 3, Visibility: models.VisibilityGlobal}, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(r models.Routine) bool {
+		return r.UserID == 1 && r.Name == "Upper A" && r.Exercises[0].RepMax == 5
+	})).Return(models.Routine{ID: 4, UserID: 1, Name: "Upper A"}, nil)
+
+	routine, err := service.CreateRoutine(ctx, 1, models.Routine{
+		Name:      "  Upper A ",
+		Exercises: []models.RoutineExercise{{ExerciseID: 3, TargetSets: 3, RepMin: 5}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, routine.ID)
+	repo.AssertExpectations(t)
+}
+
+func TestRoutineService_CreateRoutine_Invalid(t *testing.T) {
+	ctx := context.Background()
+	service, repo, exerciseRepo, _ := newTestRoutineService()
+
+	rpe := 11.0
+	ownerID := 2
+	exerciseRepo.On("GetById", mock.Anything, 3).Return(models.Exercise{ID: 3, Visibility: models.VisibilityGlobal}, nil)
+	exerciseRepo.On("GetById", mock.Anything, 8).Return(models.Exercise{ID: 8, OwnerID: &ownerID, Visibility: models.VisibilityPrivate}, nil)
+	exerciseRepo.On("GetById", mock.Anything, 9).Return(models.Exercise{}, apperrors.NotFound("exercise", 9))
+
+	_, err := service.CreateRoutine(ctx, 1, models.Routine{
+		Name: "Upper A",
+		Exercises: []models.RoutineExercise{
+			{ExerciseID: 3, TargetSets: 0, RepMin: 8, RepMax: 6, TargetRPE: &rpe},
+			{ExerciseID: 8, TargetSets: 3, RepMin: 5},
+			{ExerciseID: 9, TargetSets: 3, RepMin: 5},
+		},
+	})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	var appErr *apperrors.Error
+	assert.ErrorAs(t, err, &appErr)
+	var fields []string
+	for _, f := range appErr.Fields {
+		fields = append(fields, f.Field)
+	}
+	assert.Equal(t, []string{
+		"exercises[0].target_sets",
+		"exercises[0].rep_max",
+		"exercises[0].target_rpe",
+		"exercises[1].exercise_id",
+		"exercises[2].exercise_id",
+	}, fields)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestRoutineService_GetRoutine_OtherUser(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _, _ := newTestRoutineService()
+
+	repo.On("GetById", mock.Anything, 4).Return(models.Routine{ID: 4, UserID: 2}, nil)
+
+	_, err := service.GetRoutine(ctx, 1, 4)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestRoutineService_DeleteRoutine_OtherUser(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _, _ := newTestRoutineService()
+
+	repo.On("GetById", mock.Anything, 4).Return(models.Routine{ID: 4, UserID: 2}, nil)
+
+	err := service.DeleteRoutine(ctx, 1, 4)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestRoutineService_StartRoutine(t *testing.T) {
+	ctx := context.Background()
+	service, repo, exerciseRepo, workoutRepo := newTestRoutineService()
+
+	weight := 40.0
+	rest := 90
+	ownerID := 2
+	repo.On("GetById", mock.Anything, 4).Return(models.Routine{
+		ID:     4,
+		UserID: 1,
+		Name:   "Upper A",
+		Exercises: []models.RoutineExercise{
+			{ExerciseID: 3, Position: 1, TargetSets: 3, RepMin: 6, RepMax: 8},
+			{ExerciseID: 5, Position: 2, TargetSets: 2, RepMin: 10, RepMax: 12, TargetWeight: &weight, RestSeconds: &rest},
+			{ExerciseID: 8, Position: 3, TargetSets: 2, RepMin: 10, RepMax: 12},
+		},
+	}, nil)
+	exerciseRepo.On("GetById", mock.Anything, 3).Return(models.Exercise{ID: 3, Visibility: models.VisibilityGlobal}, nil)
+	exerciseRepo.On("GetById", mock.Anything, 5).Return(models.Exercise{ID: 5, Visibility: models.VisibilityGlobal}, nil)
+	// Made private by its owner after the routine was saved
+	exerciseRepo.On("GetById", mock.Anything, 8).Return(models.Exercise{ID: 8, OwnerID: &ownerID, Visibility: models.VisibilityPrivate}, nil)
+
+	// Only two sets were logged last time, the third repeats the last one
+	workoutRepo.On("LastPerformance", mock.Anything, 1, []int{3, 5}).Return(map[int][]models.WorkoutSet{
+		3: {{ExerciseID: 3, SetNumber: 1, Reps: 8, Weight: 70}, {ExerciseID: 3, SetNumber: 2, Reps: 7, Weight: 72.5}},
+	}, nil)
+
+	startedAt := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	var created models.Workout
+	workoutRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).(models.Workout)
+	}).Return(models.Workout{ID: 7}, nil)
+
+	workout, err := service.StartRoutine(ctx, 1, 4, startedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, workout.ID)
+
+	assert.Equal(t, 4, *created.RoutineID)
+	assert.Equal(t, "Upper A", created.Name)
+	assert.Equal(t, startedAt, created.StartedAt)
+	assert.Len(t, created.PlannedSets, 5)
+
+	third := created.PlannedSets[2]
+	assert.Equal(t, 3, third.SetNumber)
+	assert.Equal(t, 7, third.Reps)
+	assert.Equal(t, 72.5, third.Weight)
+	assert.Equal(t, models.PlannedFromHistory, third.Source)
+
+	fresh := created.PlannedSets[3]
+	assert.Equal(t, 5, fresh.ExerciseID)
+	assert.Equal(t, 10, fresh.Reps)
+	assert.Equal(t, 40.0, fresh.Weight)
+	assert.Equal(t, 90, *fresh.RestSeconds)
+	assert.Equal(t, models.PlannedFromTemplate, fresh.Source)
+	workoutRepo.AssertExpectations(t)
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"workout-api/internal/models"
+	"workout-api/internal/repository"
+)
+
+// TaxonomyService exposes the canonical muscle groups and equipment
+type TaxonomyService struct {
+	repo repository.TaxonomyRepositoryInterface
+}
+
+func NewTaxonomyService(repo repository.TaxonomyRepositoryInterface) *TaxonomyService {
+	return &TaxonomyService{repo: repo}
+}
+
+func (s *TaxonomyService) ListMuscleGroups(ctx context.Context) ([]models.MuscleGroup, error) {
+	return s.repo.ListMuscleGroups(ctx)
+}
+
+// GetMuscleGroup looks a muscle group up by slug or any alias
+func (s *TaxonomyService) GetMuscleGroup(ctx context.Context, name string) (models.MuscleGroup, error) {
+	return s.repo.ResolveMuscleGroup(ctx, name)
+}
+
+func (s *TaxonomyService) ListEquipment(ctx context.Context) ([]models.Equipment, error) {
+	return s.repo.ListEquipment(ctx)
+}
+
+// GetEquipment looks equipment up by slug or any alias
+func (s *TaxonomyService) GetEquipment(ctx context.Context, name string) (models.Equipment, error) {
+	return s.repo.ResolveEquipment(ctx, name)
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+	"workout-api/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock TaxonomyRepository that implements repository.TaxonomyRepositoryInterface
+type MockTaxonomyRepository struct {
+	mock.Mock
+}
+
+func (m *MockTaxonomyRepository) ListMuscleGroups(ctx context.Context) ([]models.MuscleGroup, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.MuscleGroup), args.Error(1)
+}
+
+func (m *MockTaxonomyRepository) ResolveMuscleGroup(ctx context.Context, name string) (models.MuscleGroup, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).(models.MuscleGroup), args.Error(1)
+}
+
+func (m *MockTaxonomyRepository) ListEquipment(ctx context.Context) ([]models.Equipment, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Equipment), args.Error(1)
+}
+
+func (m *MockTaxonomyRepository) ResolveEquipment(ctx context.Context, name string) (models.Equipment, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).(models.Equipment), args.Error(1)
+}
+
+var _ repository.TaxonomyRepositoryInterface = (*MockTaxonomyRepository)(nil)
+
+// newTestTaxonomy resolves the handful of names the exercise tests use and
+// reports anything else as unknown
+func newTestTaxonomy() *MockTaxonomyRepository {
+	m := new(MockTaxonomyRepository)
+	muscles := map[string]string{
+		"Chest": "chest", "pecs": "chest", "Triceps": "triceps", "Shoulders": "shoulders", "Biceps": "biceps",
+		"full-body": "full-body",
+	}
+	for name, slug := range muscles {
+		m.On("ResolveMuscleGroup", mock.Anything, name).Return(models.MuscleGroup{Slug: slug}, nil).Maybe()
+	}
+	m.On("ResolveMuscleGroup", mock.Anything, mock.Anything).Return(models.MuscleGroup{}, apperrors.NotFound("muscle group", nil)).Maybe()
+
+	equipment := map[string]string{
+		"Bodyweight": "bodyweight", "bodyweight": "bodyweight", "Dumbbell": "dumbbell", "db": "dumbbell",
+		"Barbell": "barbell", "barbell": "barbell", "other": "other",
+	}
+	for name, slug := range equipment {
+		m.On("ResolveEquipment", mock.Anything, name).Return(models.Equipment{Slug: slug}, nil).Maybe()
+	}
+	m.On("ResolveEquipment", mock.Anything, mock.Anything).Return(models.Equipment{}, apperrors.NotFound("equipment", nil)).Maybe()
+	return m
+}
+
+func TestTaxonomyService_GetMuscleGroup_ByAlias(t *testing.T) {
+	ctx := context.Background()
+	service := NewTaxonomyService(newTestTaxonomy())
+
+	mg, err := service.GetMuscleGroup(ctx, "pecs")
+	assert.NoError(t, err)
+	assert.Equal(t, "chest", mg.Slug)
+}
+
+func TestTaxonomyService_GetEquipment_Unknown(t *testing.T) {
+	ctx := context.Background()
+	service := NewTaxonomyService(newTestTaxonomy())
+
+	_, err := service.GetEquipment(ctx, "anvil")
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+}
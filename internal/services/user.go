@@ -1,40 +1,169 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"log"
+	"workout-api/internal/apperrors"
 	"workout-api/internal/models"
+	"workout-api/internal/pagination"
 	"workout-api/internal/repository"
 )
 
+// ErrInvalidCredentials is returned by VerifyCredentials for an unknown
+// email or a wrong password, without revealing which one it was
+var ErrInvalidCredentials = apperrors.Unauthorized("invalid email or password")
+
+// dummyHash is compared against when the email is unknown so both failure
+// paths take roughly the same time
+const dummyHash = "$2a$10$tZF8o//WTuccsf93ZISuVedxrOCTox2dsEpGcTwRXNgViE6bpVV6e"
+
 type UserService struct {
-	repo repository.UserRepositoryInterface
+	repo   repository.UserRepositoryInterface
+	hasher *PasswordHasher
 }
 
-func NewUserService(repo repository.UserRepositoryInterface) *UserService {
-	return &UserService{repo: repo}
+func NewUserService(repo repository.UserRepositoryInterface, hasher *PasswordHasher) *UserService {
+	return &UserService{repo: repo, hasher: hasher}
 }
 
-func (s *UserService) CreateUser(user models.User) error {
+func (s *UserService) CreateUser(ctx context.Context, user models.User) error {
+	if user.Password == "" {
+		return apperrors.Invalid("password", "password is required")
+	}
+
 	// Check if user already exists
-	existingUser, err := s.repo.GetByEmail(user.Email)
+	if err := s.ensureEmailAvailable(ctx, user.Email); err != nil {
+		return err
+	}
+
+	hash, err := s.hasher.Hash(user.Password)
+	if err != nil {
+		return err
+	}
+	user.Password = hash
+
+	return s.repo.Create(ctx, user)
+}
+
+func (s *UserService) GetUserByID(ctx context.Context, id int) (models.User, error) {
+	if id <= 0 {
+		return models.User{}, apperrors.Invalid("id", "invalid user ID")
+	}
+	return s.repo.GetById(ctx, id)
+}
+
+func (s *UserService) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	return s.repo.GetByEmail(ctx, email)
+}
+
+// SetUserRole grants role to the user with the given email. Access tokens
+// already issued keep the old role until they are refreshed.
+func (s *UserService) SetUserRole(ctx context.Context, email, role string) (models.User, error) {
+	if role != models.RoleUser && role != models.RoleAdmin {
+		return models.User{}, apperrors.Invalid("role", "role must be user or admin")
+	}
+	user, err := s.repo.GetByEmail(ctx, email)
+	if err != nil {
+		return models.User{}, err
+	}
+	if err := s.repo.SetRole(ctx, user.ID, role); err != nil {
+		return models.User{}, err
+	}
+	user.Role = role
+	return user, nil
+}
+
+// ListUsers returns one page of users matching filter
+func (s *UserService) ListUsers(ctx context.Context, filter models.UserFilter, query pagination.Query) (pagination.Page[models.User], error) {
+	params, err := repository.UserListSpec.Parse(query)
+	if err != nil {
+		return pagination.Page[models.User]{}, err
+	}
+	return s.repo.List(ctx, filter, params)
+}
+
+// UpdateUser changes a user's name, email and optionally password. An
+// empty password keeps the current one.
+func (s *UserService) UpdateUser(ctx context.Context, user models.User) error {
+	if user.ID <= 0 {
+		return apperrors.Invalid("id", "invalid user ID")
+	}
+
+	existing, err := s.repo.GetById(ctx, user.ID)
 	if err != nil {
 		return err
 	}
-	if existingUser.ID != 0 {
-		return errors.New("user already exists")
+
+	if user.Email != existing.Email {
+		if err := s.ensureEmailAvailable(ctx, user.Email); err != nil {
+			return err
+		}
+	}
+
+	if user.Password == "" {
+		user.Password = existing.Password
+	} else {
+		hash, err := s.hasher.Hash(user.Password)
+		if err != nil {
+			return err
+		}
+		user.Password = hash
 	}
 
-	return s.repo.Create(user)
+	return s.repo.Update(ctx, user)
 }
 
-func (s *UserService) GetUserByID(id int) (models.User, error) {
-	return s.repo.GetById(id)
+func (s *UserService) DeleteUser(ctx context.Context, id int) error {
+	if id <= 0 {
+		return apperrors.Invalid("id", "invalid user ID")
+	}
+	return s.repo.Delete(ctx, id)
 }
 
-func (s *UserService) GetAllUsers() ([]models.User, error) {
-	return s.repo.GetAll()
+// VerifyCredentials checks an email/password pair and returns the matching
+// user. Hashes made with an outdated cost are upgraded transparently.
+func (s *UserService) VerifyCredentials(ctx context.Context, email, password string) (models.User, error) {
+	user, err := s.repo.GetByEmail(ctx, email)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		_, _ = s.hasher.Verify(dummyHash, password)
+		return models.User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+
+	ok, err := s.hasher.Verify(user.Password, password)
+	if err != nil {
+		return models.User{}, err
+	}
+	if !ok {
+		return models.User{}, ErrInvalidCredentials
+	}
+
+	if s.hasher.NeedsRehash(user.Password) {
+		// A failed upgrade must not block the login, the old hash stays valid
+		if hash, err := s.hasher.Hash(password); err != nil {
+			log.Printf("failed to rehash password for user %d: %v", user.ID, err)
+		} else {
+			user.Password = hash
+			if err := s.repo.Update(ctx, user); err != nil {
+				log.Printf("failed to store rehashed password for user %d: %v", user.ID, err)
+			}
+		}
+	}
+
+	return user, nil
 }
 
-func (s *UserService) DeleteUser(id int) error {
-	return s.repo.Delete(id)
+// ensureEmailAvailable returns a conflict error when another user already has the email
+func (s *UserService) ensureEmailAvailable(ctx context.Context, email string) error {
+	_, err := s.repo.GetByEmail(ctx, email)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return apperrors.Conflict("user already exists")
 }
@@ -1,14 +1,19 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
+	"workout-api/internal/apperrors"
 	"workout-api/internal/models"
+	"workout-api/internal/pagination"
 	"workout-api/internal/repository"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // Mock UserRepository that implements repository.UserRepositoryInterface
@@ -16,61 +21,97 @@ type MockUserRepository struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) Create(user models.User) error {
-	args := m.Called(user)
+func (m *MockUserRepository) Create(ctx context.Context, user models.User) error {
+	args := m.Called(ctx, user)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) GetById(id int) (models.User, error) {
-	args := m.Called(id)
+func (m *MockUserRepository) GetById(ctx context.Context, id int) (models.User, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(models.User), args.Error(1)
 }
 
-func (m *MockUserRepository) GetByEmail(email string) (models.User, error) {
-	args := m.Called(email)
+func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	args := m.Called(ctx, email)
 	return args.Get(0).(models.User), args.Error(1)
 }
 
-func (m *MockUserRepository) GetAll() ([]models.User, error) {
-	args := m.Called()
-	return args.Get(0).([]models.User), args.Error(1)
+func (m *MockUserRepository) List(ctx context.Context, filter models.UserFilter, params pagination.Params) (pagination.Page[models.User], error) {
+	args := m.Called(ctx, filter, params)
+	return args.Get(0).(pagination.Page[models.User]), args.Error(1)
 }
 
-func (m *MockUserRepository) Update(user models.User) error {
-	args := m.Called(user)
+func (m *MockUserRepository) Update(ctx context.Context, user models.User) error {
+	args := m.Called(ctx, user)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) Delete(id int) error {
-	args := m.Called(id)
+func (m *MockUserRepository) SetRole(ctx context.Context, id int, role string) error {
+	args := m.Called(ctx, id, role)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
 // Ensure MockUserRepository implements the interface
 var _ repository.UserRepositoryInterface = (*MockUserRepository)(nil)
 
+// newTestUserService uses the minimum bcrypt cost to keep the tests fast
+func newTestUserService(repo repository.UserRepositoryInterface) *UserService {
+	return NewUserService(repo, NewPasswordHasher(bcrypt.MinCost))
+}
+
+func mustHash(t *testing.T, password string, cost int) string {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	assert.NoError(t, err)
+	return string(hash)
+}
+
 func TestUserService_CreateUser(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockUserRepository)
-	service := NewUserService(mockRepo)
+	service := newTestUserService(mockRepo)
 
 	user := models.User{
 		Name:     "John Doe",
 		Email:    "john@example.com",
-		Password: "hashedpassword",
+		Password: "plaintext",
 	}
 
 	// Mock that user doesn't exist
-	mockRepo.On("GetByEmail", user.Email).Return(models.User{}, nil)
-	mockRepo.On("Create", user).Return(nil)
-
-	err := service.CreateUser(user)
+	mockRepo.On("GetByEmail", mock.Anything, user.Email).Return(models.User{}, apperrors.NotFound("user", user.Email))
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(u models.User) bool {
+		return u.Name == user.Name && u.Email == user.Email &&
+			u.Password != "plaintext" &&
+			bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("plaintext")) == nil
+	})).Return(nil)
+
+	err := service.CreateUser(ctx, user)
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUserService_CreateUser_PasswordTooLong(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := newTestUserService(mockRepo)
+
+	// 37 two-byte runes pass a 72 character limit but not bcrypt's 72 bytes
+	user := models.User{Name: "John Doe", Email: "john@example.com", Password: strings.Repeat("é", 37)}
+	mockRepo.On("GetByEmail", mock.Anything, user.Email).Return(models.User{}, apperrors.NotFound("user", user.Email))
+
+	err := service.CreateUser(ctx, user)
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
 func TestUserService_CreateUser_UserAlreadyExists(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockUserRepository)
-	service := NewUserService(mockRepo)
+	service := newTestUserService(mockRepo)
 
 	user := models.User{
 		Name:     "John Doe",
@@ -85,17 +126,18 @@ func TestUserService_CreateUser_UserAlreadyExists(t *testing.T) {
 	}
 
 	// Mock that user already exists
-	mockRepo.On("GetByEmail", user.Email).Return(existingUser, nil)
+	mockRepo.On("GetByEmail", mock.Anything, user.Email).Return(existingUser, nil)
 
-	err := service.CreateUser(user)
-	assert.Error(t, err)
+	err := service.CreateUser(ctx, user)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
 	assert.Contains(t, err.Error(), "user already exists")
 	mockRepo.AssertExpectations(t)
 }
 
 func TestUserService_CreateUser_GetByEmailError(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockUserRepository)
-	service := NewUserService(mockRepo)
+	service := newTestUserService(mockRepo)
 
 	user := models.User{
 		Name:     "John Doe",
@@ -104,17 +146,18 @@ func TestUserService_CreateUser_GetByEmailError(t *testing.T) {
 	}
 
 	// Mock database error during GetByEmail
-	mockRepo.On("GetByEmail", user.Email).Return(models.User{}, errors.New("database error"))
+	mockRepo.On("GetByEmail", mock.Anything, user.Email).Return(models.User{}, errors.New("database error"))
 
-	err := service.CreateUser(user)
+	err := service.CreateUser(ctx, user)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "database error")
 	mockRepo.AssertExpectations(t)
 }
 
 func TestUserService_GetUserByID(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockUserRepository)
-	service := NewUserService(mockRepo)
+	service := newTestUserService(mockRepo)
 
 	expectedUser := models.User{
 		ID:        1,
@@ -125,77 +168,225 @@ func TestUserService_GetUserByID(t *testing.T) {
 		UpdatedAt: time.Now(),
 	}
 
-	mockRepo.On("GetById", 1).Return(expectedUser, nil)
+	mockRepo.On("GetById", mock.Anything, 1).Return(expectedUser, nil)
 
-	user, err := service.GetUserByID(1)
+	user, err := service.GetUserByID(ctx, 1)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser, user)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestUserService_GetUserByID_Error(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockUserRepository)
-	service := NewUserService(mockRepo)
+	service := newTestUserService(mockRepo)
 
-	mockRepo.On("GetById", 999).Return(models.User{}, errors.New("user not found"))
+	mockRepo.On("GetById", mock.Anything, 999).Return(models.User{}, apperrors.NotFound("user", 999))
 
-	user, err := service.GetUserByID(999)
-	assert.Error(t, err)
+	user, err := service.GetUserByID(ctx, 999)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
 	assert.Equal(t, models.User{}, user)
-	assert.Contains(t, err.Error(), "user not found")
+	assert.Contains(t, err.Error(), "user 999 not found")
 	mockRepo.AssertExpectations(t)
 }
 
-func TestUserService_GetAllUsers(t *testing.T) {
+func TestUserService_ListUsers(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockUserRepository)
-	service := NewUserService(mockRepo)
-
-	expectedUsers := []models.User{
-		{ID: 1, Name: "John Doe", Email: "john@example.com"},
-		{ID: 2, Name: "Jane Smith", Email: "jane@example.com"},
+	service := newTestUserService(mockRepo)
+
+	expectedPage := pagination.Page[models.User]{
+		Items: []models.User{
+			{ID: 1, Name: "John Doe", Email: "john@example.com"},
+			{ID: 2, Name: "Jane Smith", Email: "jane@example.com"},
+		},
+		Limit: 2,
 	}
+	filter := models.UserFilter{Name: "j"}
 
-	mockRepo.On("GetAll").Return(expectedUsers, nil)
+	mockRepo.On("List", mock.Anything, filter, pagination.Params{Limit: 2, Sort: "name", Desc: true}).Return(expectedPage, nil)
 
-	users, err := service.GetAllUsers()
+	page, err := service.ListUsers(ctx, filter, pagination.Query{Limit: "2", Sort: "-name"})
 	assert.NoError(t, err)
-	assert.Len(t, users, 2)
-	assert.Equal(t, expectedUsers, users)
+	assert.Equal(t, expectedPage, page)
 	mockRepo.AssertExpectations(t)
 }
 
-func TestUserService_GetAllUsers_Error(t *testing.T) {
+func TestUserService_ListUsers_InvalidSort(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := newTestUserService(mockRepo)
+
+	_, err := service.ListUsers(ctx, models.UserFilter{}, pagination.Query{Sort: "password"})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	mockRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUserService_ListUsers_Error(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockUserRepository)
-	service := NewUserService(mockRepo)
+	service := newTestUserService(mockRepo)
 
-	mockRepo.On("GetAll").Return([]models.User{}, errors.New("database connection failed"))
+	mockRepo.On("List", mock.Anything, models.UserFilter{}, mock.Anything).Return(pagination.Page[models.User]{}, errors.New("database connection failed"))
 
-	users, err := service.GetAllUsers()
+	page, err := service.ListUsers(ctx, models.UserFilter{}, pagination.Query{})
 	assert.Error(t, err)
-	assert.Empty(t, users)
+	assert.Empty(t, page.Items)
 	assert.Contains(t, err.Error(), "database connection failed")
 	mockRepo.AssertExpectations(t)
 }
 
 func TestUserService_DeleteUser(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockUserRepository)
-	service := NewUserService(mockRepo)
+	service := newTestUserService(mockRepo)
 
-	mockRepo.On("Delete", 1).Return(nil)
+	mockRepo.On("Delete", mock.Anything, 1).Return(nil)
 
-	err := service.DeleteUser(1)
+	err := service.DeleteUser(ctx, 1)
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestUserService_DeleteUser_Error(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := new(MockUserRepository)
-	service := NewUserService(mockRepo)
+	service := newTestUserService(mockRepo)
 
-	mockRepo.On("Delete", 999).Return(errors.New("user not found"))
+	mockRepo.On("Delete", mock.Anything, 999).Return(apperrors.NotFound("user", 999))
 
-	err := service.DeleteUser(999)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "user not found")
+	err := service.DeleteUser(ctx, 999)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	assert.Contains(t, err.Error(), "user 999 not found")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_UpdateUser_HashesNewPassword(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := newTestUserService(mockRepo)
+
+	existing := models.User{ID: 1, Name: "John Doe", Email: "john@example.com", Password: "oldhash"}
+	mockRepo.On("GetById", mock.Anything, 1).Return(existing, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(u models.User) bool {
+		return u.ID == 1 && bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("newpassword")) == nil
+	})).Return(nil)
+
+	err := service.UpdateUser(ctx, models.User{ID: 1, Name: "John Doe", Email: "john@example.com", Password: "newpassword"})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_UpdateUser_PasswordTooLong(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := newTestUserService(mockRepo)
+
+	existing := models.User{ID: 1, Name: "John Doe", Email: "john@example.com", Password: "oldhash"}
+	mockRepo.On("GetById", mock.Anything, 1).Return(existing, nil)
+
+	err := service.UpdateUser(ctx, models.User{ID: 1, Name: "John Doe", Email: "john@example.com", Password: strings.Repeat("a", 73)})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUserService_UpdateUser_KeepsPasswordWhenEmpty(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := newTestUserService(mockRepo)
+
+	existing := models.User{ID: 1, Name: "John Doe", Email: "john@example.com", Password: "oldhash"}
+	mockRepo.On("GetById", mock.Anything, 1).Return(existing, nil)
+	mockRepo.On("Update", mock.Anything, models.User{ID: 1, Name: "John Updated", Email: "john@example.com", Password: "oldhash"}).Return(nil)
+
+	err := service.UpdateUser(ctx, models.User{ID: 1, Name: "John Updated", Email: "john@example.com"})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_VerifyCredentials(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := newTestUserService(mockRepo)
+
+	stored := models.User{ID: 1, Email: "john@example.com", Password: mustHash(t, "secret", bcrypt.MinCost)}
+	mockRepo.On("GetByEmail", mock.Anything, "john@example.com").Return(stored, nil)
+
+	user, err := service.VerifyCredentials(ctx, "john@example.com", "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, user.ID)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUserService_VerifyCredentials_WrongPassword(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := newTestUserService(mockRepo)
+
+	stored := models.User{ID: 1, Email: "john@example.com", Password: mustHash(t, "secret", bcrypt.MinCost)}
+	mockRepo.On("GetByEmail", mock.Anything, "john@example.com").Return(stored, nil)
+
+	_, err := service.VerifyCredentials(ctx, "john@example.com", "wrong")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestUserService_VerifyCredentials_UnknownEmail(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := newTestUserService(mockRepo)
+
+	mockRepo.On("GetByEmail", mock.Anything, "nobody@example.com").Return(models.User{}, apperrors.NotFound("user", "nobody@example.com"))
+
+	_, err := service.VerifyCredentials(ctx, "nobody@example.com", "secret")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestUserService_VerifyCredentials_RehashesOutdatedCost(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := newTestUserService(mockRepo)
+
+	stored := models.User{ID: 1, Email: "john@example.com", Password: mustHash(t, "secret", bcrypt.MinCost+1)}
+	mockRepo.On("GetByEmail", mock.Anything, "john@example.com").Return(stored, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(u models.User) bool {
+		cost, err := bcrypt.Cost([]byte(u.Password))
+		return err == nil && cost == bcrypt.MinCost
+	})).Return(nil)
+
+	_, err := service.VerifyCredentials(ctx, "john@example.com", "secret")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_VerifyCredentials_UpgradesLegacyPlaintext(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := newTestUserService(mockRepo)
+
+	stored := models.User{ID: 1, Email: "john@example.com", Password: "secret"}
+	mockRepo.On("GetByEmail", mock.Anything, "john@example.com").Return(stored, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(u models.User) bool {
+		return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("secret")) == nil
+	})).Return(nil)
+
+	_, err := service.VerifyCredentials(ctx, "john@example.com", "secret")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_SetUserRole(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := newTestUserService(mockRepo)
+
+	mockRepo.On("GetByEmail", mock.Anything, "john@example.com").Return(models.User{ID: 1, Email: "john@example.com", Role: models.RoleUser}, nil)
+	mockRepo.On("SetRole", mock.Anything, 1, models.RoleAdmin).Return(nil)
+
+	user, err := service.SetUserRole(ctx, "john@example.com", models.RoleAdmin)
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, user.Role)
 	mockRepo.AssertExpectations(t)
+
+	_, err = service.SetUserRole(ctx, "john@example.com", "root")
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
 }
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+	"workout-api/internal/repository"
+)
+
+// Plausible heart rates in beats per minute
+const (
+	minHeartRate = 20
+	maxHeartRate = 250
+)
+
+// Largest set measurements the workout_sets columns can store. Weight,
+// distance and elevation are NUMERIC(7, 2), NUMERIC(10, 2) and
+// NUMERIC(7, 1), so their limits are exclusive.
+const (
+	maxSetWeight    = 1e5
+	maxSetDistance  = 1e8
+	maxSetElevation = 1e6
+	maxSetPower     = math.MaxInt16
+	maxSetInteger   = math.MaxInt32
+)
+
+type WorkoutService struct {
+	repo         repository.WorkoutRepositoryInterface
+	userRepo     repository.UserRepositoryInterface
+	exerciseRepo repository.ExerciseRepositoryInterface
+	recordRepo   repository.PersonalRecordRepositoryInterface
+}
+
+func NewWorkoutService(repo repository.WorkoutRepositoryInterface, userRepo repository.UserRepositoryInterface, exerciseRepo repository.ExerciseRepositoryInterface, recordRepo repository.PersonalRecordRepositoryInterface) *WorkoutService {
+	return &WorkoutService{repo: repo, userRepo: userRepo, exerciseRepo: exerciseRepo, recordRepo: recordRepo}
+}
+
+// StartWorkout opens a new session for a user, defaulting the start time to now
+func (s *WorkoutService) StartWorkout(ctx context.Context, workout models.Workout) (models.Workout, error) {
+	if workout.UserID <= 0 {
+		return models.Workout{}, apperrors.Invalid("user_id", "invalid user ID")
+	}
+
+	if _, err := s.userRepo.GetById(ctx, workout.UserID); err != nil {
+		return models.Workout{}, err
+	}
+
+	if workout.StartedAt.IsZero() {
+		workout.StartedAt = time.Now()
+	}
+	// Plans only come from routines, see RoutineService.StartRoutine
+	workout.FinishedAt = nil
+	workout.RoutineID = nil
+	workout.PlannedSets = nil
+
+	return s.repo.Create(ctx, workout)
+}
+
+// GetWorkoutByID returns one of the user's workouts together with all of
+// its logged sets and, for workouts started from a routine, its planned sets
+func (s *WorkoutService) GetWorkoutByID(ctx context.Context, userID, id int) (models.Workout, error) {
+	workout, err := s.ownedWorkout(ctx, userID, id)
+	if err != nil {
+		return models.Workout{}, err
+	}
+
+	sets, err := s.repo.GetSets(ctx, id)
+	if err != nil {
+		return models.Workout{}, err
+	}
+	workout.Sets = sets
+
+	// Plans outlive the routine they came from, so load them regardless
+	planned, err := s.repo.GetPlannedSets(ctx, id)
+	if err != nil {
+		return models.Workout{}, err
+	}
+	workout.PlannedSets = planned
+
+	return workout, nil
+}
+
+// ListWorkouts returns one page of a user's workouts matching filter
+func (s *WorkoutService) ListWorkouts(ctx context.Context, userID int, filter models.WorkoutFilter, query pagination.Query) (pagination.Page[models.Workout], error) {
+	if userID <= 0 {
+		return pagination.Page[models.Workout]{}, apperrors.Invalid("user_id", "invalid user ID")
+	}
+	if filter.StartedFrom != nil && filter.StartedTo != nil && !filter.StartedTo.After(*filter.StartedFrom) {
+		return pagination.Page[models.Workout]{}, apperrors.Invalid("to", "to must be after from")
+	}
+
+	params, err := repository.WorkoutListSpec.Parse(query)
+	if err != nil {
+		return pagination.Page[models.Workout]{}, err
+	}
+	return s.repo.ListByUser(ctx, userID, filter, params)
+}
+
+// LogSet records a set against an exercise from the catalog in an unfinished
+// workout. Any personal records the set breaks are saved and returned with it.
+func (s *WorkoutService) LogSet(ctx context.Context, userID int, set models.WorkoutSet) (models.WorkoutSet, error) {
+	if err := validateSet(set); err != nil {
+		return models.WorkoutSet{}, err
+	}
+
+	workout, err := s.openWorkout(ctx, userID, set.WorkoutID)
+	if err != nil {
+		return models.WorkoutSet{}, err
+	}
+	set.WorkoutID = workout.ID
+
+	// A missing exercise is a problem with the request body, not the URL.
+	// Other users' private exercises count as missing.
+	exercise, err := s.exerciseRepo.GetById(ctx, set.ExerciseID)
+	if errors.Is(err, apperrors.ErrNotFound) || (err == nil && !exercise.VisibleTo(models.Viewer{UserID: userID})) {
+		return models.WorkoutSet{}, apperrors.Invalid("exercise_id", "exercise not found")
+	}
+	if err != nil {
+		return models.WorkoutSet{}, err
+	}
+	if fields := validateTrackedSet(exercise.TrackingType, set); len(fields) > 0 {
+		return models.WorkoutSet{}, apperrors.Validation(fields...)
+	}
+
+	set, err = s.repo.AddSet(ctx, set)
+	if err != nil {
+		return models.WorkoutSet{}, err
+	}
+
+	// The set is already stored, so a failed check must not fail the request
+	records, err := saveRecords(ctx, s.recordRepo, userID, set, set.CreatedAt)
+	if err != nil {
+		log.Printf("failed to detect personal records for set %d: %v", set.ID, err)
+	}
+	set.Records = records
+
+	return set, nil
+}
+
+func (s *WorkoutService) DeleteSet(ctx context.Context, userID, workoutID, setID int) error {
+	if setID <= 0 {
+		return apperrors.Invalid("set_id", "invalid set ID")
+	}
+	if _, err := s.openWorkout(ctx, userID, workoutID); err != nil {
+		return err
+	}
+	return s.repo.DeleteSet(ctx, workoutID, setID)
+}
+
+// FinishWorkout closes an open session; finished workouts no longer accept sets
+func (s *WorkoutService) FinishWorkout(ctx context.Context, userID, id int) (models.Workout, error) {
+	workout, err := s.openWorkout(ctx, userID, id)
+	if err != nil {
+		return models.Workout{}, err
+	}
+
+	finishedAt := time.Now()
+	if err := s.repo.Finish(ctx, id, finishedAt); err != nil {
+		return models.Workout{}, err
+	}
+	workout.FinishedAt = &finishedAt
+
+	return workout, nil
+}
+
+func (s *WorkoutService) DeleteWorkout(ctx context.Context, userID, id int) error {
+	if _, err := s.ownedWorkout(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// ownedWorkout loads a workout belonging to the user
+func (s *WorkoutService) ownedWorkout(ctx context.Context, userID, id int) (models.Workout, error) {
+	if id <= 0 {
+		return models.Workout{}, apperrors.Invalid("id", "invalid workout ID")
+	}
+
+	workout, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return models.Workout{}, err
+	}
+	// Other users' workouts are indistinguishable from missing ones
+	if workout.UserID != userID {
+		return models.Workout{}, apperrors.NotFound("workout", id)
+	}
+
+	return workout, nil
+}
+
+// openWorkout loads a workout belonging to the user and ensures it can still be modified
+func (s *WorkoutService) openWorkout(ctx context.Context, userID, id int) (models.Workout, error) {
+	workout, err := s.ownedWorkout(ctx, userID, id)
+	if err != nil {
+		return models.Workout{}, err
+	}
+	if workout.FinishedAt != nil {
+		return models.Workout{}, apperrors.Conflict("workout is already finished")
+	}
+
+	return workout, nil
+}
+
+// validateSet checks the measurements any set may carry. What a set must
+// carry depends on its exercise, see validateTrackedSet.
+func validateSet(set models.WorkoutSet) error {
+	var fields []apperrors.FieldError
+	if set.ExerciseID <= 0 {
+		fields = append(fields, apperrors.FieldError{Field: "exercise_id", Message: "invalid exercise ID"})
+	}
+	if set.Reps < 0 {
+		fields = append(fields, apperrors.FieldError{Field: "reps", Message: "reps cannot be negative"})
+	} else if set.Reps > maxSetInteger {
+		fields = append(fields, apperrors.FieldError{Field: "reps", Message: fmt.Sprintf("reps cannot exceed %d", maxSetInteger)})
+	}
+	if set.Weight < 0 {
+		fields = append(fields, apperrors.FieldError{Field: "weight", Message: "weight cannot be negative"})
+	} else if set.Weight >= maxSetWeight {
+		fields = append(fields, apperrors.FieldError{Field: "weight", Message: fmt.Sprintf("weight must be less than %.0f", maxSetWeight)})
+	}
+	if set.RPE != nil && (*set.RPE < 1 || *set.RPE > 10) {
+		fields = append(fields, apperrors.FieldError{Field: "rpe", Message: "RPE must be between 1 and 10"})
+	}
+	if set.RestSeconds != nil && *set.RestSeconds < 0 {
+		fields = append(fields, apperrors.FieldError{Field: "rest_seconds", Message: "rest seconds cannot be negative"})
+	} else if set.RestSeconds != nil && *set.RestSeconds > maxSetInteger {
+		fields = append(fields, apperrors.FieldError{Field: "rest_seconds", Message: fmt.Sprintf("rest seconds cannot exceed %d", maxSetInteger)})
+	}
+	if set.DurationSeconds != nil && *set.DurationSeconds <= 0 {
+		fields = append(fields, apperrors.FieldError{Field: "duration_seconds", Message: "duration must be greater than zero"})
+	} else if set.DurationSeconds != nil && *set.DurationSeconds > maxSetInteger {
+		fields = append(fields, apperrors.FieldError{Field: "duration_seconds", Message: fmt.Sprintf("duration cannot exceed %d seconds", maxSetInteger)})
+	}
+	if set.DistanceMeters != nil && *set.DistanceMeters <= 0 {
+		fields = append(fields, apperrors.FieldError{Field: "distance_meters", Message: "distance must be greater than zero"})
+	} else if set.DistanceMeters != nil && *set.DistanceMeters >= maxSetDistance {
+		fields = append(fields, apperrors.FieldError{Field: "distance_meters", Message: fmt.Sprintf("distance must be less than %.0f meters", maxSetDistance)})
+	}
+	for _, hr := range []struct {
+		field string
+		value *int
+	}{{"avg_heart_rate", set.AvgHeartRate}, {"max_heart_rate", set.MaxHeartRate}} {
+		if hr.value != nil && (*hr.value < minHeartRate || *hr.value > maxHeartRate) {
+			fields = append(fields, apperrors.FieldError{Field: hr.field, Message: fmt.Sprintf("heart rate must be between %d and %d", minHeartRate, maxHeartRate)})
+		}
+	}
+	if set.AvgHeartRate != nil && set.MaxHeartRate != nil && *set.AvgHeartRate > *set.MaxHeartRate {
+		fields = append(fields, apperrors.FieldError{Field: "avg_heart_rate", Message: "average heart rate cannot exceed the maximum"})
+	}
+	if set.ElevationGain != nil && *set.ElevationGain < 0 {
+		fields = append(fields, apperrors.FieldError{Field: "elevation_gain_meters", Message: "elevation gain cannot be negative"})
+	} else if set.ElevationGain != nil && *set.ElevationGain >= maxSetElevation {
+		fields = append(fields, apperrors.FieldError{Field: "elevation_gain_meters", Message: fmt.Sprintf("elevation gain must be less than %.0f meters", maxSetElevation)})
+	}
+	if set.AvgPowerWatts != nil && *set.AvgPowerWatts < 0 {
+		fields = append(fields, apperrors.FieldError{Field: "avg_power_watts", Message: "power cannot be negative"})
+	} else if set.AvgPowerWatts != nil && *set.AvgPowerWatts > maxSetPower {
+		fields = append(fields, apperrors.FieldError{Field: "avg_power_watts", Message: fmt.Sprintf("power cannot exceed %d watts", maxSetPower)})
+	}
+	if set.Calories != nil && *set.Calories < 0 {
+		fields = append(fields, apperrors.FieldError{Field: "calories", Message: "calories cannot be negative"})
+	} else if set.Calories != nil && *set.Calories > maxSetInteger {
+		fields = append(fields, apperrors.FieldError{Field: "calories", Message: fmt.Sprintf("calories cannot exceed %d", maxSetInteger)})
+	}
+	if len(fields) > 0 {
+		return apperrors.Validation(fields...)
+	}
+	return nil
+}
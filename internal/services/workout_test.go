@@ -0,0 +1,424 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"workout-api/internal/apperrors"
+	"workout-api/internal/models"
+	"workout-api/internal/pagination"
+	"workout-api/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock WorkoutRepository that implements repository.WorkoutRepositoryInterface
+type MockWorkoutRepository struct {
+	mock.Mock
+}
+
+func (m *MockWorkoutRepository) Create(ctx context.Context, workout models.Workout) (models.Workout, error) {
+	args := m.Called(ctx, workout)
+	return args.Get(0).(models.Workout), args.Error(1)
+}
+
+func (m *MockWorkoutRepository) Import(ctx context.Context, workout models.Workout) (models.Workout, bool, error) {
+	args := m.Called(ctx, workout)
+	return args.Get(0).(models.Workout), args.Bool(1), args.Error(2)
+}
+
+func (m *MockWorkoutRepository) GetById(ctx context.Context, id int) (models.Workout, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.Workout), args.Error(1)
+}
+
+func (m *MockWorkoutRepository) ListByUser(ctx context.Context, userID int, filter models.WorkoutFilter, params pagination.Params) (pagination.Page[models.Workout], error) {
+	args := m.Called(ctx, userID, filter, params)
+	return args.Get(0).(pagination.Page[models.Workout]), args.Error(1)
+}
+
+func (m *MockWorkoutRepository) ListAll(ctx context.Context, userID int) ([]models.Workout, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.Workout), args.Error(1)
+}
+
+func (m *MockWorkoutRepository) Finish(ctx context.Context, id int, finishedAt time.Time) error {
+	args := m.Called(ctx, id, finishedAt)
+	return args.Error(0)
+}
+
+func (m *MockWorkoutRepository) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWorkoutRepository) AddSet(ctx context.Context, set models.WorkoutSet) (models.WorkoutSet, error) {
+	args := m.Called(ctx, set)
+	return args.Get(0).(models.WorkoutSet), args.Error(1)
+}
+
+func (m *MockWorkoutRepository) GetSets(ctx context.Context, workoutID int) ([]models.WorkoutSet, error) {
+	args := m.Called(ctx, workoutID)
+	return args.Get(0).([]models.WorkoutSet), args.Error(1)
+}
+
+func (m *MockWorkoutRepository) DeleteSet(ctx context.Context, workoutID, setID int) error {
+	args := m.Called(ctx, workoutID, setID)
+	return args.Error(0)
+}
+
+func (m *MockWorkoutRepository) GetPlannedSets(ctx context.Context, workoutID int) ([]models.PlannedSet, error) {
+	args := m.Called(ctx, workoutID)
+	return args.Get(0).([]models.PlannedSet), args.Error(1)
+}
+
+func (m *MockWorkoutRepository) LastPerformance(ctx context.Context, userID int, exerciseIDs []int) (map[int][]models.WorkoutSet, error) {
+	args := m.Called(ctx, userID, exerciseIDs)
+	return args.Get(0).(map[int][]models.WorkoutSet), args.Error(1)
+}
+
+// Ensure MockWorkoutRepository implements the interface
+var _ repository.WorkoutRepositoryInterface = (*MockWorkoutRepository)(nil)
+
+func newTestWorkoutService() (*WorkoutService, *MockWorkoutRepository, *MockUserRepository, *MockExerciseRepository, *MockPersonalRecordRepository) {
+	workoutRepo := new(MockWorkoutRepository)
+	userRepo := new(MockUserRepository)
+	exerciseRepo := new(MockExerciseRepository)
+	recordRepo := new(MockPersonalRecordRepository)
+	return NewWorkoutService(workoutRepo, userRepo, exerciseRepo, recordRepo), workoutRepo, userRepo, exerciseRepo, recordRepo
+}
+
+func TestWorkoutService_StartWorkout(t *testing.T) {
+	ctx := context.Background()
+	service, workoutRepo, userRepo, _, _ := newTestWorkoutService()
+
+	userRepo.On("GetById", mock.Anything, 1).Return(models.User{ID: 1}, nil)
+	workoutRepo.On("Create", mock.Anything, mock.MatchedBy(func(w models.Workout) bool {
+		return w.UserID == 1 && w.Name == "Push Day" && !w.StartedAt.IsZero()
+	})).Return(models.Workout{ID: 5, UserID: 1, Name: "Push Day"}, nil)
+
+	workout, err := service.StartWorkout(ctx, models.Workout{UserID: 1, Name: "Push Day"})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, workout.ID)
+	workoutRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func TestWorkoutService_StartWorkout_UserNotFound(t *testing.T) {
+	ctx := context.Background()
+	service, workoutRepo, userRepo, _, _ := newTestWorkoutService()
+
+	userRepo.On("GetById", mock.Anything, 9).Return(models.User{}, apperrors.NotFound("user", 9))
+
+	_, err := service.StartWorkout(ctx, models.Workout{UserID: 9})
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	workoutRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestWorkoutService_GetWorkoutByID(t *testing.T) {
+	ctx := context.Background()
+	service, workoutRepo, _, _, _ := newTestWorkoutService()
+
+	sets := []models.WorkoutSet{{ID: 1, WorkoutID: 1, ExerciseID: 2, Reps: 5, Weight: 80}}
+	workoutRepo.On("GetById", mock.Anything, 1).Return(models.Workout{ID: 1, UserID: 1}, nil)
+	workoutRepo.On("GetSets", mock.Anything, 1).Return(sets, nil)
+	workoutRepo.On("GetPlannedSets", mock.Anything, 1).Return([]models.PlannedSet{}, nil)
+
+	workout, err := service.GetWorkoutByID(ctx, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, sets, workout.Sets)
+	workoutRepo.AssertExpectations(t)
+}
+
+func TestWorkoutService_GetWorkoutByID_OtherUser(t *testing.T) {
+	ctx := context.Background()
+	service, workoutRepo, _, _, _ := newTestWorkoutService()
+
+	workoutRepo.On("GetById", mock.Anything, 1).Return(models.Workout{ID: 1, UserID: 2}, nil)
+
+	_, err := service.GetWorkoutByID(ctx, 1, 1)
+	assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	workoutRepo.AssertNotCalled(t, "GetSets", mock.Anything, mock.Anything)
+}
+
+func TestWorkoutService_ListWorkouts(t *testing.T) {
+	ctx := context.Background()
+	service, workoutRepo, _, _, _ := newTestWorkoutService()
+
+	expectedPage := pagination.Page[models.Workout]{Items: []models.Workout{{ID: 1, UserID: 7}}, Limit: 10}
+	params := pagination.Params{Limit: 10, Sort: "started_at", Desc: true}
+	workoutRepo.On("ListByUser", mock.Anything, 7, models.WorkoutFilter{}, params).Return(expectedPage, nil)
+
+	page, err := service.ListWorkouts(ctx, 7, models.WorkoutFilter{}, pagination.Query{Limit: "10"})
+	assert.NoError(t, err)
+	assert.Equal(t, expectedPage, page)
+	workoutRepo.AssertExpectations(t)
+}
+
+func TestWorkoutService_ListWorkouts_InvertedRange(t *testing.T) {
+	ctx := context.Background()
+	service, workoutRepo, _, _, _ := newTestWorkoutService()
+
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -7)
+
+	_, err := service.ListWorkouts(ctx, 7, models.WorkoutFilter{StartedFrom: &from, StartedTo: &to}, pagination.Query{})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	workoutRepo.AssertNotCalled(t, "ListByUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWorkoutService_LogSet(t *testing.T) {
+	ctx := context.Background()
+	service, workoutRepo, _, exerciseRepo, recordRepo := newTestWorkoutService()
+
+	set := models.WorkoutSet{WorkoutID: 1, ExerciseID: 2, Reps: 8, Weight: 60}
+	stored := models.WorkoutSet{ID: 3, WorkoutID: 1, ExerciseID: 2, SetNumber: 1, Reps: 8, Weight: 60}
+	workoutRepo.On("GetById", mock.Anything, 1).Return(models.Workout{ID: 1, UserID: 1}, nil)
+	exerciseRepo.On("GetById", mock.Anything, 2).Return(models.Exercise{ID: 2, Name: "Bench Press"}, nil)
+	workoutRepo.On("AddSet", mock.Anything, set).Return(stored, nil)
+	recordRepo.On("PreviousBests", mock.Anything, 1, stored).Return(models.RecordBests{}, nil)
+
+	logged, err := service.LogSet(ctx, 1, set)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, logged.ID)
+	assert.Equal(t, 1, logged.SetNumber)
+	assert.Empty(t, logged.Records)
+	workoutRepo.AssertExpectations(t)
+	exerciseRepo.AssertExpectations(t)
+	recordRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestWorkoutService_LogSet_Records(t *testing.T) {
+	ctx := context.Background()
+	service, workoutRepo, _, exerciseRepo, recordRepo := newTestWorkoutService()
+
+	set := models.WorkoutSet{WorkoutID: 1, ExerciseID: 2, Reps: 5, Weight: 100}
+	stored := models.WorkoutSet{ID: 3, WorkoutID: 1, ExerciseID: 2, SetNumber: 1, Reps: 5, Weight: 100, CreatedAt: time.Now()}
+	workoutRepo.On("GetById", mock.Anything, 1).Return(models.Workout{ID: 1, UserID: 1}, nil)
+	exerciseRepo.On("GetById", mock.Anything, 2).Return(models.Exercise{ID: 2}, nil)
+	workoutRepo.On("AddSet", mock.Anything, set).Return(stored, nil)
+	recordRepo.On("PreviousBests", mock.Anything, 1, stored).Return(models.RecordBests{Weight: 95, E1RM: 120, SessionVolume: 2000, CurrentVolume: 500}, nil)
+	recordRepo.On("Create", mock.Anything, mock.MatchedBy(func(records []models.PersonalRecord) bool {
+		return len(records) == 1 && records[0].Category == models.RecordHeaviestWeight && records[0].SetID == 3
+	})).Return([]models.PersonalRecord{{ID: 9, SetID: 3, Category: models.RecordHeaviestWeight, Value: 100, Previous: 95}}, nil)
+
+	logged, err := service.LogSet(ctx, 1, set)
+	assert.NoError(t, err)
+	assert.Len(t, logged.Records, 1)
+	assert.Equal(t, 9, logged.Records[0].ID)
+	recordRepo.AssertExpectations(t)
+}
+
+func TestWorkoutService_LogSet_RecordDetectionFailure(t *testing.T) {
+	ctx := context.Background()
+	service, workoutRepo, _, exerciseRepo, recordRepo := newTestWorkoutService()
+
+	set := models.WorkoutSet{WorkoutID: 1, ExerciseID: 2, Reps: 5, Weight: 100}
+	stored := models.WorkoutSet{ID: 3, WorkoutID: 1, ExerciseID: 2, SetNumber: 1, Reps: 5, Weight: 100}
+	workoutRepo.On("GetById", mock.Anything, 1).Return(models.Workout{ID: 1, UserID: 1}, nil)
+	exerciseRepo.On("GetById", mock.Anything, 2).Return(models.Exercise{ID: 2}, nil)
+	workoutRepo.On("AddSet", mock.Anything, set).Return(stored, nil)
+	recordRepo.On("PreviousBests", mock.Anything, 1, stored).Return(models.RecordBests{}, errors.New("connection reset"))
+
+	logged, err := service.LogSet(ctx, 1, set)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, logged.ID)
+	assert.Nil(t, logged.Records)
+}
+
+func TestWorkoutService_LogSet_ValidationErrors(t *testing.T) {
+	ctx := context.Background()
+	service, workoutRepo, _, exerciseRepo, _ := newTestWorkoutService()
+
+	workoutRepo.On("GetById", mock.Anything, 1).Return(models.Workout{ID: 1, UserID: 1}, nil)
+	exerciseRepo.On("GetById", mock.Anything, 2).Return(models.Exercise{ID: 2, TrackingType: models.TrackingWeightReps}, nil)
+
+	_, err := service.LogSet(ctx, 1, models.WorkoutSet{WorkoutID: 1, ExerciseID: 2, Reps: 0})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "reps must be greater than zero")
+
+	_, err = service.LogSet(ctx, 1, models.WorkoutSet{WorkoutID: 1, ExerciseID: 2, Reps: 5, Weight: -1})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "weight cannot be negative")
+
+	rpe := 11.0
+	_, err = service.LogSet(ctx, 1, models.WorkoutSet{WorkoutID: 1, ExerciseID: 2, Reps: 5, RPE: &rpe})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "RPE must be between 1 and 10")
+}
+
+func TestWorkoutService_LogSet_Cardio(t *testing.T) {
+	ctx := context.Background()
+	service, workoutRepo, _, exerciseRepo, recordRepo := newTestWorkoutService()
+
+	distance := 5000.0
+	duration := 1500
+	avgHR, maxHR := 152, 171
+	set := models.WorkoutSet{WorkoutID: 1, ExerciseID: 8, DistanceMeters: &distance, DurationSeconds: &duration, AvgHeartRate: &avgHR, MaxHeartRate: &maxHR}
+	stored := set
+	stored.ID = 4
+	workoutRepo.On("GetById", mock.Anything, 1).Return(models.Workout{ID: 1, UserID: 1}, nil)
+	exerciseRepo.On("GetById", mock.Anything, 8).Return(models.Exercise{ID: 8, TrackingType: models.TrackingDistanceDuration}, nil)
+	workoutRepo.On("AddSet", mock.Anything, set).Return(stored, nil)
+	recordRepo.On("PreviousBests", mock.Anything, 1, stored).Return(models.RecordBests{}, nil)
+
+	logged, err := service.LogSet(ctx, 1, set)
+	assert.NoError(t, err)
+	assert.Equal(t, 300.0, *logged.Pace())
+	assert.Equal(t, 12.0, *logged.Speed())
+	workoutRepo.AssertExpectations(t)
+}
+
+func TestWorkoutService_LogSet_TrackingMismatch(t *testing.T) {
+	ctx := context.Background()
+	service, workoutRepo, _, exerciseRepo, _ := newTestWorkoutService()
+
+	duration := 1500
+	avgHR, maxHR := 180, 160
+	workoutRepo.On("GetById", mock.Anything, 1).Return(models.Workout{ID: 1, UserID: 1}, nil)
+	exerciseRepo.On("GetById", mock.Anything, 8).Return(models.Exercise{ID: 8, TrackingType: models.TrackingDistanceDuration}, nil)
+
+	// Measurements any set may carry are checked before the exercise is loaded
+	_, err := service.LogSet(ctx, 1, models.WorkoutSet{WorkoutID: 1, ExerciseID: 8, DurationSeconds: &duration, AvgHeartRate: &avgHR, MaxHeartRate: &maxHR})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Contains(t, err.Error(), "average heart rate cannot exceed the maximum")
+
+	_, err = service.LogSet(ctx, 1, models.WorkoutSet{WorkoutID: 1, ExerciseID: 8, Reps: 10, DurationSeconds: &duration})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+
+	var appErr *apperrors.Error
+	assert.ErrorAs(t, err, &appErr)
+	fields := map[string]bool{}
+	for _, f := range appErr.Fields {
+		fields[f.Field] = true
+	}
+	assert.True(t, fields["distance_meters"])
+	assert.True(t, fields["reps"])
+	workoutRepo.AssertNotCalled(t, "AddSet", mock.Anything, mock.Anything)
+}
+
+func TestValidateSet_ColumnLimits(t *testing.T) {
+	duration, calories, power := maxSetInteger+1, maxSetInteger+1, maxSetPower+1
+	distance, elevation := 1e8, 1e6
+	err := validateSet(models.WorkoutSet{ExerciseID: 8, DurationSeconds: &duration, DistanceMeters: &distance,
+		ElevationGain: &elevation, AvgPowerWatts: &power, Calories: &calories})
+
+	var appErr *apperrors.Error
+	if assert.ErrorAs(t, err, &appErr) {
+		fields := make([]string, 0, len(appErr.Fields))
+		for _, f := range appErr.Fields {
+			fields = append(fields, f.Field)
+		}
+		assert.Equal(t, []string{"duration_seconds", "distance_meters", "elevation_gain_meters", "avg_power_watts", "calories"}, fields)
+	}
+
+	duration, calories, power = maxSetInteger, maxSetInteger, maxSetPower
+	distance, elevation = 99999999.99, 999999.9
+	assert.NoError(t, validateSet(models.WorkoutSet{ExerciseID: 8, DurationSeconds: &duration, DistanceMeters: &distance,
+		ElevationGain: &elevation, AvgPowerWatts: &power, Calories: &calories}))
+}
+
+func TestValidateSet_StrengthLimits(t *testing.T) {
+	rest := func(v int) *int { return &v }
+	tests := []struct {
+		name  string
+		set   models.WorkoutSet
+		field string
+	}{
+		{"weight at limit", models.WorkoutSet{ExerciseID: 1, Reps: 5, Weight: maxSetWeight}, "weight"},
+		{"weight below limit", models.WorkoutSet{ExerciseID: 1, Reps: 5, Weight: 99999.99}, ""},
+		{"reps over limit", models.WorkoutSet{ExerciseID: 1, Reps: maxSetInteger + 1}, "reps"},
+		{"reps at limit", models.WorkoutSet{ExerciseID: 1, Reps: maxSetInteger}, ""},
+		{"rest over limit", models.WorkoutSet{ExerciseID: 1, Reps: 5, RestSeconds: rest(maxSetInteger + 1)}, "rest_seconds"},
+		{"rest at limit", models.WorkoutSet{ExerciseID: 1, Reps: 5, RestSeconds: rest(maxSetInteger)}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSet(tt.set)
+			if tt.field == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var appErr *apperrors.Error
+			if assert.ErrorAs(t, err, &appErr) {
+				assert.Equal(t, tt.field, appErr.Fields[0].Field)
+			}
+		})
+	}
+}
+
+func TestWorkoutService_LogSet_FinishedWorkout(t *testing.T) {
+	ctx := context.Background()
+	service, workoutRepo, _, _, _ := newTestWorkoutService()
+
+	finishedAt := time.Now()
+	workoutRepo.On("GetById", mock.Anything, 1).Return(models.Workout{ID: 1, UserID: 1, FinishedAt: &finishedAt}, nil)
+
+	_, err := service.LogSet(ctx, 1, models.WorkoutSet{WorkoutID: 1, ExerciseID: 2, Reps: 5})
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, apperrors.ErrConflict)
+	assert.Contains(t, err.Error(), "workout is already finished")
+	workoutRepo.AssertNotCalled(t, "AddSet", mock.Anything, mock.Anything)
+}
+
+func TestWorkoutService_LogSet_ExerciseNotFound(t *testing.T) {
+	ctx := context.Background()
+	service, workoutRepo, _, exerciseRepo, _ := newTestWorkoutService()
+
+	workoutRepo.On("GetById", mock.Anything, 1).Return(models.Workout{ID: 1, UserID: 1}, nil)
+	exerciseRepo.On("GetById", mock.Anything, 42).Return(models.Exercise{}, apperrors.NotFound("exercise", 42))
+
+	_, err := service.LogSet(ctx, 1, models.WorkoutSet{WorkoutID: 1, ExerciseID: 42, Reps: 5})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Contains(t, err.Error(), "exercise not found")
+	workoutRepo.AssertNotCalled(t, "AddSet", mock.Anything, mock.Anything)
+}
+
+func TestWorkoutService_LogSet_OtherUsersPrivateExercise(t *testing.T) {
+	ctx := context.Background()
+	service, workoutRepo, _, exerciseRepo, _ := newTestWorkoutService()
+
+	otherUser := 2
+	workoutRepo.On("GetById", mock.Anything, 1).Return(models.Workout{ID: 1, UserID: 1}, nil)
+	exerciseRepo.On("GetById", mock.Anything, 42).Return(models.Exercise{ID: 42, OwnerID: &otherUser, Visibility: models.VisibilityPrivate}, nil)
+
+	_, err := service.LogSet(ctx, 1, models.WorkoutSet{WorkoutID: 1, ExerciseID: 42, Reps: 5})
+	assert.ErrorIs(t, err, apperrors.ErrValidation)
+	assert.Contains(t, err.Error(), "exercise not found")
+	workoutRepo.AssertNotCalled(t, "AddSet", mock.Anything, mock.Anything)
+}
+
+func TestWorkoutService_FinishWorkout(t *testing.T) {
+	ctx := context.Background()
+	service, workoutRepo, _, _, _ := newTestWorkoutService()
+
+	workoutRepo.On("GetById", mock.Anything, 1).Return(models.Workout{ID: 1, UserID: 1}, nil)
+	workoutRepo.On("Finish", mock.Anything, 1, mock.AnythingOfType("time.Time")).Return(nil)
+
+	workout, err := service.FinishWorkout(ctx, 1, 1)
+	assert.NoError(t, err)
+	assert.NotNil(t, workout.FinishedAt)
+	workoutRepo.AssertExpectations(t)
+}
+
+func TestWorkoutService_DeleteWorkout_OtherUser(t *testing.T) {
+	ctx := context.Background()
+	service, workoutRepo, _, _, _ := newTestWorkoutService()
+
+	workoutRepo.On("GetById", mock.Anything, 1).Return(models.Workout{ID: 1, UserID: 2}, nil)
+
+	err := service.DeleteWorkout(ctx, 1, 1)
+	assert.Error(t, err)
+	workoutRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestWorkoutService_DeleteWorkout_InvalidID(t *testing.T) {
+	ctx := context.Background()
+	service, _, _, _, _ := newTestWorkoutService()
+
+	err := service.DeleteWorkout(ctx, 1, 0)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid workout ID")
+}
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_workouts_user_started_at_id;
+DROP INDEX IF EXISTS idx_exercises_created_at_id;
+DROP INDEX IF EXISTS idx_exercises_name_id;
+DROP INDEX IF EXISTS idx_users_created_at_id;
+DROP INDEX IF EXISTS idx_users_name_id;
+
+ALTER TABLE workouts ALTER COLUMN created_at DROP NOT NULL;
+ALTER TABLE exercises ALTER COLUMN created_at DROP NOT NULL;
+ALTER TABLE users ALTER COLUMN created_at DROP NOT NULL;
@@ -0,0 +1,16 @@
+-- Keyset pagination compares (sort column, id) pairs, so sort columns must
+-- never be NULL and each sortable pair gets a composite index
+UPDATE users SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+ALTER TABLE users ALTER COLUMN created_at SET NOT NULL;
+
+UPDATE exercises SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+ALTER TABLE exercises ALTER COLUMN created_at SET NOT NULL;
+
+UPDATE workouts SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+ALTER TABLE workouts ALTER COLUMN created_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_name_id ON users(name, id);
+CREATE INDEX IF NOT EXISTS idx_users_created_at_id ON users(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_exercises_name_id ON exercises(name, id);
+CREATE INDEX IF NOT EXISTS idx_exercises_created_at_id ON exercises(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_workouts_user_started_at_id ON workouts(user_id, started_at, id);
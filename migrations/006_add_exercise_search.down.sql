@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_exercises_name_trgm;
+DROP INDEX IF EXISTS idx_exercises_search_vector;
+ALTER TABLE exercises DROP COLUMN IF EXISTS search_vector;
+-- pg_trgm is left installed; other database objects may depend on it
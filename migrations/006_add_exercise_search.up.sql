@@ -0,0 +1,13 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Names weigh more than notes when ranking full-text matches
+ALTER TABLE exercises ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(notes, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_exercises_search_vector ON exercises USING GIN (search_vector);
+
+-- Trigram index backing typo-tolerant name matching
+CREATE INDEX IF NOT EXISTS idx_exercises_name_trgm ON exercises USING GIN (name gin_trgm_ops);
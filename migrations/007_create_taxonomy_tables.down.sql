@@ -0,0 +1,9 @@
+ALTER TABLE exercises DROP CONSTRAINT IF EXISTS fk_exercises_equipment;
+ALTER TABLE exercises DROP CONSTRAINT IF EXISTS fk_exercises_muscle_group;
+ALTER TABLE exercises ALTER COLUMN muscle_group DROP NOT NULL;
+
+DROP TABLE IF EXISTS exercise_muscles;
+DROP TABLE IF EXISTS equipment_aliases;
+DROP TABLE IF EXISTS equipment;
+DROP TABLE IF EXISTS muscle_group_aliases;
+DROP TABLE IF EXISTS muscle_groups;
@@ -0,0 +1,189 @@
+-- Canonical muscle groups and equipment. Free-text input is resolved through
+-- the alias tables, which store lower-cased, whitespace-collapsed spellings.
+CREATE TABLE IF NOT EXISTS muscle_groups (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(64) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    region VARCHAR(32) NOT NULL DEFAULT 'other',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS muscle_group_aliases (
+    alias VARCHAR(255) PRIMARY KEY,
+    muscle_group_id INTEGER NOT NULL REFERENCES muscle_groups(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS equipment (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(64) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS equipment_aliases (
+    alias VARCHAR(255) PRIMARY KEY,
+    equipment_id INTEGER NOT NULL REFERENCES equipment(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS exercise_muscles (
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    muscle_group_id INTEGER NOT NULL REFERENCES muscle_groups(id),
+    role VARCHAR(16) NOT NULL CHECK (role IN ('primary', 'secondary')),
+    PRIMARY KEY (exercise_id, muscle_group_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_exercise_muscles_muscle_group ON exercise_muscles(muscle_group_id, role);
+
+INSERT INTO muscle_groups (slug, name, region) VALUES
+    ('chest', 'Chest', 'upper'),
+    ('back', 'Back', 'upper'),
+    ('traps', 'Traps', 'upper'),
+    ('shoulders', 'Shoulders', 'upper'),
+    ('rear-delts', 'Rear Delts', 'upper'),
+    ('biceps', 'Biceps', 'upper'),
+    ('triceps', 'Triceps', 'upper'),
+    ('forearms', 'Forearms', 'upper'),
+    ('abs', 'Abs', 'core'),
+    ('lower-back', 'Lower Back', 'core'),
+    ('glutes', 'Glutes', 'lower'),
+    ('quads', 'Quads', 'lower'),
+    ('hamstrings', 'Hamstrings', 'lower'),
+    ('adductors', 'Adductors', 'lower'),
+    ('abductors', 'Abductors', 'lower'),
+    ('calves', 'Calves', 'lower'),
+    ('neck', 'Neck', 'upper'),
+    ('full-body', 'Full Body', 'other')
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO muscle_group_aliases (alias, muscle_group_id)
+SELECT v.alias, mg.id
+FROM (VALUES
+    ('pecs', 'chest'), ('pec', 'chest'), ('pectorals', 'chest'), ('pectoralis major', 'chest'),
+    ('lats', 'back'), ('lat', 'back'), ('latissimus dorsi', 'back'), ('upper back', 'back'),
+    ('middle back', 'back'), ('mid back', 'back'), ('rhomboids', 'back'),
+    ('trapezius', 'traps'), ('trap', 'traps'),
+    ('delts', 'shoulders'), ('deltoids', 'shoulders'), ('shoulder', 'shoulders'),
+    ('front delts', 'shoulders'), ('anterior deltoid', 'shoulders'), ('side delts', 'shoulders'),
+    ('lateral deltoid', 'shoulders'),
+    ('rear delt', 'rear-delts'), ('posterior deltoid', 'rear-delts'),
+    ('bicep', 'biceps'), ('biceps brachii', 'biceps'),
+    ('tricep', 'triceps'), ('triceps brachii', 'triceps'),
+    ('forearm', 'forearms'), ('grip', 'forearms'),
+    ('abdominals', 'abs'), ('ab', 'abs'), ('core', 'abs'), ('obliques', 'abs'),
+    ('erectors', 'lower-back'), ('spinal erectors', 'lower-back'),
+    ('glute', 'glutes'), ('gluteus', 'glutes'), ('gluteus maximus', 'glutes'),
+    ('quadriceps', 'quads'), ('quad', 'quads'), ('legs', 'quads'),
+    ('hamstring', 'hamstrings'), ('hams', 'hamstrings'),
+    ('adductor', 'adductors'), ('inner thigh', 'adductors'),
+    ('abductor', 'abductors'), ('outer thigh', 'abductors'),
+    ('calf', 'calves'), ('gastrocnemius', 'calves'), ('soleus', 'calves'),
+    ('full body', 'full-body'), ('total body', 'full-body')
+) AS v(alias, slug)
+JOIN muscle_groups mg ON mg.slug = v.slug
+UNION
+SELECT slug, id FROM muscle_groups
+UNION
+SELECT lower(name), id FROM muscle_groups
+ON CONFLICT (alias) DO NOTHING;
+
+INSERT INTO equipment (slug, name) VALUES
+    ('barbell', 'Barbell'),
+    ('dumbbell', 'Dumbbell'),
+    ('kettlebell', 'Kettlebell'),
+    ('machine', 'Machine'),
+    ('cable', 'Cable'),
+    ('bodyweight', 'Bodyweight'),
+    ('smith-machine', 'Smith Machine'),
+    ('ez-bar', 'EZ Bar'),
+    ('trap-bar', 'Trap Bar'),
+    ('resistance-band', 'Resistance Band'),
+    ('plate', 'Plate'),
+    ('medicine-ball', 'Medicine Ball'),
+    ('treadmill', 'Treadmill'),
+    ('rowing-machine', 'Rowing Machine'),
+    ('stationary-bike', 'Stationary Bike'),
+    ('elliptical', 'Elliptical'),
+    ('jump-rope', 'Jump Rope'),
+    ('other', 'Other')
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO equipment_aliases (alias, equipment_id)
+SELECT v.alias, e.id
+FROM (VALUES
+    ('bar', 'barbell'), ('olympic bar', 'barbell'),
+    ('db', 'dumbbell'), ('dumbbells', 'dumbbell'), ('dumbell', 'dumbbell'),
+    ('kb', 'kettlebell'), ('kettlebells', 'kettlebell'),
+    ('machines', 'machine'), ('plate loaded', 'machine'), ('selectorized', 'machine'),
+    ('cables', 'cable'), ('pulley', 'cable'), ('cable machine', 'cable'),
+    ('none', 'bodyweight'), ('no equipment', 'bodyweight'), ('body weight', 'bodyweight'),
+    ('bw', 'bodyweight'), ('calisthenics', 'bodyweight'),
+    ('smith', 'smith-machine'),
+    ('ez curl bar', 'ez-bar'), ('curl bar', 'ez-bar'),
+    ('hex bar', 'trap-bar'),
+    ('band', 'resistance-band'), ('bands', 'resistance-band'), ('resistance bands', 'resistance-band'),
+    ('weight plate', 'plate'),
+    ('med ball', 'medicine-ball'),
+    ('rower', 'rowing-machine'), ('erg', 'rowing-machine'), ('concept2', 'rowing-machine'),
+    ('bike', 'stationary-bike'), ('exercise bike', 'stationary-bike'), ('spin bike', 'stationary-bike'),
+    ('skipping rope', 'jump-rope')
+) AS v(alias, slug)
+JOIN equipment e ON e.slug = v.slug
+UNION
+SELECT slug, id FROM equipment
+UNION
+SELECT lower(name), id FROM equipment
+ON CONFLICT (alias) DO NOTHING;
+
+-- Existing free-text values with no known alias become taxonomy entries of
+-- their own rather than being discarded
+INSERT INTO muscle_groups (slug, name)
+SELECT DISTINCT trim(BOTH '-' FROM regexp_replace(lower(trim(muscle_group)), '[^a-z0-9]+', '-', 'g')), initcap(trim(muscle_group))
+FROM exercises
+WHERE trim(coalesce(muscle_group, '')) <> ''
+  AND NOT EXISTS (SELECT 1 FROM muscle_group_aliases a WHERE a.alias = lower(regexp_replace(trim(exercises.muscle_group), '\s+', ' ', 'g')))
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO muscle_group_aliases (alias, muscle_group_id)
+SELECT DISTINCT lower(regexp_replace(trim(e.muscle_group), '\s+', ' ', 'g')), mg.id
+FROM exercises e
+JOIN muscle_groups mg ON mg.slug = trim(BOTH '-' FROM regexp_replace(lower(trim(e.muscle_group)), '[^a-z0-9]+', '-', 'g'))
+WHERE trim(coalesce(e.muscle_group, '')) <> ''
+ON CONFLICT (alias) DO NOTHING;
+
+INSERT INTO equipment (slug, name)
+SELECT DISTINCT trim(BOTH '-' FROM regexp_replace(lower(trim(equipment_type)), '[^a-z0-9]+', '-', 'g')), initcap(trim(equipment_type))
+FROM exercises
+WHERE trim(equipment_type) <> ''
+  AND NOT EXISTS (SELECT 1 FROM equipment_aliases a WHERE a.alias = lower(regexp_replace(trim(exercises.equipment_type), '\s+', ' ', 'g')))
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO equipment_aliases (alias, equipment_id)
+SELECT DISTINCT lower(regexp_replace(trim(ex.equipment_type), '\s+', ' ', 'g')), e.id
+FROM exercises ex
+JOIN equipment e ON e.slug = trim(BOTH '-' FROM regexp_replace(lower(trim(ex.equipment_type)), '[^a-z0-9]+', '-', 'g'))
+WHERE trim(ex.equipment_type) <> ''
+ON CONFLICT (alias) DO NOTHING;
+
+-- Rewrite exercises to canonical slugs; blanks fall back to sensible defaults
+UPDATE exercises SET muscle_group = coalesce(
+    (SELECT mg.slug FROM muscle_group_aliases a JOIN muscle_groups mg ON mg.id = a.muscle_group_id
+     WHERE a.alias = lower(regexp_replace(trim(exercises.muscle_group), '\s+', ' ', 'g'))),
+    'full-body');
+
+UPDATE exercises SET equipment_type = coalesce(
+    (SELECT e.slug FROM equipment_aliases a JOIN equipment e ON e.id = a.equipment_id
+     WHERE a.alias = lower(regexp_replace(trim(exercises.equipment_type), '\s+', ' ', 'g'))),
+    'bodyweight');
+
+ALTER TABLE exercises ALTER COLUMN muscle_group SET NOT NULL;
+ALTER TABLE exercises ADD CONSTRAINT fk_exercises_muscle_group
+    FOREIGN KEY (muscle_group) REFERENCES muscle_groups(slug) ON UPDATE CASCADE;
+ALTER TABLE exercises ADD CONSTRAINT fk_exercises_equipment
+    FOREIGN KEY (equipment_type) REFERENCES equipment(slug) ON UPDATE CASCADE;
+
+-- exercises.muscle_group is the main primary muscle; the mapping table holds it too
+INSERT INTO exercise_muscles (exercise_id, muscle_group_id, role)
+SELECT e.id, mg.id, 'primary'
+FROM exercises e
+JOIN muscle_groups mg ON mg.slug = e.muscle_group
+ON CONFLICT DO NOTHING;
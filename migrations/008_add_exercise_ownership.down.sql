@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_exercises_owner_id;
+
+-- User exercises would otherwise leak into the catalog everyone sees, so
+-- they are removed along with the sets logged against them
+DELETE FROM workout_sets WHERE exercise_id IN (SELECT id FROM exercises WHERE visibility <> 'global');
+DELETE FROM exercises WHERE visibility <> 'global';
+
+ALTER TABLE exercises DROP CONSTRAINT IF EXISTS chk_exercises_owner;
+ALTER TABLE exercises DROP CONSTRAINT IF EXISTS chk_exercises_visibility;
+ALTER TABLE exercises DROP COLUMN IF EXISTS visibility;
+ALTER TABLE exercises DROP COLUMN IF EXISTS owner_id;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_users_role;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
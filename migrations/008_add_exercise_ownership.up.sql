@@ -0,0 +1,12 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(16) NOT NULL DEFAULT 'user';
+ALTER TABLE users ADD CONSTRAINT chk_users_role CHECK (role IN ('user', 'admin'));
+
+-- Global exercises form the shared catalog and have no owner. Private ones
+-- are visible only to their owner; shared ones to everyone, but only the
+-- owner may change them.
+ALTER TABLE exercises ADD COLUMN IF NOT EXISTS owner_id INTEGER REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE exercises ADD COLUMN IF NOT EXISTS visibility VARCHAR(16) NOT NULL DEFAULT 'global';
+ALTER TABLE exercises ADD CONSTRAINT chk_exercises_visibility CHECK (visibility IN ('global', 'private', 'shared'));
+ALTER TABLE exercises ADD CONSTRAINT chk_exercises_owner CHECK ((visibility = 'global') = (owner_id IS NULL));
+
+CREATE INDEX IF NOT EXISTS idx_exercises_owner_id ON exercises(owner_id) WHERE owner_id IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_exercises_slug;
+ALTER TABLE exercises DROP CONSTRAINT IF EXISTS chk_exercises_slug;
+ALTER TABLE exercises DROP COLUMN IF EXISTS instructions;
+ALTER TABLE exercises DROP COLUMN IF EXISTS slug;
@@ -0,0 +1,21 @@
+-- Catalog entries get a stable slug so imports can update them in place,
+-- plus step by step instructions
+ALTER TABLE exercises ADD COLUMN IF NOT EXISTS slug VARCHAR(100);
+ALTER TABLE exercises ADD COLUMN IF NOT EXISTS instructions TEXT NOT NULL DEFAULT '';
+
+-- Backfill slugs for existing global exercises whose name slugifies uniquely
+WITH slugs AS (
+    SELECT id, trim(BOTH '-' FROM regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g')) AS slug
+    FROM exercises
+    WHERE visibility = 'global'
+)
+UPDATE exercises e
+SET slug = s.slug
+FROM slugs s
+WHERE e.id = s.id
+  AND s.slug <> ''
+  AND (SELECT count(*) FROM slugs d WHERE d.slug = s.slug) = 1;
+
+-- Slugs identify global catalog entries only
+ALTER TABLE exercises ADD CONSTRAINT chk_exercises_slug CHECK (slug IS NULL OR visibility = 'global');
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exercises_slug ON exercises(slug) WHERE slug IS NOT NULL;
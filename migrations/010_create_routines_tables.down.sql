@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS workout_planned_sets;
+ALTER TABLE workouts DROP COLUMN IF EXISTS routine_id;
+DROP TABLE IF EXISTS routine_exercises;
+DROP TABLE IF EXISTS routines;
@@ -0,0 +1,53 @@
+-- Routines are reusable workout templates: an ordered list of exercises
+-- with target sets, rep range, load and rest
+CREATE TABLE IF NOT EXISTS routines (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_routines_user_name_id ON routines(user_id, name, id);
+CREATE INDEX IF NOT EXISTS idx_routines_user_created_at_id ON routines(user_id, created_at, id);
+
+CREATE TABLE IF NOT EXISTS routine_exercises (
+    id SERIAL PRIMARY KEY,
+    routine_id INTEGER NOT NULL REFERENCES routines(id) ON DELETE CASCADE,
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    target_sets INTEGER NOT NULL CHECK (target_sets > 0),
+    rep_min INTEGER NOT NULL CHECK (rep_min > 0),
+    rep_max INTEGER NOT NULL,
+    target_weight NUMERIC(7, 2) CHECK (target_weight >= 0),
+    target_rpe NUMERIC(3, 1) CHECK (target_rpe BETWEEN 1 AND 10),
+    rest_seconds INTEGER CHECK (rest_seconds >= 0),
+    notes TEXT NOT NULL DEFAULT '',
+    CHECK (rep_max >= rep_min),
+    UNIQUE (routine_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_routine_exercises_exercise_id ON routine_exercises(exercise_id);
+
+-- Workouts remember the routine they were started from
+ALTER TABLE workouts ADD COLUMN IF NOT EXISTS routine_id INTEGER REFERENCES routines(id) ON DELETE SET NULL;
+
+-- Sets planned when a workout is started from a routine, prefilled from the
+-- user's last performance of each exercise. Logged sets stay in
+-- workout_sets; the plan is only guidance.
+CREATE TABLE IF NOT EXISTS workout_planned_sets (
+    id SERIAL PRIMARY KEY,
+    workout_id INTEGER NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    set_number INTEGER NOT NULL,
+    rep_min INTEGER NOT NULL,
+    rep_max INTEGER NOT NULL,
+    reps INTEGER NOT NULL,
+    weight NUMERIC(7, 2) NOT NULL DEFAULT 0,
+    rpe NUMERIC(3, 1),
+    rest_seconds INTEGER,
+    source VARCHAR(16) NOT NULL CHECK (source IN ('history', 'template')),
+    UNIQUE (workout_id, position, set_number)
+);
@@ -0,0 +1,13 @@
+DELETE FROM workout_planned_sets WHERE source = 'program';
+ALTER TABLE workout_planned_sets DROP CONSTRAINT IF EXISTS workout_planned_sets_source_check;
+ALTER TABLE workout_planned_sets ADD CONSTRAINT workout_planned_sets_source_check
+    CHECK (source IN ('history', 'template'));
+ALTER TABLE workout_planned_sets DROP COLUMN IF EXISTS amrap;
+ALTER TABLE workout_planned_sets DROP COLUMN IF EXISTS percent_tm;
+
+DROP TABLE IF EXISTS enrollment_training_maxes;
+DROP TABLE IF EXISTS program_enrollments;
+DROP TABLE IF EXISTS program_progressions;
+DROP TABLE IF EXISTS program_prescriptions;
+DROP TABLE IF EXISTS program_days;
+DROP TABLE IF EXISTS programs;
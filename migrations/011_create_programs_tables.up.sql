@@ -0,0 +1,88 @@
+-- Programs are multi-week training blocks. Each day of a week runs one of
+-- the author's routines, optionally prescribing sets as a percentage of the
+-- trainee's training max for some of its exercises.
+CREATE TABLE IF NOT EXISTS programs (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    visibility VARCHAR(16) NOT NULL DEFAULT 'private' CHECK (visibility IN ('private', 'shared')),
+    repeats BOOLEAN NOT NULL DEFAULT FALSE,
+    rounding NUMERIC(5, 2) NOT NULL DEFAULT 2.5 CHECK (rounding > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_programs_owner_id ON programs(owner_id);
+CREATE INDEX IF NOT EXISTS idx_programs_name_id ON programs(name, id);
+CREATE INDEX IF NOT EXISTS idx_programs_created_at_id ON programs(created_at, id);
+
+-- Routines used by a program cannot be deleted while it references them
+CREATE TABLE IF NOT EXISTS program_days (
+    id SERIAL PRIMARY KEY,
+    program_id INTEGER NOT NULL REFERENCES programs(id) ON DELETE CASCADE,
+    week INTEGER NOT NULL CHECK (week > 0),
+    day INTEGER NOT NULL CHECK (day > 0),
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    routine_id INTEGER NOT NULL REFERENCES routines(id) ON DELETE RESTRICT,
+    UNIQUE (program_id, week, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_program_days_routine_id ON program_days(routine_id);
+
+CREATE TABLE IF NOT EXISTS program_prescriptions (
+    id SERIAL PRIMARY KEY,
+    program_day_id INTEGER NOT NULL REFERENCES program_days(id) ON DELETE CASCADE,
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    set_number INTEGER NOT NULL CHECK (set_number > 0),
+    percent_tm NUMERIC(5, 2) NOT NULL CHECK (percent_tm > 0),
+    reps INTEGER NOT NULL CHECK (reps > 0),
+    amrap BOOLEAN NOT NULL DEFAULT FALSE,
+    UNIQUE (program_day_id, exercise_id, set_number)
+);
+
+-- Progression rules raise a training max when a session's prescribed sets
+-- were all completed, or at every new week or cycle
+CREATE TABLE IF NOT EXISTS program_progressions (
+    id SERIAL PRIMARY KEY,
+    program_id INTEGER NOT NULL REFERENCES programs(id) ON DELETE CASCADE,
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    increment NUMERIC(6, 2) NOT NULL CHECK (increment > 0),
+    trigger VARCHAR(16) NOT NULL CHECK (trigger IN ('session', 'week', 'cycle')),
+    UNIQUE (program_id, exercise_id)
+);
+
+-- An enrollment tracks a user's position in a program. Deleting a program
+-- ends its enrollments with it.
+CREATE TABLE IF NOT EXISTS program_enrollments (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    program_id INTEGER NOT NULL REFERENCES programs(id) ON DELETE CASCADE,
+    week INTEGER NOT NULL DEFAULT 1,
+    day INTEGER NOT NULL DEFAULT 1,
+    cycle INTEGER NOT NULL DEFAULT 1,
+    status VARCHAR(16) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'completed', 'cancelled')),
+    workout_id INTEGER REFERENCES workouts(id) ON DELETE SET NULL,
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ended_at TIMESTAMP
+);
+
+-- A user follows at most one program at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_program_enrollments_active_user
+    ON program_enrollments(user_id) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_program_enrollments_program_id ON program_enrollments(program_id);
+
+CREATE TABLE IF NOT EXISTS enrollment_training_maxes (
+    enrollment_id INTEGER NOT NULL REFERENCES program_enrollments(id) ON DELETE CASCADE,
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    weight NUMERIC(7, 2) NOT NULL CHECK (weight > 0),
+    PRIMARY KEY (enrollment_id, exercise_id)
+);
+
+-- Planned sets can now come from a program prescription
+ALTER TABLE workout_planned_sets ADD COLUMN IF NOT EXISTS percent_tm NUMERIC(5, 2);
+ALTER TABLE workout_planned_sets ADD COLUMN IF NOT EXISTS amrap BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE workout_planned_sets DROP CONSTRAINT IF EXISTS workout_planned_sets_source_check;
+ALTER TABLE workout_planned_sets ADD CONSTRAINT workout_planned_sets_source_check
+    CHECK (source IN ('history', 'template', 'program'));
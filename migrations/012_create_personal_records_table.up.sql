@@ -0,0 +1,23 @@
+-- Personal records are detected as sets are logged. Each row points at the
+-- set that set it; session volume records are kept per workout and grow
+-- with the session.
+CREATE TABLE IF NOT EXISTS personal_records (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    workout_id INTEGER NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+    set_id INTEGER NOT NULL REFERENCES workout_sets(id) ON DELETE CASCADE,
+    category VARCHAR(32) NOT NULL CHECK (category IN ('heaviest_weight', 'most_reps', 'best_e1rm', 'session_volume')),
+    value NUMERIC(12, 2) NOT NULL,
+    previous NUMERIC(12, 2) NOT NULL,
+    weight NUMERIC(7, 2) NOT NULL,
+    reps INTEGER NOT NULL,
+    achieved_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_personal_records_user_achieved_at_id ON personal_records(user_id, achieved_at, id);
+CREATE INDEX IF NOT EXISTS idx_personal_records_user_exercise ON personal_records(user_id, exercise_id, category);
+CREATE INDEX IF NOT EXISTS idx_personal_records_set_id ON personal_records(set_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_personal_records_session_volume
+    ON personal_records(workout_id, exercise_id) WHERE category = 'session_volume';
@@ -0,0 +1,5 @@
+ALTER TABLE muscle_groups DROP CONSTRAINT IF EXISTS chk_muscle_groups_volume_landmarks;
+ALTER TABLE muscle_groups DROP COLUMN IF EXISTS volume_mrv;
+ALTER TABLE muscle_groups DROP COLUMN IF EXISTS volume_mav;
+ALTER TABLE muscle_groups DROP COLUMN IF EXISTS volume_mev;
+ALTER TABLE muscle_groups DROP COLUMN IF EXISTS volume_mv;
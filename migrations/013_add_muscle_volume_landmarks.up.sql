@@ -0,0 +1,31 @@
+-- Weekly hard set volume landmarks per muscle group: maintenance (MV),
+-- minimum effective (MEV), top of the maximum adaptive range (MAV) and
+-- maximum recoverable (MRV). Muscles without published figures stay NULL.
+ALTER TABLE muscle_groups ADD COLUMN IF NOT EXISTS volume_mv SMALLINT;
+ALTER TABLE muscle_groups ADD COLUMN IF NOT EXISTS volume_mev SMALLINT;
+ALTER TABLE muscle_groups ADD COLUMN IF NOT EXISTS volume_mav SMALLINT;
+ALTER TABLE muscle_groups ADD COLUMN IF NOT EXISTS volume_mrv SMALLINT;
+
+ALTER TABLE muscle_groups ADD CONSTRAINT chk_muscle_groups_volume_landmarks CHECK (
+    (volume_mv IS NULL AND volume_mev IS NULL AND volume_mav IS NULL AND volume_mrv IS NULL)
+    OR (0 <= volume_mv AND volume_mv <= volume_mev AND volume_mev <= volume_mav AND volume_mav <= volume_mrv)
+);
+
+UPDATE muscle_groups mg
+SET volume_mv = v.mv, volume_mev = v.mev, volume_mav = v.mav, volume_mrv = v.mrv
+FROM (VALUES
+    ('chest', 8, 10, 20, 22),
+    ('back', 8, 10, 22, 25),
+    ('traps', 0, 4, 16, 26),
+    ('shoulders', 0, 8, 22, 26),
+    ('rear-delts', 0, 6, 18, 26),
+    ('biceps', 5, 8, 20, 26),
+    ('triceps', 4, 6, 14, 18),
+    ('forearms', 0, 2, 12, 20),
+    ('abs', 0, 0, 20, 25),
+    ('glutes', 0, 0, 12, 16),
+    ('quads', 6, 8, 18, 20),
+    ('hamstrings', 3, 6, 16, 20),
+    ('calves', 6, 8, 16, 20)
+) AS v(slug, mv, mev, mav, mrv)
+WHERE mg.slug = v.slug;
@@ -0,0 +1,17 @@
+DELETE FROM personal_records WHERE category IN ('longest_distance', 'fastest_time');
+ALTER TABLE personal_records DROP CONSTRAINT IF EXISTS personal_records_category_check;
+ALTER TABLE personal_records ADD CONSTRAINT personal_records_category_check
+    CHECK (category IN ('heaviest_weight', 'most_reps', 'best_e1rm', 'session_volume'));
+ALTER TABLE personal_records DROP COLUMN IF EXISTS duration_seconds;
+ALTER TABLE personal_records DROP COLUMN IF EXISTS distance_meters;
+
+ALTER TABLE workout_sets DROP COLUMN IF EXISTS calories;
+ALTER TABLE workout_sets DROP COLUMN IF EXISTS avg_power_watts;
+ALTER TABLE workout_sets DROP COLUMN IF EXISTS elevation_gain_meters;
+ALTER TABLE workout_sets DROP COLUMN IF EXISTS max_heart_rate;
+ALTER TABLE workout_sets DROP COLUMN IF EXISTS avg_heart_rate;
+ALTER TABLE workout_sets DROP COLUMN IF EXISTS distance_meters;
+ALTER TABLE workout_sets DROP COLUMN IF EXISTS duration_seconds;
+
+ALTER TABLE exercises DROP CONSTRAINT IF EXISTS chk_exercises_tracking_type;
+ALTER TABLE exercises DROP COLUMN IF EXISTS tracking_type;
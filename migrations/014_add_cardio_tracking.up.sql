@@ -0,0 +1,25 @@
+-- Exercises say which measurements a logged set needs; sets gain the
+-- time, distance and effort measurements of cardio and conditioning work.
+-- Distances are meters and durations seconds.
+ALTER TABLE exercises ADD COLUMN IF NOT EXISTS tracking_type VARCHAR(32) NOT NULL DEFAULT 'weight_reps';
+ALTER TABLE exercises ADD CONSTRAINT chk_exercises_tracking_type
+    CHECK (tracking_type IN ('weight_reps', 'reps_only', 'duration', 'distance_duration'));
+
+UPDATE exercises SET tracking_type = 'distance_duration'
+WHERE equipment_type IN ('treadmill', 'rowing-machine', 'stationary-bike', 'elliptical');
+UPDATE exercises SET tracking_type = 'duration' WHERE equipment_type = 'jump-rope';
+
+ALTER TABLE workout_sets ADD COLUMN IF NOT EXISTS duration_seconds INTEGER;
+ALTER TABLE workout_sets ADD COLUMN IF NOT EXISTS distance_meters NUMERIC(10, 2);
+ALTER TABLE workout_sets ADD COLUMN IF NOT EXISTS avg_heart_rate SMALLINT;
+ALTER TABLE workout_sets ADD COLUMN IF NOT EXISTS max_heart_rate SMALLINT;
+ALTER TABLE workout_sets ADD COLUMN IF NOT EXISTS elevation_gain_meters NUMERIC(7, 1);
+ALTER TABLE workout_sets ADD COLUMN IF NOT EXISTS avg_power_watts SMALLINT;
+ALTER TABLE workout_sets ADD COLUMN IF NOT EXISTS calories INTEGER;
+
+-- Cardio records describe their set by distance and duration
+ALTER TABLE personal_records ADD COLUMN IF NOT EXISTS distance_meters NUMERIC(10, 2);
+ALTER TABLE personal_records ADD COLUMN IF NOT EXISTS duration_seconds INTEGER;
+ALTER TABLE personal_records DROP CONSTRAINT IF EXISTS personal_records_category_check;
+ALTER TABLE personal_records ADD CONSTRAINT personal_records_category_check CHECK (category IN (
+    'heaviest_weight', 'most_reps', 'best_e1rm', 'session_volume', 'longest_distance', 'fastest_time'));
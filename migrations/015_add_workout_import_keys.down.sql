@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_workouts_import;
+ALTER TABLE workouts DROP CONSTRAINT IF EXISTS chk_workouts_import;
+ALTER TABLE workouts DROP COLUMN IF EXISTS import_key;
+ALTER TABLE workouts DROP COLUMN IF EXISTS import_source;
@@ -0,0 +1,9 @@
+-- Workouts imported from another app remember where they came from so
+-- re-uploading the same export does not duplicate them
+ALTER TABLE workouts ADD COLUMN IF NOT EXISTS import_source VARCHAR(16);
+ALTER TABLE workouts ADD COLUMN IF NOT EXISTS import_key VARCHAR(64);
+ALTER TABLE workouts ADD CONSTRAINT chk_workouts_import
+    CHECK ((import_source IS NULL) = (import_key IS NULL));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workouts_import
+    ON workouts(user_id, import_source, import_key) WHERE import_key IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS activity_points;
+DROP TABLE IF EXISTS activities;
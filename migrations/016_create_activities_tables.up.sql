@@ -0,0 +1,43 @@
+-- Activities are cardio sessions uploaded from device files. Each one is
+-- logged as a workout with a single set holding its summary, and keeps its
+-- track points for map and split analysis.
+CREATE TABLE IF NOT EXISTS activities (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    workout_id INTEGER NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+    set_id INTEGER NOT NULL REFERENCES workout_sets(id) ON DELETE CASCADE,
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id),
+    format VARCHAR(8) NOT NULL CHECK (format IN ('gpx', 'tcx', 'fit')),
+    sport VARCHAR(32) NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    started_at TIMESTAMP NOT NULL,
+    duration_seconds INTEGER NOT NULL,
+    distance_meters NUMERIC(10, 2) NOT NULL,
+    elevation_gain_meters NUMERIC(7, 1),
+    avg_heart_rate SMALLINT,
+    max_heart_rate SMALLINT,
+    avg_power_watts SMALLINT,
+    calories INTEGER,
+    splits JSONB NOT NULL DEFAULT '[]',
+    point_count INTEGER NOT NULL,
+    file_hash CHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_activities_user_started_at_id ON activities(user_id, started_at, id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_activities_user_file_hash ON activities(user_id, file_hash);
+CREATE INDEX IF NOT EXISTS idx_activities_workout_id ON activities(workout_id);
+
+CREATE TABLE IF NOT EXISTS activity_points (
+    activity_id INTEGER NOT NULL REFERENCES activities(id) ON DELETE CASCADE,
+    seq INTEGER NOT NULL,
+    recorded_at TIMESTAMP NOT NULL,
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    elevation NUMERIC(7, 1),
+    distance_meters NUMERIC(10, 2),
+    heart_rate SMALLINT,
+    cadence SMALLINT,
+    power_watts SMALLINT,
+    PRIMARY KEY (activity_id, seq)
+);
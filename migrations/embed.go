@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL schema migrations so the server binary
+// can apply them without the files being present on disk. Files are named
+// NNN_description.up.sql with a matching NNN_description.down.sql.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS